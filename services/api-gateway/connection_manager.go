@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// connWrapper serializes the writes, gorilla/websocket supports a single concurrent writer
+type connWrapper struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+// ConnectionManager keeps the open WebSocket connections by user ID
+type ConnectionManager struct {
+	connections map[string]*connWrapper
+	mu          sync.RWMutex
+}
+
+func NewConnectionManager() *ConnectionManager {
+	return &ConnectionManager{
+		connections: make(map[string]*connWrapper),
+	}
+}
+
+func (cm *ConnectionManager) Add(id string, conn *websocket.Conn) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	cm.connections[id] = &connWrapper{conn: conn}
+}
+
+// Remove forgets the connection, unless the user already reconnected with a new one
+func (cm *ConnectionManager) Remove(id string, conn *websocket.Conn) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if wrapper, ok := cm.connections[id]; ok && wrapper.conn == conn {
+		delete(cm.connections, id)
+	}
+}
+
+func (cm *ConnectionManager) SendMessage(id string, message any) error {
+	cm.mu.RLock()
+	wrapper, ok := cm.connections[id]
+	cm.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no connection for user %s", id)
+	}
+
+	wrapper.mu.Lock()
+	defer wrapper.mu.Unlock()
+
+	return wrapper.conn.WriteJSON(message)
+}
@@ -2,11 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"ride-sharing/services/api-gateway/grpcclients"
 	"ride-sharing/shared/contracts"
+	driverPb "ride-sharing/shared/proto/driver"
+	pb "ride-sharing/shared/proto/trip"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func handleTripPreview(w http.ResponseWriter, r *http.Request) {
@@ -23,10 +31,23 @@ func handleTripPreview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if reqBody.PickUp == nil || reqBody.Destination == nil {
+		http.Error(w, "Pickup and destination are required", http.StatusBadRequest)
+		return
+	}
+
+	for _, waypoint := range reqBody.Waypoints {
+		if waypoint == nil {
+			http.Error(w, "Waypoints can't be empty", http.StatusBadRequest)
+			return
+		}
+	}
+
 	// TODO: This can be done better - don't create a new connection for each req
 	tripService, err := grpcclients.NewTripServiceClient()
 	if err != nil {
-		log.Fatal(err)
+		writeServiceUnavailable(w, "trip", err)
+		return
 	}
 	defer tripService.Close()
 
@@ -34,6 +55,18 @@ func handleTripPreview(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		errMsg := "Failed to preview the trip"
 		log.Printf("%s: %v", errMsg, err)
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, "No route found for the selected locations", http.StatusNotFound)
+			return
+		case codes.InvalidArgument:
+			http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+			return
+		case codes.FailedPrecondition:
+			// Out of the service areas
+			http.Error(w, status.Convert(err).Message(), http.StatusUnprocessableEntity)
+			return
+		}
 		http.Error(w, errMsg, http.StatusInternalServerError)
 		return
 	}
@@ -58,7 +91,8 @@ func handleTripStart(w http.ResponseWriter, r *http.Request) {
 	// so we create a new client for each connection
 	tripService, err := grpcclients.NewTripServiceClient()
 	if err != nil {
-		log.Fatal(err)
+		writeServiceUnavailable(w, "trip", err)
+		return
 	}
 	defer tripService.Close()
 
@@ -66,7 +100,15 @@ func handleTripStart(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		errMsg := "Failed to start the trip"
 		log.Printf("%s: %v", errMsg, err)
-		http.Error(w, errMsg, http.StatusInternalServerError)
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		case codes.FailedPrecondition:
+			// The promo code of the fare can't be used anymore, the rider previews the trip again
+			http.Error(w, status.Convert(err).Message(), http.StatusConflict)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -74,3 +116,363 @@ func handleTripStart(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusCreated, response)
 }
+
+func handleTripCancel(w http.ResponseWriter, r *http.Request) {
+	reqBody := new(cancelTripRequest)
+	if err := json.NewDecoder(r.Body).Decode(reqBody); err != nil {
+		http.Error(w, "failed to parse JSON data", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(reqBody.TripID) <= 0 || len(reqBody.UserID) <= 0 {
+		http.Error(w, "Trip ID and user ID are required", http.StatusBadRequest)
+		return
+	}
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "trip", err)
+		return
+	}
+	defer tripService.Close()
+
+	cancelled, err := tripService.Client.CancelTrip(r.Context(), reqBody.toProto())
+	if err != nil {
+		errMsg := "Failed to cancel the trip"
+		log.Printf("%s: %v", errMsg, err)
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, "Trip not found", http.StatusNotFound)
+		case codes.PermissionDenied:
+			http.Error(w, "Trip does not belong to the user", http.StatusForbidden)
+		case codes.FailedPrecondition:
+			http.Error(w, status.Convert(err).Message(), http.StatusConflict)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: cancelled, Error: nil})
+}
+
+func handleTripRate(w http.ResponseWriter, r *http.Request) {
+	reqBody := new(rateTripRequest)
+	if err := json.NewDecoder(r.Body).Decode(reqBody); err != nil {
+		http.Error(w, "failed to parse JSON data", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(reqBody.TripID) <= 0 || len(reqBody.UserID) <= 0 {
+		http.Error(w, "Trip ID and user ID are required", http.StatusBadRequest)
+		return
+	}
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "trip", err)
+		return
+	}
+	defer tripService.Close()
+
+	rated, err := tripService.Client.RateTrip(r.Context(), reqBody.toProto())
+	if err != nil {
+		errMsg := "Failed to rate the trip"
+		log.Printf("%s: %v", errMsg, err)
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, "Trip not found", http.StatusNotFound)
+		case codes.InvalidArgument:
+			http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		case codes.FailedPrecondition, codes.AlreadyExists:
+			http.Error(w, status.Convert(err).Message(), http.StatusConflict)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, contracts.APIResponse{Data: rated, Error: nil})
+}
+
+func handleTripTip(w http.ResponseWriter, r *http.Request) {
+	reqBody := new(tipTripRequest)
+	if err := json.NewDecoder(r.Body).Decode(reqBody); err != nil {
+		http.Error(w, "failed to parse JSON data", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(reqBody.TripID) <= 0 || len(reqBody.UserID) <= 0 {
+		http.Error(w, "Trip ID and user ID are required", http.StatusBadRequest)
+		return
+	}
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "trip", err)
+		return
+	}
+	defer tripService.Close()
+
+	tip, err := tripService.Client.TipTrip(r.Context(), reqBody.toProto())
+	if err != nil {
+		errMsg := "Failed to tip the driver"
+		log.Printf("%s: %v", errMsg, err)
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, "Trip not found", http.StatusNotFound)
+		case codes.PermissionDenied:
+			http.Error(w, "Trip does not belong to the user", http.StatusForbidden)
+		case codes.InvalidArgument:
+			http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		case codes.FailedPrecondition, codes.AlreadyExists:
+			http.Error(w, status.Convert(err).Message(), http.StatusConflict)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, contracts.APIResponse{Data: tip, Error: nil})
+}
+
+func handleTripSplit(w http.ResponseWriter, r *http.Request) {
+	reqBody := new(splitTripRequest)
+	if err := json.NewDecoder(r.Body).Decode(reqBody); err != nil {
+		http.Error(w, "failed to parse JSON data", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(reqBody.TripID) <= 0 || len(reqBody.UserID) <= 0 || len(reqBody.InviteeIDs) <= 0 {
+		http.Error(w, "Trip ID, user ID and invitee IDs are required", http.StatusBadRequest)
+		return
+	}
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "trip", err)
+		return
+	}
+	defer tripService.Close()
+
+	trip, err := tripService.Client.InviteToSplit(r.Context(), reqBody.toProto())
+	if err != nil {
+		errMsg := "Failed to split the fare"
+		log.Printf("%s: %v", errMsg, err)
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, "Trip not found", http.StatusNotFound)
+		case codes.PermissionDenied:
+			http.Error(w, "Trip does not belong to the user", http.StatusForbidden)
+		case codes.InvalidArgument:
+			http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		case codes.FailedPrecondition:
+			http.Error(w, status.Convert(err).Message(), http.StatusConflict)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: trip, Error: nil})
+}
+
+// handleGetTrip returns the trip to one of its riders, with what they paid net of the refunds
+func handleGetTrip(w http.ResponseWriter, r *http.Request) {
+	tripID := r.PathValue("tripID")
+	userID := r.URL.Query().Get("userID")
+	if userID == "" {
+		http.Error(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "trip", err)
+		return
+	}
+	defer tripService.Close()
+
+	trip, err := tripService.Client.GetTrip(r.Context(), &pb.GetTripReq{TripID: tripID, UserID: userID})
+	if err != nil {
+		errMsg := "Failed to get the trip"
+		log.Printf("%s: %v", errMsg, err)
+		switch status.Code(err) {
+		case codes.NotFound:
+			http.Error(w, "Trip not found", http.StatusNotFound)
+		case codes.PermissionDenied:
+			http.Error(w, "Trip does not belong to the user", http.StatusForbidden)
+		default:
+			http.Error(w, errMsg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: trip, Error: nil})
+}
+
+// handleTripReceipt returns the receipt of the completed trip to one of its riders, as an HTML document
+// with format=html
+func handleTripReceipt(w http.ResponseWriter, r *http.Request) {
+	tripID := r.PathValue("tripID")
+	query := r.URL.Query()
+	userID := query.Get("userID")
+	if userID == "" {
+		http.Error(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	req := &pb.GetTripReceiptReq{TripID: tripID, UserID: userID}
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "trip", err)
+		return
+	}
+	defer tripService.Close()
+
+	if query.Get("format") == "html" {
+		export, err := tripService.Client.ExportTripReceipt(r.Context(), req)
+		if err != nil {
+			writeReceiptError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+		w.WriteHeader(http.StatusOK)
+		w.Write(export.Html)
+		return
+	}
+
+	receipt, err := tripService.Client.GetTripReceipt(r.Context(), req)
+	if err != nil {
+		writeReceiptError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: receipt, Error: nil})
+}
+
+func writeReceiptError(w http.ResponseWriter, err error) {
+	errMsg := "Failed to get the receipt"
+	log.Printf("%s: %v", errMsg, err)
+	switch status.Code(err) {
+	case codes.NotFound:
+		http.Error(w, "Trip not found", http.StatusNotFound)
+	case codes.PermissionDenied:
+		http.Error(w, "Trip does not belong to the user", http.StatusForbidden)
+	case codes.FailedPrecondition:
+		http.Error(w, "Trip is not completed", http.StatusConflict)
+	default:
+		http.Error(w, errMsg, http.StatusInternalServerError)
+	}
+}
+
+func handleRatingSummary(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("userID")
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "trip", err)
+		return
+	}
+	defer tripService.Close()
+
+	summary, err := tripService.Client.GetRatingSummary(r.Context(), &pb.GetRatingSummaryReq{UserID: userID})
+	if err != nil {
+		log.Printf("Failed to get the rating summary of %s: %v", userID, err)
+		http.Error(w, "Failed to get the rating summary", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: summary, Error: nil})
+}
+
+// handleDriverStatement returns the daily or weekly statement of the driver, as a CSV file with format=csv
+func handleDriverStatement(w http.ResponseWriter, r *http.Request) {
+	driverID := r.PathValue("driverID")
+	query := r.URL.Query()
+
+	req := &driverPb.GetDriverStatementRequest{
+		DriverID: driverID,
+		Period:   query.Get("period"),
+	}
+	if req.Period == "" {
+		req.Period = "daily"
+	}
+	if date := query.Get("date"); date != "" {
+		parsed, err := time.Parse(time.DateOnly, date)
+		if err != nil {
+			http.Error(w, "date must be formatted as YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		req.Date = timestamppb.New(parsed)
+	}
+
+	driverService, err := grpcclients.NewDriverServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "driver", err)
+		return
+	}
+	defer driverService.Close()
+
+	if query.Get("format") == "csv" {
+		export, err := driverService.Client.ExportDriverStatement(r.Context(), req)
+		if err != nil {
+			writeStatementError(w, driverID, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+		w.WriteHeader(http.StatusOK)
+		w.Write(export.Csv)
+		return
+	}
+
+	statement, err := driverService.Client.GetDriverStatement(r.Context(), req)
+	if err != nil {
+		writeStatementError(w, driverID, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: statement, Error: nil})
+}
+
+func writeStatementError(w http.ResponseWriter, driverID string, err error) {
+	log.Printf("Failed to get the statement of %s: %v", driverID, err)
+	if status.Code(err) == codes.InvalidArgument {
+		http.Error(w, status.Convert(err).Message(), http.StatusBadRequest)
+		return
+	}
+	http.Error(w, "Failed to get the statement", http.StatusInternalServerError)
+}
+
+func handleDriverShift(w http.ResponseWriter, r *http.Request) {
+	driverID := r.PathValue("driverID")
+
+	driverService, err := grpcclients.NewDriverServiceClient()
+	if err != nil {
+		writeServiceUnavailable(w, "driver", err)
+		return
+	}
+	defer driverService.Close()
+
+	shift, err := driverService.Client.GetDriverShift(r.Context(), &driverPb.GetDriverShiftRequest{DriverID: driverID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			http.Error(w, "The driver has no shift", http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to get the shift of %s: %v", driverID, err)
+		http.Error(w, "Failed to get the shift", http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, contracts.APIResponse{Data: shift, Error: nil})
+}
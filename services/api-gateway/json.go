@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
 )
 
@@ -10,3 +11,9 @@ func writeJSON(w http.ResponseWriter, statusCode int, data any) error {
 	w.WriteHeader(statusCode)
 	return json.NewEncoder(w).Encode(data)
 }
+
+// writeServiceUnavailable answers with a 503 when the service behind the request can't be reached
+func writeServiceUnavailable(w http.ResponseWriter, service string, err error) {
+	log.Printf("Failed to connect to the %s service: %v", service, err)
+	http.Error(w, "Service unavailable, try again later", http.StatusServiceUnavailable)
+}
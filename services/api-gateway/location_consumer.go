@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// LocationConsumer fans the driver locations out to the riders looking at them
+type LocationConsumer struct {
+	rabbitMQ    *messaging.RabbitMQ
+	connManager *ConnectionManager
+	riderMap    *RiderMap
+}
+
+func NewLocationConsumer(
+	rabbitMQ *messaging.RabbitMQ,
+	connManager *ConnectionManager,
+	riderMap *RiderMap,
+) *LocationConsumer {
+	return &LocationConsumer{
+		rabbitMQ:    rabbitMQ,
+		connManager: connManager,
+		riderMap:    riderMap,
+	}
+}
+
+func (lc *LocationConsumer) Start() error {
+	return lc.rabbitMQ.ConsumeMessages(
+		messaging.NotifyDriverLocationQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.DriverLocationData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the message data: %v", err)
+			}
+
+			if payload.Driver == nil {
+				return fmt.Errorf("driver location without a driver")
+			}
+
+			updates := lc.riderMap.UpdateDriver(payload.Driver, payload.RiderID)
+			for riderID, drivers := range updates {
+				clientMsg := contracts.WSMessage[any]{
+					Type: contracts.DriverCmdLocation,
+					Data: drivers,
+				}
+
+				if err := lc.connManager.SendMessage(riderID, clientMsg); err != nil {
+					log.Printf("Failed to send the driver locations to %s: %v", riderID, err)
+				}
+			}
+
+			return nil
+		},
+	)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"ride-sharing/shared/env"
+	"ride-sharing/shared/messaging"
 )
 
 var httpAddr = env.GetString("HTTP_ADDR", ":8081")
@@ -17,12 +18,66 @@ var httpAddr = env.GetString("HTTP_ADDR", ":8081")
 func main() {
 	log.Println("Starting API Gateway")
 
+	rabbitMQURI := env.GetString(env.RabbitMQ.URI, env.RabbitMQDefaults.URI)
+	rabbitMQ, err := messaging.NewRabbitMQ(rabbitMQURI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rabbitMQ.Close()
+
+	log.Println("Successfully connected to RabbitMQ")
+
+	connManager := NewConnectionManager()
+
+	// Queues whose messages are forwarded to the riders and drivers WebSockets
+	wsQueues := []string{
+		messaging.DriverCmdTripRequestQueue,
+		messaging.NotifyDriverNoDriversFoundQueue,
+		messaging.NotifyDriverAssignQueue,
+		messaging.NotifyTripCompletedQueue,
+		messaging.NotifyPaymentStatusQueue,
+		messaging.NotifyDriverTipQueue,
+		messaging.NotifySplitQueue,
+	}
+	for _, queueName := range wsQueues {
+		if err := NewQueueConsumer(rabbitMQ, connManager, queueName).Start(); err != nil {
+			log.Fatalf("Failed to consume the %s queue: %v", queueName, err)
+		}
+	}
+
+	riderMap := NewRiderMap(
+		env.GetFloat("RIDER_MAP_RADIUS_METERS", 3000),
+		env.GetDuration("RIDER_MAP_DRIVER_TTL", time.Minute),
+	)
+	if err := NewLocationConsumer(rabbitMQ, connManager, riderMap).Start(); err != nil {
+		log.Fatalf("Failed to consume the driver locations: %v", err)
+	}
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("POST /trip/preview", handleTripPreview)
 	mux.HandleFunc("POST /trip/start", handleTripStart)
-	mux.HandleFunc("/ws/riders", handleRidersWS)
-	mux.HandleFunc("/ws/drivers", handleDriversWS)
+	mux.HandleFunc("POST /trip/cancel", handleTripCancel)
+	mux.HandleFunc("POST /trip/rate", handleTripRate)
+	mux.HandleFunc("POST /trip/tip", handleTripTip)
+	mux.HandleFunc("POST /trip/split", handleTripSplit)
+	mux.HandleFunc("GET /trip/{tripID}", handleGetTrip)
+	mux.HandleFunc("GET /trip/{tripID}/receipt", handleTripReceipt)
+	mux.HandleFunc("GET /ratings/{userID}", handleRatingSummary)
+	mux.HandleFunc("GET /drivers/{driverID}/statement", handleDriverStatement)
+	mux.HandleFunc("GET /drivers/{driverID}/shift", handleDriverShift)
+	mux.Handle("POST /webhooks/payments", NewPaymentWebhooks(
+		rabbitMQ,
+		env.GetString("PAYMENT_WEBHOOK_SECRET", ""),
+		env.GetDuration("PAYMENT_WEBHOOK_TOLERANCE", 5*time.Minute),
+		env.GetDuration("PAYMENT_WEBHOOK_DEDUP_TTL", 24*time.Hour),
+	))
+	mux.HandleFunc("/ws/riders", func(w http.ResponseWriter, r *http.Request) {
+		handleRidersWS(w, r, connManager, riderMap, rabbitMQ)
+	})
+	mux.HandleFunc("/ws/drivers", func(w http.ResponseWriter, r *http.Request) {
+		handleDriversWS(w, r, connManager, rabbitMQ)
+	})
 
 	server := &http.Server{
 		Addr:    httpAddr,
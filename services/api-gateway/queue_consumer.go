@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// QueueConsumer forwards the messages of a queue to the WebSocket of their owner
+type QueueConsumer struct {
+	rabbitMQ    *messaging.RabbitMQ
+	connManager *ConnectionManager
+	queueName   string
+}
+
+func NewQueueConsumer(
+	rabbitMQ *messaging.RabbitMQ,
+	connManager *ConnectionManager,
+	queueName string,
+) *QueueConsumer {
+	return &QueueConsumer{
+		rabbitMQ:    rabbitMQ,
+		connManager: connManager,
+		queueName:   queueName,
+	}
+}
+
+func (qc *QueueConsumer) Start() error {
+	return qc.rabbitMQ.ConsumeMessages(
+		qc.queueName,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			data, err := wsPayload(message.Data)
+			if err != nil {
+				return err
+			}
+
+			clientMsg := contracts.WSMessage[any]{
+				Type: msg.RoutingKey,
+				Data: data,
+			}
+
+			// The user may be connected to another gateway instance or gone, nothing to retry
+			if err := qc.connManager.SendMessage(message.OwnerID, clientMsg); err != nil {
+				log.Printf("Failed to forward %s to %s: %v", msg.RoutingKey, message.OwnerID, err)
+			}
+
+			return nil
+		},
+	)
+}
+
+// wsPayload unwraps the trip of the trip payloads, that's what the web client expects
+func wsPayload(data []byte) (any, error) {
+	var payload struct {
+		Trip json.RawMessage `json:"trip"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the message data: %v", err)
+	}
+
+	if payload.Trip != nil {
+		return payload.Trip, nil
+	}
+
+	return json.RawMessage(data), nil
+}
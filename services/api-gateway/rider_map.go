@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	driverpb "ride-sharing/shared/proto/driver"
+	"ride-sharing/shared/types"
+	"ride-sharing/shared/util"
+)
+
+// RiderMap keeps what the riders connected to this gateway see on their map:
+// the last location they sent and the drivers moving around it.
+type RiderMap struct {
+	radiusMeters float64
+	staleAfter   time.Duration
+
+	mu sync.Mutex
+	// viewports has every connected rider, with no location until the rider sends one
+	viewports map[string]*types.Coordinate
+	drivers   map[string]*trackedDriver
+}
+
+type trackedDriver struct {
+	driver  *driverpb.Driver
+	riderID string
+	seenAt  time.Time
+}
+
+func NewRiderMap(radiusMeters float64, staleAfter time.Duration) *RiderMap {
+	return &RiderMap{
+		radiusMeters: radiusMeters,
+		staleAfter:   staleAfter,
+		viewports:    make(map[string]*types.Coordinate),
+		drivers:      make(map[string]*trackedDriver),
+	}
+}
+
+// AddRider tracks a connected rider, who sees the driver assigned to it before sending any location
+func (m *RiderMap) AddRider(riderID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.viewports[riderID]; !ok {
+		m.viewports[riderID] = nil
+	}
+}
+
+func (m *RiderMap) SetViewport(riderID string, location *types.Coordinate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.viewports[riderID] = location
+}
+
+func (m *RiderMap) RemoveRider(riderID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.viewports, riderID)
+}
+
+// UpdateDriver records the driver location and returns the drivers every affected rider
+// should now see: the riders around the driver and the rider assigned to it, with or without a location.
+func (m *RiderMap) UpdateDriver(driver *driverpb.Driver, riderID string) map[string][]*driverpb.Driver {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.drivers[driver.GetId()] = &trackedDriver{driver: driver, riderID: riderID, seenAt: now}
+
+	// Drivers which stopped sending their location left the map
+	for id, d := range m.drivers {
+		if now.Sub(d.seenAt) > m.staleAfter {
+			delete(m.drivers, id)
+		}
+	}
+
+	updates := make(map[string][]*driverpb.Driver)
+	for id, viewport := range m.viewports {
+		if id == riderID || m.isNear(viewport, driver) {
+			updates[id] = m.visibleDrivers(id, viewport)
+		}
+	}
+
+	return updates
+}
+
+// visibleDrivers must be called with the mutex held
+func (m *RiderMap) visibleDrivers(riderID string, viewport *types.Coordinate) []*driverpb.Driver {
+	drivers := make([]*driverpb.Driver, 0)
+	for _, d := range m.drivers {
+		if d.riderID == riderID || m.isNear(viewport, d.driver) {
+			drivers = append(drivers, d.driver)
+		}
+	}
+
+	return drivers
+}
+
+func (m *RiderMap) isNear(viewport *types.Coordinate, driver *driverpb.Driver) bool {
+	location := driver.GetLocation()
+	if viewport == nil || location == nil {
+		return false
+	}
+
+	distance := util.HaversineMeters(
+		viewport.Latitude, viewport.Longitude,
+		location.GetLatitude(), location.GetLongitude(),
+	)
+
+	return distance <= m.radiusMeters
+}
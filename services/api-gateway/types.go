@@ -1,17 +1,36 @@
 package main
 
 import (
+	"fmt"
+	"time"
+
+	driverpb "ride-sharing/shared/proto/driver"
 	pb "ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/types"
+	"ride-sharing/shared/util"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type previewTripRequest struct {
 	UserID      string            `json:"userID"`
 	PickUp      *types.Coordinate `json:"pickup"`
 	Destination *types.Coordinate `json:"destination"`
+	// Optional intermediate stops, in the order they should be visited
+	Waypoints []*types.Coordinate `json:"waypoints,omitempty"`
+	// Optional promo code, discounting the fares it applies to
+	PromoCode string `json:"promoCode,omitempty"`
 }
 
 func (p *previewTripRequest) toProto() *pb.PreviewTripReq {
+	waypoints := make([]*pb.Coordinate, len(p.Waypoints))
+	for idx, waypoint := range p.Waypoints {
+		waypoints[idx] = &pb.Coordinate{
+			Latitude:  waypoint.Latitude,
+			Longitude: waypoint.Longitude,
+		}
+	}
+
 	return &pb.PreviewTripReq{
 		UserID: p.UserID,
 		StartLocation: &pb.Coordinate{
@@ -22,17 +41,163 @@ func (p *previewTripRequest) toProto() *pb.PreviewTripReq {
 			Latitude:  p.Destination.Latitude,
 			Longitude: p.Destination.Longitude,
 		},
+		Waypoints: waypoints,
+		PromoCode: p.PromoCode,
 	}
 }
 
 type startTripRequest struct {
 	RideFareID string `json:"rideFareID"`
 	UserID     string `json:"userID"`
+	// Optional pickup time (RFC 3339) to book the ride ahead
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
 }
 
 func (c *startTripRequest) toProto() *pb.CreateTripReq {
-	return &pb.CreateTripReq{
+	req := &pb.CreateTripReq{
 		RideFareID: c.RideFareID,
 		UserID:     c.UserID,
 	}
+
+	if c.ScheduledAt != nil {
+		req.ScheduledAt = timestamppb.New(*c.ScheduledAt)
+	}
+
+	return req
+}
+
+type cancelTripRequest struct {
+	TripID string `json:"tripID"`
+	UserID string `json:"userID"`
+}
+
+func (c *cancelTripRequest) toProto() *pb.CancelTripReq {
+	return &pb.CancelTripReq{
+		TripID: c.TripID,
+		UserID: c.UserID,
+	}
+}
+
+type stopReachedRequest struct {
+	TripID    string `json:"tripID"`
+	StopIndex int32  `json:"stopIndex"`
+}
+
+func (s *stopReachedRequest) toProto(driverID string) *pb.ReachTripStopReq {
+	return &pb.ReachTripStopReq{
+		TripID:    s.TripID,
+		DriverID:  driverID,
+		StopIndex: s.StopIndex,
+	}
+}
+
+type tripCompleteRequest struct {
+	TripID string `json:"tripID"`
+	// What the driver app measured of the route driven, the quoted route is priced for the values omitted
+	Distance     float64 `json:"distance,omitempty"`
+	Duration     float64 `json:"duration,omitempty"`
+	WaitMinutes  float64 `json:"waitMinutes,omitempty"`
+	TollsInCents float64 `json:"tollsInCents,omitempty"`
+}
+
+func (t *tripCompleteRequest) toProto(driverID string) *pb.CompleteTripReq {
+	return &pb.CompleteTripReq{
+		TripID:       t.TripID,
+		DriverID:     driverID,
+		Distance:     t.Distance,
+		Duration:     t.Duration,
+		WaitMinutes:  t.WaitMinutes,
+		TollsInCents: t.TollsInCents,
+	}
+}
+
+type rateTripRequest struct {
+	TripID string `json:"tripID"`
+	UserID string `json:"userID"`
+	// RateeID is the rider a driver rates on a pool trip
+	RateeID string   `json:"rateeID,omitempty"`
+	Score   int32    `json:"score"`
+	Tags    []string `json:"tags,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+func (r *rateTripRequest) toProto() *pb.RateTripReq {
+	return &pb.RateTripReq{
+		TripID:  r.TripID,
+		UserID:  r.UserID,
+		RateeID: r.RateeID,
+		Score:   r.Score,
+		Tags:    r.Tags,
+		Comment: r.Comment,
+	}
+}
+
+// tipTripRequest tips the driver of a completed trip, either a fixed amount or a percentage of the fare
+type tipTripRequest struct {
+	TripID        string  `json:"tripID"`
+	UserID        string  `json:"userID"`
+	AmountInCents int64   `json:"amountInCents,omitempty"`
+	Percent       float64 `json:"percent,omitempty"`
+}
+
+func (t *tipTripRequest) toProto() *pb.TipTripReq {
+	return &pb.TipTripReq{
+		TripID:        t.TripID,
+		UserID:        t.UserID,
+		AmountInCents: t.AmountInCents,
+		Percent:       t.Percent,
+	}
+}
+
+// splitTripRequest invites other riders to split the fare of the trip with its owner
+type splitTripRequest struct {
+	TripID     string   `json:"tripID"`
+	UserID     string   `json:"userID"`
+	InviteeIDs []string `json:"inviteeIDs"`
+}
+
+func (s *splitTripRequest) toProto() *pb.InviteToSplitReq {
+	return &pb.InviteToSplitReq{
+		TripID:     s.TripID,
+		UserID:     s.UserID,
+		InviteeIDs: s.InviteeIDs,
+	}
+}
+
+// locationRequest is the location the riders and drivers send over their WebSocket
+type locationRequest struct {
+	Location *types.Coordinate `json:"location"`
+}
+
+func (l *locationRequest) validate() error {
+	if l.Location == nil {
+		return fmt.Errorf("location is required")
+	}
+
+	if !util.IsValidCoordinate(l.Location.Latitude, l.Location.Longitude) {
+		return fmt.Errorf("invalid location: %+v", *l.Location)
+	}
+
+	return nil
+}
+
+func (l *locationRequest) toProto(driverID string) *driverpb.UpdateDriverLocationRequest {
+	return &driverpb.UpdateDriverLocationRequest{
+		DriverID: driverID,
+		Location: &driverpb.Location{
+			Latitude:  l.Location.Latitude,
+			Longitude: l.Location.Longitude,
+		},
+	}
+}
+
+type driverStatusRequest struct {
+	Status string `json:"status"`
+}
+
+func (d *driverStatusRequest) toProto(driverID string) *driverpb.SetDriverStatusRequest {
+	return &driverpb.SetDriverStatusRequest{
+		DriverID: driverID,
+		Status:   d.Status,
+	}
 }
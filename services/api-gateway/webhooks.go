@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+)
+
+// Webhook events are small, anything bigger isn't from the provider
+const maxWebhookBytes = 64 << 10
+
+var (
+	errInvalidSignature = errors.New("invalid webhook signature")
+	errStaleWebhook     = errors.New("webhook timestamp out of tolerance")
+)
+
+// paymentWebhookEvent is the part of the provider events we use, in the Stripe format
+type paymentWebhookEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID                string `json:"id"`
+			ClientReferenceID string `json:"client_reference_id"`
+			AmountTotal       int64  `json:"amount_total"`
+			Currency          string `json:"currency"`
+			PaymentStatus     string `json:"payment_status"`
+			// PaymentMethodTypes are the methods the checkout offered
+			PaymentMethodTypes []string `json:"payment_method_types"`
+			Metadata           struct {
+				TripID   string `json:"trip_id"`
+				UserID   string `json:"user_id"`
+				DriverID string `json:"driver_id"`
+				// PayerID is set when someone else pays for the rider, the owner covering a split fare share
+				PayerID string `json:"payer_id"`
+				// Kind is fare, tip or balance, the tips go to the driver in full
+				Kind string `json:"kind"`
+				// Capture is manual for the sessions which only place a hold, captured when the trip completes
+				Capture string `json:"capture"`
+			} `json:"metadata"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// routingKey maps the provider event to the payment event it stands for, if any
+func (e *paymentWebhookEvent) routingKey() (string, bool) {
+	switch e.Type {
+	case "checkout.session.completed":
+		if e.Data.Object.Metadata.Capture == "manual" {
+			return contracts.PaymentEventAuthorized, true
+		}
+		// Delayed payment methods complete the session unpaid, the async events tell how it went
+		if e.Data.Object.PaymentStatus == "unpaid" {
+			return "", false
+		}
+		return contracts.PaymentEventSuccess, true
+	case "checkout.session.async_payment_succeeded":
+		return contracts.PaymentEventSuccess, true
+	case "checkout.session.async_payment_failed":
+		return contracts.PaymentEventFailed, true
+	case "checkout.session.expired":
+		return contracts.PaymentEventCancelled, true
+	}
+
+	return "", false
+}
+
+// PaymentWebhooks turns the payment provider webhooks into payment.event.* messages
+type PaymentWebhooks struct {
+	rabbitMQ  *messaging.RabbitMQ
+	secret    []byte
+	tolerance time.Duration
+	seen      *eventDeduper
+}
+
+func NewPaymentWebhooks(
+	rabbitMQ *messaging.RabbitMQ,
+	secret string,
+	tolerance time.Duration,
+	dedupTTL time.Duration,
+) *PaymentWebhooks {
+	return &PaymentWebhooks{
+		rabbitMQ:  rabbitMQ,
+		secret:    []byte(secret),
+		tolerance: tolerance,
+		seen:      newEventDeduper(dedupTTL),
+	}
+}
+
+// ServeHTTP only publishes the event, the provider retries the webhooks which aren't answered quickly
+func (wh *PaymentWebhooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if len(wh.secret) == 0 {
+		http.Error(w, "Payment webhooks are not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		http.Error(w, "Failed to read the webhook", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := wh.verify(r.Header.Get("Stripe-Signature"), body, time.Now()); err != nil {
+		log.Printf("Rejected a payment webhook: %v", err)
+		http.Error(w, "Invalid signature", http.StatusBadRequest)
+		return
+	}
+
+	event := new(paymentWebhookEvent)
+	if err := json.Unmarshal(body, event); err != nil || event.ID == "" {
+		http.Error(w, "Failed to parse the webhook", http.StatusBadRequest)
+		return
+	}
+
+	routingKey, ok := event.routingKey()
+	if !ok {
+		// Acknowledged so the provider doesn't retry the events we don't handle
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if !wh.seen.reserve(event.ID) {
+		log.Printf("Ignoring the duplicate payment webhook %s", event.ID)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := wh.publish(r.Context(), routingKey, event); err != nil {
+		// Let the provider retry it
+		wh.seen.release(event.ID)
+		log.Printf("Failed to publish the payment webhook %s: %v", event.ID, err)
+		http.Error(w, "Failed to process the webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// verify checks the "t=<unix time>,v1=<hex HMAC-SHA256 of t.body>" signature header
+func (wh *PaymentWebhooks) verify(header string, body []byte, now time.Time) error {
+	var (
+		timestamp  string
+		signatures []string
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing timestamp or signature", errInvalidSignature)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp %q", errInvalidSignature, timestamp)
+	}
+
+	mac := hmac.New(sha256.New, wh.secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	matched := false
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(decoded, expected) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return errInvalidSignature
+	}
+
+	// Checked once the signature is known to be genuine, replayed webhooks are refused
+	if age := now.Sub(time.Unix(unix, 0)); age > wh.tolerance || age < -wh.tolerance {
+		return fmt.Errorf("%w: signed %s ago", errStaleWebhook, age.Round(time.Second))
+	}
+
+	return nil
+}
+
+func (wh *PaymentWebhooks) publish(ctx context.Context, routingKey string, event *paymentWebhookEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	session := event.Data.Object
+
+	tripID := session.Metadata.TripID
+	if tripID == "" {
+		tripID = session.ClientReferenceID
+	}
+	if tripID == "" || session.Metadata.UserID == "" {
+		return fmt.Errorf("session %s has no trip or rider", session.ID)
+	}
+
+	payload := messaging.PaymentEventData{
+		TripID:        tripID,
+		UserID:        session.Metadata.UserID,
+		DriverID:      session.Metadata.DriverID,
+		PayerID:       session.Metadata.PayerID,
+		SessionID:     session.ID,
+		Kind:          session.Metadata.Kind,
+		AmountInCents: session.AmountTotal,
+		Currency:      session.Currency,
+	}
+	if payload.Kind == messaging.PaymentKindTip {
+		payload.TipInCents = session.AmountTotal
+	}
+	// The checkout doesn't tell which method was used, unless it offered only one
+	if len(session.PaymentMethodTypes) == 1 {
+		payload.Method = session.PaymentMethodTypes[0]
+	}
+	// Nothing is charged yet, the session holds its total
+	if routingKey == contracts.PaymentEventAuthorized {
+		payload.AmountInCents = 0
+		payload.HoldInCents = session.AmountTotal
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the payment event: %w", err)
+	}
+
+	ownerID := session.Metadata.PayerID
+	if ownerID == "" {
+		ownerID = session.Metadata.UserID
+	}
+
+	return wh.rabbitMQ.Publish(ctx, routingKey, contracts.AmqpMessage{
+		OwnerID: ownerID,
+		Data:    data,
+	})
+}
+
+// eventDeduper remembers the event IDs for the TTL, providers deliver the webhooks at least once
+type eventDeduper struct {
+	ttl time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+	// prunedAt is when the expired events were last forgotten
+	prunedAt time.Time
+}
+
+func newEventDeduper(ttl time.Duration) *eventDeduper {
+	return &eventDeduper{
+		ttl:  ttl,
+		seen: make(map[string]time.Time),
+	}
+}
+
+// reserve tells whether the event is new, and marks it as seen
+func (d *eventDeduper) reserve(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if seenAt, ok := d.seen[id]; ok && now.Sub(seenAt) < d.ttl {
+		return false
+	}
+
+	// Forgets the expired events once per TTL, the map holds at most two TTLs of events
+	if now.Sub(d.prunedAt) >= d.ttl {
+		for seenID, seenAt := range d.seen {
+			if now.Sub(seenAt) >= d.ttl {
+				delete(d.seen, seenID)
+			}
+		}
+		d.prunedAt = now
+	}
+
+	d.seen[id] = now
+	return true
+}
+
+// release forgets the event, so that its redelivery is processed
+func (d *eventDeduper) release(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.seen, id)
+}
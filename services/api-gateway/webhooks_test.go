@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testWebhookSecret = "whsec_test"
+
+func sign(secret string, body string, at time.Time) string {
+	timestamp := fmt.Sprintf("%d", at.Unix())
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newTestWebhooks(secret string) *PaymentWebhooks {
+	return NewPaymentWebhooks(nil, secret, 5*time.Minute, time.Hour)
+}
+
+func TestPaymentWebhooksVerify(t *testing.T) {
+	const body = `{"id":"evt_1","type":"checkout.session.completed"}`
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{
+			name:   "valid",
+			header: sign(testWebhookSecret, body, now),
+		},
+		{
+			name:   "one of the signatures matches",
+			header: sign(testWebhookSecret, body, now) + ",v1=" + strings.Repeat("00", 32),
+		},
+		{
+			name:    "signed with another secret",
+			header:  sign("whsec_other", body, now),
+			wantErr: errInvalidSignature,
+		},
+		{
+			name:    "signature of another body",
+			header:  sign(testWebhookSecret, `{"id":"evt_2"}`, now),
+			wantErr: errInvalidSignature,
+		},
+		{
+			name:    "missing header",
+			wantErr: errInvalidSignature,
+		},
+		{
+			name:    "not hex",
+			header:  fmt.Sprintf("t=%d,v1=zz", now.Unix()),
+			wantErr: errInvalidSignature,
+		},
+		{
+			name:    "replayed after the tolerance",
+			header:  sign(testWebhookSecret, body, now.Add(-10*time.Minute)),
+			wantErr: errStaleWebhook,
+		},
+		{
+			name:    "signed in the future",
+			header:  sign(testWebhookSecret, body, now.Add(10*time.Minute)),
+			wantErr: errStaleWebhook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTestWebhooks(testWebhookSecret).verify(tt.header, []byte(body), now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPaymentWebhooksServeHTTP(t *testing.T) {
+	const (
+		completed = `{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"payment_status":"paid"}}}`
+		ignored   = `{"id":"evt_2","type":"customer.created"}`
+	)
+
+	tests := []struct {
+		name   string
+		secret string
+		body   string
+		header func(body string) string
+		// seen are the events already processed
+		seen       []string
+		wantStatus int
+		wantSeen   bool
+	}{
+		{
+			name:       "not configured",
+			body:       completed,
+			header:     func(body string) string { return sign(testWebhookSecret, body, time.Now()) },
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "bad signature",
+			secret:     testWebhookSecret,
+			body:       completed,
+			header:     func(body string) string { return sign("whsec_other", body, time.Now()) },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "stale signature",
+			secret:     testWebhookSecret,
+			body:       completed,
+			header:     func(body string) string { return sign(testWebhookSecret, body, time.Now().Add(-time.Hour)) },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "event not handled",
+			secret:     testWebhookSecret,
+			body:       ignored,
+			header:     func(body string) string { return sign(testWebhookSecret, body, time.Now()) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "redelivered event",
+			secret:     testWebhookSecret,
+			body:       completed,
+			header:     func(body string) string { return sign(testWebhookSecret, body, time.Now()) },
+			seen:       []string{"evt_1"},
+			wantStatus: http.StatusOK,
+			wantSeen:   true,
+		},
+		{
+			// The session has no trip, so the event can't be published and is released for the retry
+			name:       "publish failed",
+			secret:     testWebhookSecret,
+			body:       completed,
+			header:     func(body string) string { return sign(testWebhookSecret, body, time.Now()) },
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wh := newTestWebhooks(tt.secret)
+			for _, id := range tt.seen {
+				wh.seen.reserve(id)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/payment", strings.NewReader(tt.body))
+			req.Header.Set("Stripe-Signature", tt.header(tt.body))
+			rec := httptest.NewRecorder()
+
+			wh.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if _, seen := wh.seen.seen["evt_1"]; seen != tt.wantSeen {
+				t.Errorf("evt_1 seen: %v, want %v", seen, tt.wantSeen)
+			}
+		})
+	}
+}
+
+func TestEventDeduper(t *testing.T) {
+	d := newEventDeduper(time.Minute)
+
+	if !d.reserve("evt_1") {
+		t.Fatal("reserve() of a new event = false")
+	}
+	if d.reserve("evt_1") {
+		t.Error("reserve() of a seen event = true")
+	}
+
+	// The publish failed, the redelivery has to be processed
+	d.release("evt_1")
+	if !d.reserve("evt_1") {
+		t.Error("reserve() of a released event = false")
+	}
+
+	// Seen longer than the TTL ago
+	d.seen["evt_1"] = time.Now().Add(-2 * time.Minute)
+	if !d.reserve("evt_1") {
+		t.Error("reserve() of an expired event = false")
+	}
+}
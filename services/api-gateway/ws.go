@@ -1,24 +1,39 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"log"
 	"net/http"
+	"time"
 
 	"ride-sharing/services/api-gateway/grpcclients"
 	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/messaging"
 	"ride-sharing/shared/proto/driver"
+	"ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/util"
 
 	"github.com/gorilla/websocket"
 )
 
+// driverHeartbeatInterval has to be shorter than the heartbeat timeout of the driver service
+var driverHeartbeatInterval = env.GetDuration("DRIVER_HEARTBEAT_INTERVAL", 10*time.Second)
+
 var upgrader = websocket.Upgrader{
 	CheckOrigin: func(r *http.Request) bool {
 		return true
 	},
 }
 
-func handleRidersWS(w http.ResponseWriter, r *http.Request) {
+func handleRidersWS(
+	w http.ResponseWriter,
+	r *http.Request,
+	connManager *ConnectionManager,
+	riderMap *RiderMap,
+	rabbitMQ *messaging.RabbitMQ,
+) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WS upgrade failed: %v\n", err)
@@ -33,6 +48,11 @@ func handleRidersWS(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	connManager.Add(userID, conn)
+	defer connManager.Remove(userID, conn)
+	riderMap.AddRider(userID)
+	defer riderMap.RemoveRider(userID)
+
 	for {
 		_, msg, err := conn.ReadMessage()
 		if err != nil {
@@ -41,10 +61,48 @@ func handleRidersWS(w http.ResponseWriter, r *http.Request) {
 		}
 
 		log.Printf("Received message: %s", msg)
+
+		riderMsg := new(contracts.WSDriverMessage)
+		if err := json.Unmarshal(msg, riderMsg); err != nil {
+			log.Printf("Error parsing rider message: %v\n", err)
+			continue
+		}
+
+		switch riderMsg.Type {
+		case contracts.DriverCmdLocation:
+			// Riders send their own location, the drivers around it are shown on their map
+			req := new(locationRequest)
+			if err := json.Unmarshal(riderMsg.Data, req); err != nil {
+				log.Printf("Error parsing %s data: %v\n", riderMsg.Type, err)
+				continue
+			}
+			if err := req.validate(); err != nil {
+				log.Printf("Ignoring the location of rider %s: %v", userID, err)
+				continue
+			}
+
+			riderMap.SetViewport(userID, req.Location)
+		case contracts.RiderCmdSplitAccept, contracts.RiderCmdSplitDecline:
+			// The invitee is identified by the connection, not by what the client sends
+			err := rabbitMQ.Publish(r.Context(), riderMsg.Type, contracts.AmqpMessage{
+				OwnerID: userID,
+				Data:    riderMsg.Data,
+			})
+			if err != nil {
+				log.Printf("Failed to publish %s of rider %s: %v", riderMsg.Type, userID, err)
+			}
+		default:
+			log.Printf("Unknown rider message type: %s", riderMsg.Type)
+		}
 	}
 }
 
-func handleDriversWS(w http.ResponseWriter, r *http.Request) {
+func handleDriversWS(
+	w http.ResponseWriter,
+	r *http.Request,
+	connManager *ConnectionManager,
+	rabbitMQ *messaging.RabbitMQ,
+) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WS upgrade failed: %v\n", err)
@@ -67,19 +125,29 @@ func handleDriversWS(w http.ResponseWriter, r *http.Request) {
 
 	ctx := r.Context()
 
+	connManager.Add(userID, conn)
+	defer connManager.Remove(userID, conn)
+
+	// The clients are shared by every message of the connection
 	driverService, err := grpcclients.NewDriverServiceClient()
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("Error connecting to the driver service: %v\n", err)
+		return
+	}
+
+	tripService, err := grpcclients.NewTripServiceClient()
+	if err != nil {
+		log.Printf("Error connecting to the trip service: %v\n", err)
+		driverService.Close()
+		return
 	}
+	defer tripService.Close()
 
 	// Closing connections
 	defer func() {
 		_, err := driverService.Client.UnregisterDriver(
 			ctx,
-			&driver.RegisterDriverRequest{
-				DriverID:    userID,
-				PackageSlug: pkgSlug,
-			},
+			&driver.UnregisterDriverRequest{DriverID: userID},
 		)
 		if err != nil {
 			// TODO: Handle this better
@@ -108,11 +176,16 @@ func handleDriversWS(w http.ResponseWriter, r *http.Request) {
 		Data: driverData.Driver,
 	}
 
-	if err := conn.WriteJSON(msg); err != nil {
+	if err := connManager.SendMessage(userID, msg); err != nil {
 		log.Printf("Error sending message: %v\n", err)
 		return
 	}
 
+	// Keeps the driver online in the driver service while the connection is open
+	heartbeatCtx, stopHeartbeats := context.WithCancel(ctx)
+	defer stopHeartbeats()
+	go sendHeartbeats(heartbeatCtx, driverService.Client, connManager, userID, driverData.Driver.Status)
+
 	for {
 		_, msg, err := conn.ReadMessage()
 		if err != nil {
@@ -121,5 +194,119 @@ func handleDriversWS(w http.ResponseWriter, r *http.Request) {
 		}
 
 		log.Printf("Received message: %s", msg)
+
+		driverMsg := new(contracts.WSDriverMessage)
+		if err := json.Unmarshal(msg, driverMsg); err != nil {
+			log.Printf("Error parsing driver message: %v\n", err)
+			continue
+		}
+
+		handleDriverMessage(ctx, userID, driverMsg, driverService.Client, tripService.Client, rabbitMQ)
+	}
+}
+
+func handleDriverMessage(
+	ctx context.Context,
+	driverID string,
+	msg *contracts.WSDriverMessage,
+	driverClient driver.DriverServiceClient,
+	tripClient trip.TripServiceClient,
+	rabbitMQ *messaging.RabbitMQ,
+) {
+	switch msg.Type {
+	case contracts.DriverCmdLocation:
+		req := new(locationRequest)
+		if err := json.Unmarshal(msg.Data, req); err != nil {
+			log.Printf("Error parsing %s data: %v\n", msg.Type, err)
+			return
+		}
+		if err := req.validate(); err != nil {
+			log.Printf("Ignoring the location of driver %s: %v", driverID, err)
+			return
+		}
+
+		if _, err := driverClient.UpdateDriverLocation(ctx, req.toProto(driverID)); err != nil {
+			log.Printf("Failed to update the location of driver %s: %v", driverID, err)
+		}
+	case contracts.DriverCmdTripComplete:
+		req := new(tripCompleteRequest)
+		if err := json.Unmarshal(msg.Data, req); err != nil {
+			log.Printf("Error parsing %s data: %v\n", msg.Type, err)
+			return
+		}
+
+		if _, err := tripClient.CompleteTrip(ctx, req.toProto(driverID)); err != nil {
+			log.Printf("Failed to complete trip %s: %v", req.TripID, err)
+		}
+	case contracts.DriverCmdStatus:
+		req := new(driverStatusRequest)
+		if err := json.Unmarshal(msg.Data, req); err != nil {
+			log.Printf("Error parsing %s data: %v\n", msg.Type, err)
+			return
+		}
+
+		if _, err := driverClient.SetDriverStatus(ctx, req.toProto(driverID)); err != nil {
+			log.Printf("Failed to set the status of driver %s to %s: %v", driverID, req.Status, err)
+		}
+	case contracts.DriverCmdTripAccept, contracts.DriverCmdTripDecline:
+		// The driver is identified by the connection, not by what the client sends
+		err := rabbitMQ.Publish(ctx, msg.Type, contracts.AmqpMessage{
+			OwnerID: driverID,
+			Data:    msg.Data,
+		})
+		if err != nil {
+			log.Printf("Failed to publish %s of driver %s: %v", msg.Type, driverID, err)
+		}
+	case contracts.DriverCmdStopReached:
+		req := new(stopReachedRequest)
+		if err := json.Unmarshal(msg.Data, req); err != nil {
+			log.Printf("Error parsing %s data: %v\n", msg.Type, err)
+			return
+		}
+
+		if _, err := tripClient.ReachTripStop(ctx, req.toProto(driverID)); err != nil {
+			log.Printf("Failed to mark stop %d of trip %s as reached: %v", req.StopIndex, req.TripID, err)
+		}
+	default:
+		log.Printf("Unknown driver message type: %s", msg.Type)
+	}
+}
+
+// sendHeartbeats keeps the driver online, and tells the driver when the driver service changed its status,
+// ex. when it's sent on a required break, along with its shift
+func sendHeartbeats(
+	ctx context.Context,
+	client driver.DriverServiceClient,
+	connManager *ConnectionManager,
+	driverID string,
+	lastStatus string,
+) {
+	ticker := time.NewTicker(driverHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			res, err := client.Heartbeat(ctx, &driver.HeartbeatRequest{DriverID: driverID})
+			if err != nil {
+				log.Printf("Failed to send the heartbeat of driver %s: %v", driverID, err)
+				continue
+			}
+
+			if res.Status == lastStatus {
+				continue
+			}
+			lastStatus = res.Status
+
+			msg := contracts.WSMessage[*driver.ShiftSummary]{
+				Type: contracts.DriverCmdStatus,
+				Data: res.Shift,
+			}
+			if err := connManager.SendMessage(driverID, msg); err != nil {
+				log.Printf("Failed to send the status of driver %s: %v", driverID, err)
+			}
+		}
 	}
 }
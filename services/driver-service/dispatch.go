@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	pb "ride-sharing/shared/proto/trip"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// dispatch is the offer workflow of a single trip
+type dispatch struct {
+	trip *pb.Trip
+	// offeredTo is the driver currently holding the offer
+	offeredTo string
+	// excluded drivers declined the trip or let the offer time out
+	excluded map[string]bool
+	timer    *time.Timer
+}
+
+// Dispatcher offers trips to one driver at a time. When the driver declines or doesn't
+// answer within the timeout, the trip is offered to the next best candidate.
+type Dispatcher struct {
+	rabbitMQ     *messaging.RabbitMQ
+	service      *Service
+	cfg          matchingConfig
+	offerTimeout time.Duration
+
+	mu         sync.Mutex
+	dispatches map[string]*dispatch
+}
+
+func NewDispatcher(
+	rabbitMQ *messaging.RabbitMQ,
+	service *Service,
+	cfg matchingConfig,
+	offerTimeout time.Duration,
+) *Dispatcher {
+	return &Dispatcher{
+		rabbitMQ:     rabbitMQ,
+		service:      service,
+		cfg:          cfg,
+		offerTimeout: offerTimeout,
+		dispatches:   make(map[string]*dispatch),
+	}
+}
+
+// Start looks for a driver for the trip
+func (d *Dispatcher) Start(ctx context.Context, trip *pb.Trip) error {
+	if trip.GetPickup() == nil {
+		return fmt.Errorf("trip %s has no pickup location", trip.GetId())
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Redelivered event, the trip is already being offered
+	if _, ok := d.dispatches[trip.GetId()]; ok {
+		return nil
+	}
+
+	dsp := &dispatch{
+		trip:     trip,
+		excluded: make(map[string]bool),
+	}
+	d.dispatches[trip.GetId()] = dsp
+
+	return d.offerNext(ctx, dsp)
+}
+
+// Cancel stops looking for a driver, ex. when the rider cancelled the trip.
+// Only trips waiting for a driver can be cancelled: a driver who accepted the trip meanwhile
+// is rejected by the trip service and released by Reject.
+func (d *Dispatcher) Cancel(tripID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if dsp, ok := d.dispatches[tripID]; ok {
+		if dsp.offeredTo != "" {
+			d.service.TransitionDriverFrom(dsp.offeredTo, DriverStatusOffered, DriverStatusAvailable)
+		}
+		d.stop(dsp)
+	}
+}
+
+// Reject makes the driver available again when the trip service refused to assign the trip it accepted
+func (d *Dispatcher) Reject(tripID, driverID string) {
+	if d.service.ReleaseTrip(driverID, tripID) {
+		log.Printf("Driver %s released, trip %s couldn't be assigned", driverID, tripID)
+	}
+}
+
+// Accept assigns the trip to the driver, if the driver is the one currently holding the offer.
+// Any later or concurrent answer finds no offer, so the trip is assigned exactly once.
+func (d *Dispatcher) Accept(ctx context.Context, tripID, driverID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dsp, ok := d.dispatches[tripID]
+	if !ok || dsp.offeredTo != driverID {
+		log.Printf("Driver %s accepted trip %s without holding the offer, ignoring", driverID, tripID)
+		return nil
+	}
+
+	d.stop(dsp)
+
+	driver, ok := d.service.GetDriver(driverID)
+	if !ok || !d.service.TransitionDriverFrom(driverID, DriverStatusOffered, DriverStatusEnRoute) {
+		// The driver went offline while answering
+		log.Printf("Driver %s accepted trip %s but is no longer available", driverID, tripID)
+		return d.restart(ctx, dsp)
+	}
+
+	// The dispatch keeps the unassigned trip in case the assignment can't be sent
+	trip := proto.Clone(dsp.trip).(*pb.Trip)
+	trip.Status = "assigned"
+	trip.Driver = &pb.TripDriver{
+		Id:             driver.Id,
+		Name:           driver.Name,
+		ProfilePicture: driver.ProfilePicture,
+		CarPlate:       driver.CarPlate,
+		SeatCapacity:   driver.SeatCapacity,
+		CarMake:        driver.GetVehicle().GetMake(),
+		CarModel:       driver.GetVehicle().GetModel(),
+		CarColor:       driver.GetVehicle().GetColor(),
+	}
+	d.service.AssignTrip(driverID, trip)
+
+	log.Printf("Trip %s accepted by driver %s", tripID, driverID)
+
+	// The trip service confirms the assignment with trip.event.driver_assigned, or rejects it
+	if err := d.publish(ctx, contracts.DriverCmdTripAssign, driverID, messaging.TripEventData{Trip: trip}); err != nil {
+		// The trip service never hears of the assignment, so the driver is freed and the trip offered again
+		d.service.ReleaseTrip(driverID, tripID)
+		if restartErr := d.restart(ctx, dsp); restartErr != nil {
+			log.Printf("Failed to offer trip %s again: %v", tripID, restartErr)
+		}
+
+		return fmt.Errorf("failed to publish the assignment of trip %s: %w", tripID, err)
+	}
+
+	return nil
+}
+
+// Decline offers the trip to the next candidate
+func (d *Dispatcher) Decline(ctx context.Context, tripID, driverID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.moveOn(ctx, tripID, driverID)
+}
+
+// IsOffered reports whether the driver is holding the offer of any trip
+func (d *Dispatcher) IsOffered(driverID string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.isOffered(driverID)
+}
+
+// OfferedTrip returns the ID of the trip the driver is holding the offer of, if any
+func (d *Dispatcher) OfferedTrip(driverID string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for tripID, dsp := range d.dispatches {
+		if dsp.offeredTo == driverID {
+			return tripID, true
+		}
+	}
+
+	return "", false
+}
+
+// isOffered must be called with the mutex held
+func (d *Dispatcher) isOffered(driverID string) bool {
+	for _, dsp := range d.dispatches {
+		if dsp.offeredTo == driverID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d *Dispatcher) timeout(tripID, driverID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if dsp, ok := d.dispatches[tripID]; ok && dsp.offeredTo == driverID {
+		log.Printf("Driver %s didn't answer the offer of trip %s in time", driverID, tripID)
+	}
+
+	if err := d.moveOn(ctx, tripID, driverID); err != nil {
+		log.Printf("Failed to offer trip %s to the next driver: %v", tripID, err)
+	}
+}
+
+// moveOn excludes the driver and offers the trip to the next candidate.
+// It must be called with the mutex held.
+func (d *Dispatcher) moveOn(ctx context.Context, tripID, driverID string) error {
+	dsp, ok := d.dispatches[tripID]
+	if !ok || dsp.offeredTo != driverID {
+		// Stale answer, the offer already moved on
+		return nil
+	}
+
+	dsp.timer.Stop()
+	dsp.excluded[driverID] = true
+	dsp.offeredTo = ""
+	d.service.TransitionDriverFrom(driverID, DriverStatusOffered, DriverStatusAvailable)
+
+	if err := d.publish(
+		ctx,
+		contracts.TripEventDriverNotInterested,
+		dsp.trip.GetUserID(),
+		messaging.TripEventData{Trip: dsp.trip},
+	); err != nil {
+		log.Printf("Failed to publish %s: %v", contracts.TripEventDriverNotInterested, err)
+	}
+
+	return d.offerNext(ctx, dsp)
+}
+
+// offerNext offers the trip to the best candidate left, or gives up when there is none.
+// It must be called with the mutex held.
+func (d *Dispatcher) offerNext(ctx context.Context, dsp *dispatch) error {
+	trip := dsp.trip
+
+	candidates := d.service.FindCandidates(
+		d.cfg,
+		trip.Pickup.Latitude,
+		trip.Pickup.Longitude,
+		trip.GetSelectedFare().GetPackageSlug(),
+		func(driverID string) bool {
+			return dsp.excluded[driverID] || d.isOffered(driverID)
+		},
+	)
+
+	if len(candidates) == 0 {
+		log.Printf("No drivers found for trip %s", trip.GetId())
+		d.stop(dsp)
+
+		return d.publish(ctx, contracts.TripEventNoDriversFound, trip.GetUserID(), messaging.TripEventData{Trip: trip})
+	}
+
+	best := candidates[0]
+	log.Printf(
+		"Offering trip %s to driver %s (%.0fm away, ETA %.0fs)",
+		trip.GetId(), best.Driver.Id, best.Distance, best.ETA,
+	)
+
+	if !d.service.TransitionDriverFrom(best.Driver.Id, DriverStatusAvailable, DriverStatusOffered) {
+		// Changed status since the search, try the others
+		dsp.excluded[best.Driver.Id] = true
+		return d.offerNext(ctx, dsp)
+	}
+
+	dsp.offeredTo = best.Driver.Id
+	dsp.timer = time.AfterFunc(d.offerTimeout, func() {
+		d.timeout(trip.GetId(), best.Driver.Id)
+	})
+
+	return d.publish(
+		ctx,
+		contracts.DriverCmdTripRequest,
+		best.Driver.Id,
+		messaging.DriverTripRequestData{Trip: trip, Driver: best.Driver},
+	)
+}
+
+// restart offers the trip again from scratch, keeping the excluded drivers.
+// It must be called with the mutex held.
+func (d *Dispatcher) restart(ctx context.Context, dsp *dispatch) error {
+	d.dispatches[dsp.trip.GetId()] = dsp
+	return d.offerNext(ctx, dsp)
+}
+
+// stop must be called with the mutex held
+func (d *Dispatcher) stop(dsp *dispatch) {
+	if dsp.timer != nil {
+		dsp.timer.Stop()
+	}
+
+	dsp.offeredTo = ""
+	delete(d.dispatches, dsp.trip.GetId())
+}
+
+func (d *Dispatcher) publish(ctx context.Context, routingKey, ownerID string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the %s payload: %v", routingKey, err)
+	}
+
+	return d.rabbitMQ.Publish(ctx, routingKey, contracts.AmqpMessage{
+		OwnerID: ownerID,
+		Data:    data,
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// driverConsumer handles the drivers' answers to the trip offers
+type driverConsumer struct {
+	rabbitMQ   *messaging.RabbitMQ
+	dispatcher *Dispatcher
+}
+
+func NewDriverConsumer(rabbitMQ *messaging.RabbitMQ, dispatcher *Dispatcher) Consumer {
+	return &driverConsumer{
+		rabbitMQ:   rabbitMQ,
+		dispatcher: dispatcher,
+	}
+}
+
+func (c *driverConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.DriverTripResponseQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.DriverTripResponseData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the driver response: %v", err)
+			}
+
+			// The owner is set by the gateway from the driver's connection, so it can be trusted
+			driverID := message.OwnerID
+
+			switch msg.RoutingKey {
+			case contracts.DriverCmdTripAccept:
+				return c.dispatcher.Accept(ctx, payload.TripID, driverID)
+			case contracts.DriverCmdTripDecline:
+				return c.dispatcher.Decline(ctx, payload.TripID, driverID)
+			}
+
+			log.Printf("Unknown driver response: %s", msg.RoutingKey)
+			return nil
+		},
+	)
+}
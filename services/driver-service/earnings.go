@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/messaging"
+	tripPb "ride-sharing/shared/proto/trip"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// commissionConfig is the share of the fares the platform keeps, per package
+type commissionConfig struct {
+	rates       map[string]float64
+	defaultRate float64
+}
+
+func commissionConfigFromEnv() commissionConfig {
+	return commissionConfig{
+		rates:       parseRates(env.GetString("DRIVER_COMMISSION_RATES", "luxury=0.2,pool=0.3")),
+		defaultRate: env.GetFloat("DRIVER_COMMISSION_DEFAULT", 0.25),
+	}
+}
+
+func (c commissionConfig) rate(packageSlug string) float64 {
+	if rate, ok := c.rates[packageSlug]; ok {
+		return rate
+	}
+
+	return c.defaultRate
+}
+
+// parseRates parses package=rate pairs, separated by commas. Invalid pairs are skipped.
+func parseRates(value string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		slug, rawRate, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rawRate), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			log.Printf("Ignoring the invalid commission rate %q", pair)
+			continue
+		}
+
+		rates[strings.TrimSpace(slug)] = rate
+	}
+
+	return rates
+}
+
+// Earnings books what the drivers earn on the ledger
+type Earnings struct {
+	ledger     *Ledger
+	commission commissionConfig
+}
+
+func NewEarnings(ledger *Ledger, commission commissionConfig) *Earnings {
+	return &Earnings{
+		ledger:     ledger,
+		commission: commission,
+	}
+}
+
+// RecordTripCompleted books the fares of the trip to the driver, minus the commission of the package
+func (e *Earnings) RecordTripCompleted(trip *tripPb.Trip) error {
+	driverID := trip.GetDriver().GetId()
+	if driverID == "" {
+		return fmt.Errorf("completed trip %s has no driver", trip.GetId())
+	}
+
+	// Pool trips are paid by every rider, the others carry their owner as their only rider
+	var fare float64
+	if len(trip.GetRiders()) > 1 {
+		for _, rider := range trip.GetRiders() {
+			fare += rider.GetFare().GetTotalPriceInCents()
+		}
+	} else if final := trip.GetFinalFare(); final != nil {
+		// The route driven, rather than the quote
+		fare = final.GetTotalPriceInCents()
+	} else {
+		fare = trip.GetSelectedFare().GetTotalPriceInCents()
+	}
+
+	gross := int64(math.Round(fare))
+	packageSlug := trip.GetSelectedFare().GetPackageSlug()
+	commission := int64(math.Round(float64(gross) * e.commission.rate(packageSlug)))
+
+	earning := transfer("trip:"+trip.GetId()+":fare", TransactionEarning, accountReceivable, driverAccount(driverID), gross)
+	earning.driverID = driverID
+	earning.tripID = trip.GetId()
+	earning.description = fmt.Sprintf("%s trip fare", packageSlug)
+
+	fee := transfer("trip:"+trip.GetId()+":commission", TransactionFee, driverAccount(driverID), accountRevenue, commission)
+	fee.driverID = driverID
+	fee.tripID = trip.GetId()
+	fee.description = fmt.Sprintf("%.0f%% %s commission", e.commission.rate(packageSlug)*100, packageSlug)
+
+	for _, tx := range []*ledgerTransaction{earning, fee} {
+		if tx.entries[0].amount == 0 {
+			continue
+		}
+		if _, err := e.ledger.Record(tx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RecordPayment settles what the rider owed, the tip going to the driver in full
+func (e *Earnings) RecordPayment(payment *messaging.PaymentEventData) error {
+	if payment.TipInCents < 0 || payment.TipInCents > payment.AmountInCents {
+		return fmt.Errorf("payment of trip %s has an invalid tip of %d", payment.TripID, payment.TipInCents)
+	}
+
+	// The part of the final fare over the hold is paid apart, it settles the rest of what's owed
+	id, description := "payment:"+payment.TripID+":"+payment.UserID, fmt.Sprintf("payment of %s", payment.UserID)
+	if payment.Kind == messaging.PaymentKindBalance {
+		id, description = "balance:"+payment.TripID+":"+payment.UserID, fmt.Sprintf("balance of %s", payment.UserID)
+	}
+
+	settlement := transfer(
+		id,
+		TransactionPayment,
+		accountCash,
+		accountReceivable,
+		payment.AmountInCents-payment.TipInCents,
+	)
+	settlement.tripID = payment.TripID
+	settlement.description = description
+
+	// Tips added after the trip are paid on their own, there's nothing owed to settle
+	if settlement.entries[0].amount != 0 {
+		if _, err := e.ledger.Record(settlement); err != nil {
+			return err
+		}
+	}
+
+	if payment.TipInCents == 0 {
+		return nil
+	}
+
+	if payment.DriverID == "" {
+		return fmt.Errorf("tip of trip %s has no driver", payment.TripID)
+	}
+
+	tip := transfer(
+		"tip:"+payment.TripID+":"+payment.UserID,
+		TransactionTip,
+		accountCash,
+		driverAccount(payment.DriverID),
+		payment.TipInCents,
+	)
+	tip.driverID = payment.DriverID
+	tip.tripID = payment.TripID
+	tip.description = fmt.Sprintf("tip from %s", payment.UserID)
+
+	_, err := e.ledger.Record(tip)
+	return err
+}
+
+// Adjust pays the driver more, or takes back with a negative amount, outside of the trips
+func (e *Earnings) Adjust(driverID string, amount int64, reason, referenceID string) (*ledgerTransaction, error) {
+	switch {
+	case driverID == "":
+		return nil, fmt.Errorf("%w: the driver ID is required", ErrInvalidAdjustment)
+	case amount == 0:
+		return nil, fmt.Errorf("%w: the amount can't be zero", ErrInvalidAdjustment)
+	case strings.TrimSpace(reason) == "":
+		return nil, fmt.Errorf("%w: the reason is required", ErrInvalidAdjustment)
+	case referenceID == "":
+		return nil, fmt.Errorf("%w: the reference ID is required", ErrInvalidAdjustment)
+	}
+
+	id := "adjustment:" + referenceID
+
+	// Retried adjustments are returned as they were recorded the first time
+	if existing, ok := e.ledger.Get(id); ok {
+		return existing, nil
+	}
+
+	tx := transfer(id, TransactionAdjustment, accountAdjustments, driverAccount(driverID), amount)
+	tx.driverID = driverID
+	tx.description = strings.TrimSpace(reason)
+
+	if _, err := e.ledger.Record(tx); err != nil {
+		return nil, err
+	}
+
+	existing, _ := e.ledger.Get(id)
+	return existing, nil
+}
+
+type earningsConsumer struct {
+	rabbitMQ *messaging.RabbitMQ
+	earnings *Earnings
+}
+
+func NewEarningsConsumer(rabbitMQ *messaging.RabbitMQ, earnings *Earnings) Consumer {
+	return &earningsConsumer{
+		rabbitMQ: rabbitMQ,
+		earnings: earnings,
+	}
+}
+
+func (c *earningsConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.DriverEarningsQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			switch msg.RoutingKey {
+			case contracts.TripEventCompleted:
+				var payload messaging.TripEventData
+				if err := json.Unmarshal(message.Data, &payload); err != nil {
+					return fmt.Errorf("failed to unmarshal the trip event: %v", err)
+				}
+				return c.earnings.RecordTripCompleted(payload.Trip)
+			case contracts.PaymentEventSuccess:
+				var payload messaging.PaymentEventData
+				if err := json.Unmarshal(message.Data, &payload); err != nil {
+					return fmt.Errorf("failed to unmarshal the payment event: %v", err)
+				}
+				return c.earnings.RecordPayment(&payload)
+			}
+
+			log.Printf("Unknown earnings event: %s", msg.RoutingKey)
+			return nil
+		},
+	)
+}
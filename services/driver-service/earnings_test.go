@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ride-sharing/shared/messaging"
+	tripPb "ride-sharing/shared/proto/trip"
+)
+
+func newTestEarnings() (*Earnings, *Ledger) {
+	ledger := NewLedger()
+	commission := commissionConfig{rates: map[string]float64{"luxury": 0.2}, defaultRate: 0.25}
+
+	return NewEarnings(ledger, commission), ledger
+}
+
+func completedTrip(id, packageSlug string, fareInCents float64) *tripPb.Trip {
+	fare := &tripPb.RideFare{UserID: "rider", PackageSlug: packageSlug, TotalPriceInCents: fareInCents}
+
+	return &tripPb.Trip{
+		Id:           id,
+		SelectedFare: fare,
+		Driver:       &tripPb.TripDriver{Id: "driver"},
+		Riders:       []*tripPb.TripRider{{UserID: "rider", Fare: fare}},
+	}
+}
+
+func TestRecordTripCompleted(t *testing.T) {
+	tests := []struct {
+		name           string
+		trip           *tripPb.Trip
+		wantEarning    int64
+		wantCommission int64
+	}{
+		{
+			name:           "default rate",
+			trip:           completedTrip("sedan", "sedan", 1_000.4),
+			wantEarning:    1_000,
+			wantCommission: 250,
+		},
+		{
+			name:           "package rate",
+			trip:           completedTrip("luxury", "luxury", 2_000),
+			wantEarning:    2_000,
+			wantCommission: 400,
+		},
+		{
+			name: "final fare",
+			trip: func() *tripPb.Trip {
+				// The driver took a longer route than quoted
+				trip := completedTrip("final", "sedan", 1_000)
+				trip.FinalFare = &tripPb.FinalFare{TotalPriceInCents: 1_200}
+				return trip
+			}(),
+			wantEarning:    1_200,
+			wantCommission: 300,
+		},
+		{
+			name: "pool riders",
+			trip: &tripPb.Trip{
+				Id:           "pool",
+				SelectedFare: &tripPb.RideFare{PackageSlug: "pool", TotalPriceInCents: 600},
+				Driver:       &tripPb.TripDriver{Id: "driver"},
+				Riders: []*tripPb.TripRider{
+					{UserID: "owner", Fare: &tripPb.RideFare{TotalPriceInCents: 600}},
+					{UserID: "rider", Fare: &tripPb.RideFare{TotalPriceInCents: 400}},
+				},
+			},
+			wantEarning:    1_000,
+			wantCommission: 250,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			earnings, ledger := newTestEarnings()
+
+			// Replayed completions are booked once
+			for range 2 {
+				if err := earnings.RecordTripCompleted(tt.trip); err != nil {
+					t.Fatalf("RecordTripCompleted() error = %v", err)
+				}
+			}
+
+			if got := ledger.Balance(driverAccount("driver")); got != tt.wantEarning-tt.wantCommission {
+				t.Errorf("driver balance = %d, want %d", got, tt.wantEarning-tt.wantCommission)
+			}
+			if got := ledger.Balance(accountRevenue); got != tt.wantCommission {
+				t.Errorf("revenue = %d, want %d", got, tt.wantCommission)
+			}
+			// The riders owe the fare until they pay
+			if got := ledger.Balance(accountReceivable); got != -tt.wantEarning {
+				t.Errorf("receivable = %d, want %d", got, -tt.wantEarning)
+			}
+		})
+	}
+}
+
+func TestRecordPayment(t *testing.T) {
+	earnings, ledger := newTestEarnings()
+	if err := earnings.RecordTripCompleted(completedTrip("trip", "sedan", 1_000)); err != nil {
+		t.Fatalf("RecordTripCompleted() error = %v", err)
+	}
+
+	invalid := &messaging.PaymentEventData{
+		TripID:        "trip",
+		UserID:        "rider",
+		DriverID:      "driver",
+		AmountInCents: 100,
+		TipInCents:    200,
+	}
+	if err := earnings.RecordPayment(invalid); err == nil {
+		t.Error("RecordPayment() with a tip over the amount succeeded")
+	}
+
+	payment := &messaging.PaymentEventData{
+		TripID:        "trip",
+		UserID:        "rider",
+		DriverID:      "driver",
+		AmountInCents: 1_200,
+		TipInCents:    200,
+	}
+	if err := earnings.RecordPayment(payment); err != nil {
+		t.Fatalf("RecordPayment() error = %v", err)
+	}
+
+	// The fare was settled and the whole tip went to the driver
+	if got := ledger.Balance(accountReceivable); got != 0 {
+		t.Errorf("receivable = %d, want 0", got)
+	}
+	if got := ledger.Balance(driverAccount("driver")); got != 750+200 {
+		t.Errorf("driver balance = %d, want %d", got, 750+200)
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	earnings, ledger := newTestEarnings()
+
+	if _, err := earnings.Adjust("driver", 0, "bonus", "ref"); !errors.Is(err, ErrInvalidAdjustment) {
+		t.Errorf("Adjust() of 0 error = %v, want %v", err, ErrInvalidAdjustment)
+	}
+	if _, err := earnings.Adjust("driver", 500, " ", "ref"); !errors.Is(err, ErrInvalidAdjustment) {
+		t.Errorf("Adjust() without a reason error = %v, want %v", err, ErrInvalidAdjustment)
+	}
+
+	first, err := earnings.Adjust("driver", 500, "bonus", "ref")
+	if err != nil {
+		t.Fatalf("Adjust() error = %v", err)
+	}
+	// Retried with the same reference
+	retried, err := earnings.Adjust("driver", 500, "bonus", "ref")
+	if err != nil {
+		t.Fatalf("Adjust() again error = %v", err)
+	}
+	if retried != first {
+		t.Error("retried adjustment recorded again")
+	}
+	if _, err := earnings.Adjust("driver", -200, "damage", "other"); err != nil {
+		t.Fatalf("Adjust() negative error = %v", err)
+	}
+
+	if got := ledger.Balance(driverAccount("driver")); got != 300 {
+		t.Errorf("driver balance = %d, want 300", got)
+	}
+}
+
+func TestPeriodBounds(t *testing.T) {
+	// Wednesday
+	date := time.Date(2024, time.March, 13, 15, 30, 0, 0, statementLocation)
+
+	tests := []struct {
+		period   string
+		from, to time.Time
+		wantErr  error
+	}{
+		{
+			period: PeriodDaily,
+			from:   time.Date(2024, time.March, 13, 0, 0, 0, 0, statementLocation),
+			to:     time.Date(2024, time.March, 14, 0, 0, 0, 0, statementLocation),
+		},
+		{
+			period: PeriodWeekly,
+			from:   time.Date(2024, time.March, 11, 0, 0, 0, 0, statementLocation),
+			to:     time.Date(2024, time.March, 18, 0, 0, 0, 0, statementLocation),
+		},
+		{
+			period:  "monthly",
+			wantErr: ErrInvalidPeriod,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			from, to, err := periodBounds(tt.period, date)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("periodBounds() error = %v, want %v", err, tt.wantErr)
+			}
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Errorf("periodBounds() = [%s, %s), want [%s, %s)", from, to, tt.from, tt.to)
+			}
+		})
+	}
+
+	// Sundays belong to the week which started on the Monday before
+	sunday := time.Date(2024, time.March, 17, 23, 0, 0, 0, statementLocation)
+	if from, _, _ := periodBounds(PeriodWeekly, sunday); from.Day() != 11 {
+		t.Errorf("week of Sunday the 17th starts on the %d, want the 11th", from.Day())
+	}
+}
+
+func TestStatement(t *testing.T) {
+	earnings, _ := newTestEarnings()
+	if err := earnings.RecordTripCompleted(completedTrip("trip", "sedan", 1_000)); err != nil {
+		t.Fatalf("RecordTripCompleted() error = %v", err)
+	}
+	payment := &messaging.PaymentEventData{
+		TripID:        "trip",
+		UserID:        "rider",
+		DriverID:      "driver",
+		AmountInCents: 1_200,
+		TipInCents:    200,
+	}
+	if err := earnings.RecordPayment(payment); err != nil {
+		t.Fatalf("RecordPayment() error = %v", err)
+	}
+	if _, err := earnings.Adjust("driver", -100, "damage", "ref"); err != nil {
+		t.Fatalf("Adjust() error = %v", err)
+	}
+
+	statement, err := earnings.Statement("driver", PeriodDaily, time.Now())
+	if err != nil {
+		t.Fatalf("Statement() error = %v", err)
+	}
+
+	// The settlement of the fare doesn't touch the driver account
+	if len(statement.Lines) != 4 {
+		t.Errorf("%d lines, want 4", len(statement.Lines))
+	}
+	if statement.EarningsInCents != 1_000 ||
+		statement.FeesInCents != -250 ||
+		statement.TipsInCents != 200 ||
+		statement.AdjustmentsInCents != -100 ||
+		statement.NetInCents != 850 {
+		t.Errorf("totals = %d earnings, %d fees, %d tips, %d adjustments, %d net, want 1000, -250, 200, -100, 850",
+			statement.EarningsInCents,
+			statement.FeesInCents,
+			statement.TipsInCents,
+			statement.AdjustmentsInCents,
+			statement.NetInCents,
+		)
+	}
+
+	data, err := statementCSV(statement)
+	if err != nil {
+		t.Fatalf("statementCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("statement CSV unreadable: %v", err)
+	}
+	// Header, lines and the 5 totals
+	if len(rows) != 1+4+5 {
+		t.Fatalf("%d rows, want %d", len(rows), 1+4+5)
+	}
+	if net := rows[len(rows)-1]; net[4] != "net" || net[5] != "850" {
+		t.Errorf("last row = %v, want the net of 850", net)
+	}
+
+	yesterday, err := earnings.Statement("driver", PeriodDaily, time.Now().AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatalf("Statement() error = %v", err)
+	}
+	if len(yesterday.Lines) != 0 || yesterday.NetInCents != 0 {
+		t.Errorf("statement of yesterday has %d lines", len(yesterday.Lines))
+	}
+}
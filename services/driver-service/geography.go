@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"ride-sharing/shared/geojson"
+)
+
+// loadRoutes loads the routes of the drivers from the GeoJSON file, or falls back to the predefined ones.
+// When service areas are configured, every point of the routes has to be within one.
+func loadRoutes(routesFile, areasFile string) ([][][]float64, error) {
+	routes := PredefinedRoutes
+	if routesFile != "" {
+		loaded, err := geojson.LoadRoutes(routesFile)
+		if err != nil {
+			return nil, err
+		}
+		routes = loaded
+	}
+
+	if areasFile != "" {
+		areas, err := geojson.LoadServiceAreas(areasFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, route := range routes {
+			for _, point := range route {
+				if _, ok := areas.Find(point[0], point[1]); !ok {
+					return nil, fmt.Errorf("route #%d goes through %v, out of the service areas", i+1, point)
+				}
+			}
+		}
+
+		log.Printf("Loaded %d service areas", len(areas))
+	}
+
+	log.Printf("Loaded %d driver routes", len(routes))
+
+	return routes, nil
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	pb "ride-sharing/shared/proto/driver"
+	"ride-sharing/shared/util"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -13,12 +16,24 @@ import (
 type driverGrpcHandler struct {
 	pb.UnimplementedDriverServiceServer
 
-	service *Service
+	service        *Service
+	locationFanout *LocationFanout
+	matchingCfg    matchingConfig
+	earnings       *Earnings
 }
 
-func NewGrpcHandler(s *grpc.Server, service *Service) {
+func NewGrpcHandler(
+	s *grpc.Server,
+	service *Service,
+	locationFanout *LocationFanout,
+	matchingCfg matchingConfig,
+	earnings *Earnings,
+) {
 	handler := &driverGrpcHandler{
-		service: service,
+		service:        service,
+		locationFanout: locationFanout,
+		matchingCfg:    matchingCfg,
+		earnings:       earnings,
 	}
 
 	pb.RegisterDriverServiceServer(s, handler)
@@ -30,6 +45,9 @@ func (h *driverGrpcHandler) RegisterDriver(
 ) (*pb.RegisterDriverResponse, error) {
 	driver, err := h.service.RegisterDriver(req.GetDriverID(), req.GetPackageSlug())
 	if err != nil {
+		if errors.Is(err, ErrProfileNotFound) || errors.Is(err, ErrPackageNotAllowed) {
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to register driver: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to register driver")
 	}
 
@@ -38,9 +56,303 @@ func (h *driverGrpcHandler) RegisterDriver(
 
 func (h *driverGrpcHandler) UnregisterDriver(
 	ctx context.Context,
-	req *pb.RegisterDriverRequest,
+	req *pb.UnregisterDriverRequest,
 ) (*pb.RegisterDriverResponse, error) {
 	h.service.UnregisterDriver(req.GetDriverID())
+	h.locationFanout.Forget(req.GetDriverID())
 
 	return &pb.RegisterDriverResponse{Driver: &pb.Driver{Id: req.GetDriverID()}}, nil
 }
+
+func (h *driverGrpcHandler) Heartbeat(
+	ctx context.Context,
+	req *pb.HeartbeatRequest,
+) (*pb.HeartbeatResponse, error) {
+	driverStatus, err := h.service.Heartbeat(req.GetDriverID())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "heartbeat failed: %v", err)
+	}
+
+	shift, err := h.service.ShiftSummary(req.GetDriverID())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "heartbeat failed: %v", err)
+	}
+
+	return &pb.HeartbeatResponse{Status: driverStatus, Shift: shift}, nil
+}
+
+func (h *driverGrpcHandler) SetDriverStatus(
+	ctx context.Context,
+	req *pb.SetDriverStatusRequest,
+) (*pb.RegisterDriverResponse, error) {
+	if err := h.service.SetClientStatus(req.GetDriverID(), req.GetStatus()); err != nil {
+		if errors.Is(err, ErrInvalidStatusTransition) || errors.Is(err, ErrBreakRequired) {
+			return nil, status.Errorf(codes.FailedPrecondition, "failed to set the driver status: %v", err)
+		}
+		return nil, status.Errorf(codes.NotFound, "failed to set the driver status: %v", err)
+	}
+
+	driver, ok := h.service.GetDriver(req.GetDriverID())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "driver %s is not registered", req.GetDriverID())
+	}
+
+	return &pb.RegisterDriverResponse{Driver: driver}, nil
+}
+
+func (h *driverGrpcHandler) UpdateDriverLocation(
+	ctx context.Context,
+	req *pb.UpdateDriverLocationRequest,
+) (*pb.RegisterDriverResponse, error) {
+	location := req.GetLocation()
+	if location == nil {
+		return nil, status.Error(codes.InvalidArgument, "location is required")
+	}
+
+	if !util.IsValidCoordinate(location.GetLatitude(), location.GetLongitude()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid location: %v", location)
+	}
+
+	driver, riderID, err := h.service.UpdateDriverLocation(
+		req.GetDriverID(),
+		location.GetLatitude(),
+		location.GetLongitude(),
+	)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to update the driver location: %v", err)
+	}
+
+	h.locationFanout.Publish(ctx, driver, riderID)
+
+	return &pb.RegisterDriverResponse{Driver: driver}, nil
+}
+
+func (h *driverGrpcHandler) GetDriver(
+	ctx context.Context,
+	req *pb.GetDriverRequest,
+) (*pb.GetDriverResponse, error) {
+	driver, ok := h.service.GetDriver(req.GetDriverID())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "driver %s is not registered", req.GetDriverID())
+	}
+
+	return &pb.GetDriverResponse{Driver: driver}, nil
+}
+
+func (h *driverGrpcHandler) ListNearbyDrivers(
+	ctx context.Context,
+	req *pb.ListNearbyDriversRequest,
+) (*pb.ListNearbyDriversResponse, error) {
+	location := req.GetLocation()
+	if location == nil || !util.IsValidCoordinate(location.GetLatitude(), location.GetLongitude()) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid location: %v", location)
+	}
+
+	radius := req.GetRadiusMeters()
+	if radius <= 0 || radius > maxNearbyRadiusMeters {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			"the radius has to be between 0 and %.0f meters, got %.0f", maxNearbyRadiusMeters, radius,
+		)
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > maxNearbyDrivers {
+		limit = maxNearbyDrivers
+	}
+
+	nearby := h.service.ListNearbyDrivers(
+		h.matchingCfg,
+		location.GetLatitude(),
+		location.GetLongitude(),
+		radius,
+		req.GetPackageSlug(),
+		limit,
+	)
+
+	drivers := make([]*pb.NearbyDriver, len(nearby))
+	for i, c := range nearby {
+		drivers[i] = &pb.NearbyDriver{Driver: c.Driver, Distance: c.Distance, Eta: c.ETA}
+	}
+
+	return &pb.ListNearbyDriversResponse{Drivers: drivers}, nil
+}
+
+func (h *driverGrpcHandler) WatchDriverLocation(
+	req *pb.WatchDriverLocationRequest,
+	stream grpc.ServerStreamingServer[pb.Driver],
+) error {
+	driver, updates, unsubscribe, ok := h.service.WatchDriver(req.GetDriverID())
+	if !ok {
+		return status.Errorf(codes.NotFound, "driver %s is not registered", req.GetDriverID())
+	}
+	defer unsubscribe()
+
+	// The current location first, the watcher doesn't have to wait for the driver to move
+	if err := stream.Send(driver); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case driver, open := <-updates:
+			if !open {
+				// Unregistered
+				return nil
+			}
+			if err := stream.Send(driver); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (h *driverGrpcHandler) CreateDriverProfile(
+	ctx context.Context,
+	req *pb.DriverProfileRequest,
+) (*pb.DriverProfile, error) {
+	profile, err := h.service.profiles.Create(req.GetProfile())
+	if err != nil {
+		return nil, profileError("failed to create the driver profile", err)
+	}
+
+	return profile, nil
+}
+
+func (h *driverGrpcHandler) GetDriverProfile(
+	ctx context.Context,
+	req *pb.GetDriverProfileRequest,
+) (*pb.DriverProfile, error) {
+	profile, err := h.service.profiles.Get(req.GetDriverID())
+	if err != nil {
+		return nil, profileError("failed to get the driver profile", err)
+	}
+
+	return profile, nil
+}
+
+// UpdateDriverProfile replaces the profile, the drivers online keep their vehicle until they register again
+func (h *driverGrpcHandler) UpdateDriverProfile(
+	ctx context.Context,
+	req *pb.DriverProfileRequest,
+) (*pb.DriverProfile, error) {
+	profile, err := h.service.profiles.Update(req.GetProfile())
+	if err != nil {
+		return nil, profileError("failed to update the driver profile", err)
+	}
+
+	return profile, nil
+}
+
+func (h *driverGrpcHandler) DeleteDriverProfile(
+	ctx context.Context,
+	req *pb.GetDriverProfileRequest,
+) (*pb.DeleteDriverProfileResponse, error) {
+	if err := h.service.profiles.Delete(req.GetDriverID()); err != nil {
+		return nil, profileError("failed to delete the driver profile", err)
+	}
+
+	return &pb.DeleteDriverProfileResponse{}, nil
+}
+
+func (h *driverGrpcHandler) ListDriverProfiles(
+	ctx context.Context,
+	req *pb.ListDriverProfilesRequest,
+) (*pb.ListDriverProfilesResponse, error) {
+	return &pb.ListDriverProfilesResponse{Profiles: h.service.profiles.List()}, nil
+}
+
+func (h *driverGrpcHandler) GetDriverStatement(
+	ctx context.Context,
+	req *pb.GetDriverStatementRequest,
+) (*pb.DriverStatement, error) {
+	statement, err := h.statement(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return statement, nil
+}
+
+func (h *driverGrpcHandler) ExportDriverStatement(
+	ctx context.Context,
+	req *pb.GetDriverStatementRequest,
+) (*pb.ExportDriverStatementResponse, error) {
+	statement, err := h.statement(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := statementCSV(statement)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to export the statement: %v", err)
+	}
+
+	return &pb.ExportDriverStatementResponse{
+		Filename: statementFilename(statement),
+		Csv:      data,
+	}, nil
+}
+
+func (h *driverGrpcHandler) statement(req *pb.GetDriverStatementRequest) (*pb.DriverStatement, error) {
+	if req.GetDriverID() == "" {
+		return nil, status.Error(codes.InvalidArgument, "the driver ID is required")
+	}
+
+	date := time.Now()
+	if req.GetDate() != nil {
+		date = req.GetDate().AsTime()
+	}
+
+	statement, err := h.earnings.Statement(req.GetDriverID(), req.GetPeriod(), date)
+	if err != nil {
+		if errors.Is(err, ErrInvalidPeriod) {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to get the statement: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get the statement: %v", err)
+	}
+
+	return statement, nil
+}
+
+func (h *driverGrpcHandler) AdjustDriverEarnings(
+	ctx context.Context,
+	req *pb.AdjustDriverEarningsRequest,
+) (*pb.StatementLine, error) {
+	tx, err := h.earnings.Adjust(req.GetDriverID(), req.GetAmountInCents(), req.GetReason(), req.GetReferenceID())
+	if err != nil {
+		if errors.Is(err, ErrInvalidAdjustment) {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to adjust the earnings: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to adjust the earnings: %v", err)
+	}
+
+	return toStatementLine(tx, tx.driverID), nil
+}
+
+func (h *driverGrpcHandler) GetDriverShift(
+	ctx context.Context,
+	req *pb.GetDriverShiftRequest,
+) (*pb.ShiftSummary, error) {
+	shift, err := h.service.ShiftSummary(req.GetDriverID())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to get the shift: %v", err)
+	}
+
+	return shift, nil
+}
+
+func profileError(msg string, err error) error {
+	switch {
+	case errors.Is(err, ErrProfileNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, ErrProfileAlreadyExists):
+		return status.Errorf(codes.AlreadyExists, "%s: %v", msg, err)
+	case errors.Is(err, ErrInvalidProfile):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
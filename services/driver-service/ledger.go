@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Ledger accounts, the drivers have one account each
+const (
+	// accountReceivable is what the riders owe for their completed trips
+	accountReceivable = "platform:receivable"
+	// accountCash is what the riders paid
+	accountCash = "platform:cash"
+	// accountRevenue is the commission kept on the trips
+	accountRevenue = "platform:revenue"
+	// accountAdjustments is what the platform paid, or took back, outside of the trips
+	accountAdjustments = "platform:adjustments"
+)
+
+func driverAccount(driverID string) string {
+	return "driver:" + driverID
+}
+
+// Transaction kinds, the ones touching driver accounts show up in the statements
+const (
+	TransactionEarning    = "earning"
+	TransactionFee        = "fee"
+	TransactionTip        = "tip"
+	TransactionAdjustment = "adjustment"
+	TransactionPayment    = "payment"
+)
+
+var ErrUnbalancedTransaction = errors.New("unbalanced ledger transaction")
+
+// ledgerEntry moves money in or out of an account: debits are positive and credits negative
+type ledgerEntry struct {
+	account string
+	amount  int64
+}
+
+// ledgerTransaction is a set of entries summing to zero
+type ledgerTransaction struct {
+	// id is derived from what caused the transaction, so replayed events are recorded once
+	id          string
+	kind        string
+	driverID    string
+	tripID      string
+	description string
+	createdAt   time.Time
+	entries     []ledgerEntry
+}
+
+// transfer is the transaction of an amount credited to an account and debited from another one
+func transfer(id, kind string, from, to string, amount int64) *ledgerTransaction {
+	return &ledgerTransaction{
+		id:        id,
+		kind:      kind,
+		createdAt: time.Now(),
+		entries: []ledgerEntry{
+			{account: from, amount: amount},
+			{account: to, amount: -amount},
+		},
+	}
+}
+
+// Ledger is the double-entry book of the driver earnings
+type Ledger struct {
+	mu           sync.RWMutex
+	transactions []*ledgerTransaction
+	byID         map[string]*ledgerTransaction
+}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		byID: make(map[string]*ledgerTransaction),
+	}
+}
+
+// Record adds the transaction, unless one with the same ID was already recorded.
+// It reports whether the transaction was added.
+func (l *Ledger) Record(tx *ledgerTransaction) (bool, error) {
+	var sum int64
+	for _, e := range tx.entries {
+		sum += e.amount
+	}
+	if sum != 0 || len(tx.entries) < 2 {
+		return false, fmt.Errorf("%w: %s is off by %d", ErrUnbalancedTransaction, tx.id, sum)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.byID[tx.id]; ok {
+		return false, nil
+	}
+
+	l.transactions = append(l.transactions, tx)
+	l.byID[tx.id] = tx
+
+	return true, nil
+}
+
+// Get returns the transaction with the ID
+func (l *Ledger) Get(id string) (*ledgerTransaction, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	tx, ok := l.byID[id]
+	return tx, ok
+}
+
+// Balance is what the account holds, credits count positive for the driver accounts
+func (l *Ledger) Balance(account string) int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var balance int64
+	for _, tx := range l.transactions {
+		balance += tx.amountFor(account)
+	}
+
+	return balance
+}
+
+// DriverTransactions returns the transactions touching the driver account within [from, to), oldest first
+func (l *Ledger) DriverTransactions(driverID string, from, to time.Time) []*ledgerTransaction {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	account := driverAccount(driverID)
+
+	var found []*ledgerTransaction
+	for _, tx := range l.transactions {
+		if tx.createdAt.Before(from) || !tx.createdAt.Before(to) {
+			continue
+		}
+		if tx.amountFor(account) != 0 {
+			found = append(found, tx)
+		}
+	}
+
+	return found
+}
+
+// amountFor returns what the transaction credits the account, negative when it's debited
+func (tx *ledgerTransaction) amountFor(account string) int64 {
+	var amount int64
+	for _, e := range tx.entries {
+		if e.account == account {
+			amount -= e.amount
+		}
+	}
+
+	return amount
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLedgerRecord(t *testing.T) {
+	ledger := NewLedger()
+
+	earning := transfer("trip:1:fare", TransactionEarning, accountReceivable, driverAccount("driver"), 1_000)
+
+	added, err := ledger.Record(earning)
+	if err != nil || !added {
+		t.Fatalf("Record() = %v, %v, want the transaction added", added, err)
+	}
+
+	// Replayed events are recorded once
+	added, err = ledger.Record(earning)
+	if err != nil || added {
+		t.Errorf("Record() again = %v, %v, want the transaction skipped", added, err)
+	}
+
+	unbalanced := transfer("trip:2:fare", TransactionEarning, accountReceivable, driverAccount("driver"), 500)
+	unbalanced.entries[0].amount = 400
+	if _, err := ledger.Record(unbalanced); !errors.Is(err, ErrUnbalancedTransaction) {
+		t.Errorf("Record() unbalanced error = %v, want %v", err, ErrUnbalancedTransaction)
+	}
+
+	if _, err := ledger.Record(transfer("fee", TransactionFee, driverAccount("driver"), accountRevenue, 250)); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	balances := map[string]int64{
+		driverAccount("driver"): 750,
+		accountReceivable:       -1_000,
+		accountRevenue:          250,
+		driverAccount("other"):  0,
+	}
+	for account, want := range balances {
+		if got := ledger.Balance(account); got != want {
+			t.Errorf("Balance(%s) = %d, want %d", account, got, want)
+		}
+	}
+}
+
+func TestLedgerDriverTransactions(t *testing.T) {
+	ledger := NewLedger()
+	now := time.Now()
+
+	for id, createdAt := range map[string]time.Time{
+		"yesterday": now.Add(-24 * time.Hour),
+		"today":     now,
+		"tomorrow":  now.Add(24 * time.Hour),
+	} {
+		tx := transfer(id, TransactionEarning, accountReceivable, driverAccount("driver"), 100)
+		tx.createdAt = createdAt
+		if _, err := ledger.Record(tx); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	other := transfer("other", TransactionEarning, accountReceivable, driverAccount("other"), 100)
+	if _, err := ledger.Record(other); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	found := ledger.DriverTransactions("driver", now.Add(-time.Hour), now.Add(time.Hour))
+	if len(found) != 1 || found[0].id != "today" {
+		t.Errorf("DriverTransactions() = %d transactions, want today's only", len(found))
+	}
+}
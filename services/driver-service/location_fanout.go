@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+	pb "ride-sharing/shared/proto/driver"
+)
+
+// LocationFanout publishes the driver locations for the riders watching them.
+// Drivers send their location way more often than riders need it, so every driver
+// is published at most once per interval.
+type LocationFanout struct {
+	rabbitMQ *messaging.RabbitMQ
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func NewLocationFanout(rabbitMQ *messaging.RabbitMQ, interval time.Duration) *LocationFanout {
+	return &LocationFanout{
+		rabbitMQ: rabbitMQ,
+		interval: interval,
+		lastSent: make(map[string]time.Time),
+	}
+}
+
+// Publish sends the driver location, unless it was sent less than an interval ago
+func (f *LocationFanout) Publish(ctx context.Context, driver *pb.Driver, riderID string) {
+	if !f.allow(driver.GetId(), time.Now()) {
+		return
+	}
+
+	data, err := json.Marshal(messaging.DriverLocationData{Driver: driver, RiderID: riderID})
+	if err != nil {
+		log.Printf("Failed to marshal the location of driver %s: %v", driver.GetId(), err)
+		return
+	}
+
+	err = f.rabbitMQ.Publish(ctx, contracts.DriverCmdLocation, contracts.AmqpMessage{
+		OwnerID: driver.GetId(),
+		Data:    data,
+	})
+	if err != nil {
+		log.Printf("Failed to publish the location of driver %s: %v", driver.GetId(), err)
+	}
+}
+
+// Forget drops the throttling state of the driver
+func (f *LocationFanout) Forget(driverID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.lastSent, driverID)
+}
+
+func (f *LocationFanout) allow(driverID string, now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if last, ok := f.lastSent[driverID]; ok && now.Sub(last) < f.interval {
+		return false
+	}
+
+	f.lastSent[driverID] = now
+	return true
+}
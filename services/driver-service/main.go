@@ -7,11 +7,16 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"ride-sharing/shared/env"
 	"ride-sharing/shared/messaging"
+	"ride-sharing/shared/osrm"
+	tripPb "ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/util"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 var GRPCAddr = ":9082"
@@ -32,7 +37,45 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	svc := newService()
+	routes, err := loadRoutes(
+		env.GetString("SIMULATION_ROUTES_FILE", ""),
+		env.GetString("SERVICE_AREAS_FILE", ""),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load the driver routes: %v", err)
+	}
+
+	profiles, err := NewProfileStore(env.GetString("DRIVER_PROFILES_FILE", ""))
+	if err != nil {
+		log.Fatalf("Failed to load the driver profiles: %v", err)
+	}
+
+	simulation := env.GetBool("DRIVER_SIMULATION", false)
+
+	// The made up demo profiles are only handed out in simulation mode
+	requireProfiles := env.GetBool("DRIVER_PROFILE_REQUIRED", true)
+	if !requireProfiles && !simulation {
+		log.Println("DRIVER_PROFILE_REQUIRED=false is ignored outside of simulation mode, the drivers need a profile")
+		requireProfiles = true
+	}
+
+	statementLocation, err = loadTimezone("DRIVER_STATEMENT_TIMEZONE")
+	if err != nil {
+		log.Fatalf("Failed to load the statement timezone: %v", err)
+	}
+
+	shiftCfg, err := shiftConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load the shift limits: %v", err)
+	}
+
+	svc := newService(routes, profiles, requireProfiles, shiftCfg)
+	go svc.SweepOffline(
+		ctx,
+		env.GetDuration("DRIVER_HEARTBEAT_TIMEOUT", 30*time.Second),
+		env.GetDuration("DRIVER_SWEEP_INTERVAL", 5*time.Second),
+	)
+	go svc.EnforceShiftLimits(ctx, env.GetDuration("DRIVER_SHIFT_CHECK_INTERVAL", 30*time.Second))
 
 	rabbitMQURI := env.GetString(env.RabbitMQ.URI, env.RabbitMQDefaults.URI)
 	rabbitMQ, err := messaging.NewRabbitMQ(rabbitMQURI)
@@ -43,16 +86,63 @@ func main() {
 
 	log.Println("Successfully connected to RabbitMQ")
 
-	consumer := NewTripConsumer(rabbitMQ)
-	go func(consumer Consumer) {
-		if err := consumer.Listen(); err != nil {
-			log.Fatalf("Failed to listen to the RabbitMQ messages: %v", err)
+	matchingCfg := matchingConfigFromEnv()
+	dispatcher := NewDispatcher(
+		rabbitMQ,
+		svc,
+		matchingCfg,
+		env.GetDuration("DISPATCH_OFFER_TIMEOUT", 15*time.Second),
+	)
+
+	earnings := NewEarnings(NewLedger(), commissionConfigFromEnv())
+
+	consumers := []Consumer{
+		NewTripConsumer(rabbitMQ, dispatcher),
+		NewDriverConsumer(rabbitMQ, dispatcher),
+		NewCompletionConsumer(rabbitMQ, svc),
+		NewRatingConsumer(rabbitMQ, svc),
+		NewEarningsConsumer(rabbitMQ, earnings),
+	}
+	for _, consumer := range consumers {
+		go func(consumer Consumer) {
+			if err := consumer.Listen(); err != nil {
+				log.Fatalf("Failed to listen to the RabbitMQ messages: %v", err)
+			}
+		}(consumer)
+	}
+
+	locationFanout := NewLocationFanout(rabbitMQ, env.GetDuration("DRIVER_LOCATION_FANOUT_INTERVAL", 2*time.Second))
+
+	if simulation {
+		router := osrm.NewClient(
+			env.GetString("OSRM_API", "http://router.project-osrm.org"),
+			env.GetDuration("OSRM_TIMEOUT", 5*time.Second),
+		)
+
+		// The bots complete their trips like the real drivers, through the trip service
+		tripConn, err := grpc.NewClient(
+			env.GetString("TRIP_SERVICE_URL", "trip-service:9083"),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		if err != nil {
+			log.Fatalf("Failed to create the trip service client: %v", err)
 		}
-	}(consumer)
+		defer util.CloseOrLog(tripConn, "gRPC connection for trip service client")
+
+		simulator := NewSimulator(
+			svc,
+			dispatcher,
+			locationFanout,
+			router,
+			tripPb.NewTripServiceClient(tripConn),
+			simulationConfigFromEnv(),
+		)
+		go simulator.Run(ctx)
+	}
 
 	// Starting the gRPC server
 	grpcServer := grpc.NewServer()
-	NewGrpcHandler(grpcServer, svc)
+	NewGrpcHandler(grpcServer, svc, locationFanout, matchingCfg, earnings)
 
 	log.Printf("Starting Driver service gRPC server on port %s", lis.Addr().String())
 
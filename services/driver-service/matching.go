@@ -0,0 +1,150 @@
+package main
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"ride-sharing/shared/env"
+	pb "ride-sharing/shared/proto/driver"
+)
+
+const (
+	minRating = 1
+	maxRating = 5
+)
+
+type matchingConfig struct {
+	// radiusSteps are the search radiuses in meters, tried in order until a driver is found
+	radiusSteps []float64
+	// avgSpeedKmh is used to estimate how long drivers take to reach the pickup
+	avgSpeedKmh float64
+	// maxCandidates is how many of the closest drivers are ranked
+	maxCandidates int
+	// ratingWeight is how much a low rating counts against a driver, 0 ignores the ratings.
+	// The ETA of a 1 star driver is counted (1 + ratingWeight) times.
+	ratingWeight float64
+}
+
+func matchingConfigFromEnv() matchingConfig {
+	return matchingConfig{
+		radiusSteps:   parseFloatList(env.GetString("MATCHING_RADIUS_STEPS", "1000,2500,5000"), []float64{1000, 2500, 5000}),
+		avgSpeedKmh:   env.GetFloat("MATCHING_AVG_SPEED_KMH", 25),
+		maxCandidates: env.GetInt("MATCHING_MAX_CANDIDATES", 20),
+		ratingWeight:  env.GetFloat("MATCHING_RATING_WEIGHT", 0.5),
+	}
+}
+
+// candidate is a driver that can take a trip
+type candidate struct {
+	Driver   *pb.Driver
+	Distance float64 // meters to the pickup
+	ETA      float64 // seconds to the pickup
+	Score    float64 // lower is better
+}
+
+// FindCandidates looks for drivers of the package around the pickup, growing the search
+// radius over the configured steps until someone is found. Candidates are ranked best first.
+func (s *Service) FindCandidates(
+	cfg matchingConfig,
+	lat, lng float64,
+	packageSlug string,
+	exclude func(driverID string) bool,
+) []*candidate {
+	speedMps := cfg.avgSpeedKmh * 1000 / 3600
+
+	for _, radius := range cfg.radiusSteps {
+		matches := s.FindNearbyDrivers(lat, lng, radius, packageSlug, 0)
+
+		candidates := make([]*candidate, 0, len(matches))
+		for _, m := range matches {
+			if exclude != nil && exclude(m.Driver.Id) {
+				continue
+			}
+
+			eta := m.Distance / speedMps
+			candidates = append(candidates, &candidate{
+				Driver:   m.Driver,
+				Distance: m.Distance,
+				ETA:      eta,
+				Score:    eta * ratingPenalty(cfg, m.Driver),
+			})
+
+			if cfg.maxCandidates > 0 && len(candidates) >= cfg.maxCandidates {
+				break
+			}
+		}
+
+		if len(candidates) > 0 {
+			rankCandidates(candidates)
+			return candidates
+		}
+	}
+
+	return nil
+}
+
+// Bounds of the nearby drivers listings, the spatial index is walked cell by cell
+const (
+	maxNearbyRadiusMeters float64 = 20_000
+	maxNearbyDrivers              = 100
+)
+
+// ListNearbyDrivers returns the drivers of the package available around the location, closest first,
+// with the time they'd take to get there. An empty package matches every package.
+func (s *Service) ListNearbyDrivers(
+	cfg matchingConfig,
+	lat, lng float64,
+	radiusMeters float64,
+	packageSlug string,
+	limit int,
+) []*candidate {
+	speedMps := cfg.avgSpeedKmh * 1000 / 3600
+
+	matches := s.FindNearbyDrivers(lat, lng, radiusMeters, packageSlug, limit)
+
+	nearby := make([]*candidate, len(matches))
+	for i, m := range matches {
+		eta := m.Distance / speedMps
+		nearby[i] = &candidate{Driver: m.Driver, Distance: m.Distance, ETA: eta, Score: eta}
+	}
+
+	return nearby
+}
+
+// ratingPenalty scales the ETA of the driver by its rating, unrated drivers get the benefit of the doubt
+func ratingPenalty(cfg matchingConfig, driver *pb.Driver) float64 {
+	if driver.RatingCount == 0 {
+		return 1
+	}
+
+	return 1 + cfg.ratingWeight*(maxRating-driver.Rating)/(maxRating-minRating)
+}
+
+func rankCandidates(candidates []*candidate) {
+	slices.SortStableFunc(candidates, func(a, b *candidate) int {
+		switch {
+		case a.Score < b.Score:
+			return -1
+		case a.Score > b.Score:
+			return 1
+		}
+		return 0
+	})
+}
+
+// parseFloatList parses comma separated numbers, invalid lists give back the fallback
+func parseFloatList(value string, fallback []float64) []float64 {
+	parts := strings.Split(value, ",")
+	values := make([]float64, 0, len(parts))
+
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || v <= 0 {
+			return fallback
+		}
+		values = append(values, v)
+	}
+
+	return values
+}
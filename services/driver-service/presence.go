@@ -0,0 +1,240 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	pb "ride-sharing/shared/proto/driver"
+	tripPb "ride-sharing/shared/proto/trip"
+)
+
+// Driver statuses
+const (
+	DriverStatusOffline   = "offline"
+	DriverStatusAvailable = "available"
+	DriverStatusOffered   = "offered"
+	DriverStatusEnRoute   = "en_route"
+	DriverStatusOnTrip    = "on_trip"
+	DriverStatusBreak     = "break"
+)
+
+var ErrInvalidStatusTransition = errors.New("invalid driver status transition")
+
+// statusTransitions lists the statuses every status can move to.
+// Any status can go offline, that's handled separately. Offline drivers come back
+// on a break when they went offline during a required break, and on their trip when they had one.
+var statusTransitions = map[string][]string{
+	DriverStatusOffline:   {DriverStatusAvailable, DriverStatusBreak, DriverStatusEnRoute, DriverStatusOnTrip},
+	DriverStatusAvailable: {DriverStatusOffered, DriverStatusBreak},
+	DriverStatusOffered:   {DriverStatusAvailable, DriverStatusEnRoute},
+	DriverStatusEnRoute:   {DriverStatusOnTrip, DriverStatusAvailable},
+	DriverStatusOnTrip:    {DriverStatusAvailable},
+	DriverStatusBreak:     {DriverStatusAvailable},
+}
+
+// clientStatuses are the statuses drivers can switch to themselves, through the WebSocket.
+// Drivers on a trip can only report the pickup, the trip service ends the trip.
+var clientStatuses = []string{DriverStatusAvailable, DriverStatusBreak, DriverStatusOnTrip}
+
+func canTransition(from, to string) bool {
+	return to == DriverStatusOffline || slices.Contains(statusTransitions[from], to)
+}
+
+// TransitionDriver moves the driver to the status, if the current status allows it
+func (s *Service) TransitionDriver(driverID, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.transition(driverID, to)
+}
+
+// TransitionDriverFrom moves the driver to the status only when it's currently in the from status.
+// It's used by the trip workflow, where a driver may have moved on already (ex. went on a break).
+func (s *Service) TransitionDriverFrom(driverID, from, to string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.drivers[driverID]
+	if !ok || d.Driver.Status != from {
+		return false
+	}
+
+	return s.transition(driverID, to) == nil
+}
+
+// SetClientStatus applies a status change requested by the driver
+func (s *Service) SetClientStatus(driverID, to string) error {
+	if !slices.Contains(clientStatuses, to) {
+		return fmt.Errorf("%w: drivers can't switch to %s", ErrInvalidStatusTransition, to)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.drivers[driverID]
+	onTrip := ok && (d.trip != nil || isOnTrip(d.Driver.Status))
+	if onTrip && !(d.Driver.Status == DriverStatusEnRoute && to == DriverStatusOnTrip) {
+		return fmt.Errorf("%w: driver %s is %s on a trip", ErrInvalidStatusTransition, driverID, d.Driver.Status)
+	}
+
+	return s.transition(driverID, to)
+}
+
+// transition must be called with the mutex held
+func (s *Service) transition(driverID, to string) error {
+	d, ok := s.drivers[driverID]
+	if !ok {
+		return fmt.Errorf("driver %s is not registered", driverID)
+	}
+
+	from := d.Driver.Status
+	if from == to {
+		return nil
+	}
+
+	if !canTransition(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	now := time.Now()
+	if err := s.checkShift(driverID, to, now); err != nil {
+		return err
+	}
+
+	d.Driver.Status = to
+	s.shiftOf(driverID).setStatus(to, now, s.shiftCfg)
+	if to != DriverStatusOffline {
+		d.lastHeartbeat = now
+	}
+	switch {
+	case to == DriverStatusOffline && isOnTrip(from):
+		// The driver is still on the trip, it's resumed when the heartbeats come back
+		d.tripStatus = from
+	case to == DriverStatusAvailable || to == DriverStatusOffline:
+		// The trip is over (or was never started), riders stop following the driver
+		d.trip, d.tripStatus = nil, ""
+	default:
+		d.tripStatus = ""
+	}
+
+	log.Printf("Driver %s: %s -> %s", driverID, from, to)
+
+	// Drivers done with a trip past their limits go on a break instead
+	if to == DriverStatusAvailable {
+		s.enforceShift(driverID, now)
+	}
+
+	return nil
+}
+
+// Heartbeat keeps the driver online and returns its status.
+// Drivers that went offline because of missed heartbeats come back on their trip, as available,
+// or on a break if they were on a required break.
+func (s *Service) Heartbeat(driverID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.drivers[driverID]
+	if !ok {
+		return "", fmt.Errorf("driver %s is not registered", driverID)
+	}
+
+	d.lastHeartbeat = time.Now()
+
+	if d.Driver.Status == DriverStatusOffline {
+		to := d.tripStatus
+		if to == "" {
+			to = s.resumeStatus(driverID, d.lastHeartbeat)
+		}
+		if err := s.transition(driverID, to); err != nil {
+			return "", err
+		}
+	}
+
+	return d.Driver.Status, nil
+}
+
+// SweepOffline marks the drivers without a heartbeat within the timeout as offline,
+// ex. when the gateway holding their WebSocket died without unregistering them.
+func (s *Service) SweepOffline(ctx context.Context, timeout, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for id, d := range s.drivers {
+				if d.Driver.Status != DriverStatusOffline && now.Sub(d.lastHeartbeat) > timeout {
+					log.Printf("Driver %s missed its heartbeats", id)
+					_ = s.transition(id, DriverStatusOffline)
+				}
+			}
+			for id, p := range s.presences {
+				if now.Sub(p.lastHeartbeat) > timeout && p.expired() {
+					delete(s.presences, id)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// driverPresence is what an unregistered driver was doing, a reconnecting driver gets it back
+type driverPresence struct {
+	// status is empty when the driver resumes like a new one, available or on its required break
+	status        string
+	location      *pb.Location
+	trip          *tripPb.Trip
+	lastHeartbeat time.Time
+}
+
+func newDriverPresence(d *driverInMap) *driverPresence {
+	p := &driverPresence{
+		status:        d.Driver.Status,
+		location:      d.Driver.Location,
+		trip:          d.trip,
+		lastHeartbeat: d.lastHeartbeat,
+	}
+
+	switch p.status {
+	case DriverStatusOffline:
+		p.status = d.tripStatus
+	case DriverStatusOffered:
+		// The dispatch moves the offer on meanwhile
+		p.status = ""
+	}
+
+	return p
+}
+
+// expired tells whether there is nothing left to resume once the driver missed its heartbeats.
+// Like with SweepOffline, only the trips are resumed.
+func (p *driverPresence) expired() bool {
+	return !isOnTrip(p.status)
+}
+
+// releaseTrip ends the trip the unregistered driver was on
+func (p *driverPresence) releaseTrip(tripID string) bool {
+	if p == nil || p.trip.GetId() != tripID {
+		return false
+	}
+
+	p.trip = nil
+	if isOnTrip(p.status) {
+		p.status = ""
+	}
+
+	return true
+}
+
+// isMatchable tells whether the driver can be offered a trip
+func isMatchable(d *driverInMap) bool {
+	return d.Driver.Status == DriverStatusAvailable
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tripPb "ride-sharing/shared/proto/trip"
+)
+
+func newTestDriver(t *testing.T) *Service {
+	t.Helper()
+
+	s := newService([][][]float64{{{40.7128, -74.0060}}}, nil, false, shiftConfig{location: time.UTC})
+	s.register("driver", "sedan", nil)
+
+	return s
+}
+
+func driverStatus(t *testing.T, s *Service) string {
+	t.Helper()
+
+	d, ok := s.GetDriver("driver")
+	if !ok {
+		t.Fatal("driver not registered")
+	}
+
+	return d.Status
+}
+
+func newPresenceTestService(t *testing.T) *Service {
+	t.Helper()
+
+	s := newTestDriver(t)
+
+	// The driver accepted the trip of the rider
+	if err := s.TransitionDriver("driver", DriverStatusOffered); err != nil {
+		t.Fatalf("TransitionDriver() error = %v", err)
+	}
+	if !s.TransitionDriverFrom("driver", DriverStatusOffered, DriverStatusEnRoute) {
+		t.Fatal("TransitionDriverFrom() failed")
+	}
+	s.AssignTrip("driver", &tripPb.Trip{Id: "trip", UserID: "rider"})
+
+	return s
+}
+
+func assertStatus(t *testing.T, s *Service, want string) {
+	t.Helper()
+
+	if status := driverStatus(t, s); status != want {
+		t.Errorf("status = %s, want %s", status, want)
+	}
+}
+
+// goOffline does what SweepOffline does with the drivers which missed their heartbeats
+func goOffline(t *testing.T, s *Service) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.transition("driver", DriverStatusOffline); err != nil {
+		t.Fatalf("transition() error = %v", err)
+	}
+}
+
+func TestTransitionDriver(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    []string
+		wantErr error
+	}{
+		{
+			name: "trip workflow",
+			path: []string{DriverStatusOffered, DriverStatusEnRoute, DriverStatusOnTrip, DriverStatusAvailable},
+		},
+		{
+			name: "offer declined",
+			path: []string{DriverStatusOffered, DriverStatusAvailable},
+		},
+		{
+			name: "break",
+			path: []string{DriverStatusBreak, DriverStatusAvailable},
+		},
+		{
+			name: "offline from any status",
+			path: []string{DriverStatusOffered, DriverStatusOffline, DriverStatusAvailable},
+		},
+		{
+			name:    "on a trip without an offer",
+			path:    []string{DriverStatusOnTrip},
+			wantErr: ErrInvalidStatusTransition,
+		},
+		{
+			name:    "offered on a break",
+			path:    []string{DriverStatusBreak, DriverStatusOffered},
+			wantErr: ErrInvalidStatusTransition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestDriver(t)
+
+			var err error
+			for _, to := range tt.path {
+				if err = s.TransitionDriver("driver", to); err != nil {
+					break
+				}
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TransitionDriver() error = %v, want %v", err, tt.wantErr)
+			}
+			if want := tt.path[len(tt.path)-1]; tt.wantErr == nil && driverStatus(t, s) != want {
+				t.Errorf("status = %s, want %s", driverStatus(t, s), want)
+			}
+		})
+	}
+}
+
+func TestSetClientStatus(t *testing.T) {
+	s := newTestDriver(t)
+
+	// The offers come from the dispatch, not from the drivers
+	if err := s.SetClientStatus("driver", DriverStatusOffered); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("SetClientStatus(%s) error = %v, want %v", DriverStatusOffered, err, ErrInvalidStatusTransition)
+	}
+	if err := s.SetClientStatus("driver", DriverStatusBreak); err != nil {
+		t.Errorf("SetClientStatus(%s) error = %v", DriverStatusBreak, err)
+	}
+	if status := driverStatus(t, s); status != DriverStatusBreak {
+		t.Errorf("status = %s, want %s", status, DriverStatusBreak)
+	}
+}
+
+func TestSweepOffline(t *testing.T) {
+	s := newTestDriver(t)
+
+	s.mu.Lock()
+	s.drivers["driver"].lastHeartbeat = time.Now().Add(-time.Minute)
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.SweepOffline(ctx, 30*time.Second, time.Millisecond)
+
+	if status := driverStatus(t, s); status != DriverStatusOffline {
+		t.Fatalf("status = %s, want %s", status, DriverStatusOffline)
+	}
+
+	// The driver is back
+	status, err := s.Heartbeat("driver")
+	if err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if status != DriverStatusAvailable {
+		t.Errorf("Heartbeat() status = %s, want %s", status, DriverStatusAvailable)
+	}
+}
+
+func TestSetClientStatusOnTrip(t *testing.T) {
+	s := newPresenceTestService(t)
+
+	for _, to := range []string{DriverStatusAvailable, DriverStatusBreak} {
+		if err := s.SetClientStatus("driver", to); !errors.Is(err, ErrInvalidStatusTransition) {
+			t.Errorf("SetClientStatus(%s) en route error = %v, want %v", to, err, ErrInvalidStatusTransition)
+		}
+	}
+
+	// Drivers report the pickup themselves
+	if err := s.SetClientStatus("driver", DriverStatusOnTrip); err != nil {
+		t.Fatalf("SetClientStatus(%s) error = %v", DriverStatusOnTrip, err)
+	}
+	if err := s.SetClientStatus("driver", DriverStatusAvailable); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("SetClientStatus(%s) on trip error = %v, want %v", DriverStatusAvailable, err, ErrInvalidStatusTransition)
+	}
+	assertStatus(t, s, DriverStatusOnTrip)
+
+	// The trip service completed the trip
+	if !s.ReleaseTrip("driver", "trip") {
+		t.Fatal("ReleaseTrip() failed")
+	}
+	assertStatus(t, s, DriverStatusAvailable)
+	if err := s.SetClientStatus("driver", DriverStatusBreak); err != nil {
+		t.Errorf("SetClientStatus(%s) after the trip error = %v", DriverStatusBreak, err)
+	}
+}
+
+func TestHeartbeatResumesTrip(t *testing.T) {
+	s := newPresenceTestService(t)
+	if err := s.SetClientStatus("driver", DriverStatusOnTrip); err != nil {
+		t.Fatalf("SetClientStatus() error = %v", err)
+	}
+
+	goOffline(t, s)
+	if err := s.SetClientStatus("driver", DriverStatusAvailable); !errors.Is(err, ErrInvalidStatusTransition) {
+		t.Errorf("SetClientStatus() offline on a trip error = %v, want %v", err, ErrInvalidStatusTransition)
+	}
+
+	status, err := s.Heartbeat("driver")
+	if err != nil {
+		t.Fatalf("Heartbeat() error = %v", err)
+	}
+	if status != DriverStatusOnTrip {
+		t.Errorf("Heartbeat() status = %s, want %s", status, DriverStatusOnTrip)
+	}
+	if _, riderID, _ := s.UpdateDriverLocation("driver", 40.72, -74.00); riderID != "rider" {
+		t.Errorf("rider following the driver = %q, want rider", riderID)
+	}
+
+	// The trip completed while the driver was offline
+	goOffline(t, s)
+	if !s.ReleaseTrip("driver", "trip") {
+		t.Fatal("ReleaseTrip() failed")
+	}
+	assertStatus(t, s, DriverStatusOffline)
+
+	if status, _ := s.Heartbeat("driver"); status != DriverStatusAvailable {
+		t.Errorf("Heartbeat() status = %s, want %s", status, DriverStatusAvailable)
+	}
+	if _, riderID, _ := s.UpdateDriverLocation("driver", 40.72, -74.00); riderID != "" {
+		t.Errorf("rider following the driver = %q, want none", riderID)
+	}
+}
+
+func TestRegisterResumesPresence(t *testing.T) {
+	s := newPresenceTestService(t)
+	if _, _, err := s.UpdateDriverLocation("driver", 40.72, -74.00); err != nil {
+		t.Fatalf("UpdateDriverLocation() error = %v", err)
+	}
+
+	// The WebSocket of the driver dropped and reconnected
+	s.UnregisterDriver("driver")
+	driver := s.register("driver", "sedan", nil)
+
+	if driver.Status != DriverStatusEnRoute {
+		t.Errorf("status = %s, want %s", driver.Status, DriverStatusEnRoute)
+	}
+	if loc := driver.GetLocation(); loc.GetLatitude() != 40.72 || loc.GetLongitude() != -74.00 {
+		t.Errorf("location = %v, want the one before the reconnection", loc)
+	}
+	if _, riderID, _ := s.UpdateDriverLocation("driver", 40.73, -74.00); riderID != "rider" {
+		t.Errorf("rider following the driver = %q, want rider", riderID)
+	}
+}
+
+func TestRegisterAfterTripEnded(t *testing.T) {
+	s := newPresenceTestService(t)
+
+	// The trip completed while the driver was disconnected
+	s.UnregisterDriver("driver")
+	if !s.ReleaseTrip("driver", "trip") {
+		t.Fatal("ReleaseTrip() failed")
+	}
+
+	if driver := s.register("driver", "sedan", nil); driver.Status != DriverStatusAvailable {
+		t.Errorf("status = %s, want %s", driver.Status, DriverStatusAvailable)
+	}
+	if _, riderID, _ := s.UpdateDriverLocation("driver", 40.72, -74.00); riderID != "" {
+		t.Errorf("rider following the driver = %q, want none", riderID)
+	}
+}
+
+func TestSweepOfflinePresences(t *testing.T) {
+	s := newService([][][]float64{{{40.7128, -74.0060}}}, nil, false, shiftConfig{location: time.UTC})
+	for _, id := range []string{"available", "on_trip"} {
+		s.register(id, "sedan", nil)
+		if _, _, err := s.UpdateDriverLocation(id, 40.72, -74.00); err != nil {
+			t.Fatalf("UpdateDriverLocation() error = %v", err)
+		}
+	}
+	if err := s.TransitionDriver("on_trip", DriverStatusOffered); err != nil {
+		t.Fatalf("TransitionDriver() error = %v", err)
+	}
+	if err := s.TransitionDriver("on_trip", DriverStatusEnRoute); err != nil {
+		t.Fatalf("TransitionDriver() error = %v", err)
+	}
+	s.AssignTrip("on_trip", &tripPb.Trip{Id: "trip", UserID: "rider"})
+
+	for _, id := range []string{"available", "on_trip"} {
+		s.UnregisterDriver(id)
+		s.presences[id].lastHeartbeat = time.Now().Add(-time.Minute)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.SweepOffline(ctx, 30*time.Second, time.Millisecond)
+
+	// Only the trip is kept once the heartbeats timed out
+	if driver := s.register("available", "sedan", nil); driver.GetLocation().GetLatitude() != 40.7128 {
+		t.Errorf("location = %v, want the starting one", driver.GetLocation())
+	}
+	if driver := s.register("on_trip", "sedan", nil); driver.Status != DriverStatusEnRoute {
+		t.Errorf("status = %s, want %s", driver.Status, DriverStatusEnRoute)
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	pb "ride-sharing/shared/proto/driver"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	ErrProfileNotFound      = errors.New("driver profile not found")
+	ErrProfileAlreadyExists = errors.New("driver profile already exists")
+	ErrInvalidProfile       = errors.New("invalid driver profile")
+	// ErrPackageNotAllowed is returned when a driver goes online for a package its vehicle doesn't qualify for
+	ErrPackageNotAllowed = errors.New("the vehicle doesn't qualify for the package")
+)
+
+// PackageSlugs are the ride packages a vehicle can qualify for
+var PackageSlugs = []string{"sedan", "suv", "van", "luxury", "pool"}
+
+// maxVehicleSeats is way more than any car, it catches typos
+const maxVehicleSeats = 8
+
+// ProfileStore keeps the driver profiles in memory and, when it has a file, saves them on every change
+// so they survive restarts
+type ProfileStore struct {
+	path string
+
+	mu       sync.RWMutex
+	profiles map[string]*pb.DriverProfile
+}
+
+// NewProfileStore loads the profiles of the file, if it exists. Without a path the profiles are only kept in memory.
+func NewProfileStore(path string) (*ProfileStore, error) {
+	ps := &ProfileStore{
+		path:     path,
+		profiles: make(map[string]*pb.DriverProfile),
+	}
+
+	if path == "" {
+		return ps, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ps, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the driver profiles: %w", err)
+	}
+
+	list := new(pb.ListDriverProfilesResponse)
+	if err := protojson.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("failed to parse the driver profiles of %s: %w", path, err)
+	}
+
+	for _, profile := range list.Profiles {
+		if err := validateProfile(profile); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		ps.profiles[profile.DriverID] = profile
+	}
+
+	log.Printf("Loaded %d driver profiles", len(ps.profiles))
+
+	return ps, nil
+}
+
+func (ps *ProfileStore) Create(profile *pb.DriverProfile) (*pb.DriverProfile, error) {
+	if err := validateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.profiles[profile.DriverID]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileAlreadyExists, profile.DriverID)
+	}
+
+	// Ratings only come from the riders
+	profile = proto.Clone(profile).(*pb.DriverProfile)
+	profile.Rating, profile.RatingCount = 0, 0
+
+	return ps.put(profile)
+}
+
+func (ps *ProfileStore) Get(driverID string) (*pb.DriverProfile, error) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	profile, ok := ps.profiles[driverID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, driverID)
+	}
+
+	return proto.Clone(profile).(*pb.DriverProfile), nil
+}
+
+// Update replaces the profile
+func (ps *ProfileStore) Update(profile *pb.DriverProfile) (*pb.DriverProfile, error) {
+	if err := validateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	existing, ok := ps.profiles[profile.DriverID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProfileNotFound, profile.DriverID)
+	}
+
+	// Ratings only come from the riders
+	profile = proto.Clone(profile).(*pb.DriverProfile)
+	profile.Rating, profile.RatingCount = existing.Rating, existing.RatingCount
+
+	return ps.put(profile)
+}
+
+// SetRating records the rating summary of the driver, drivers without a profile are ignored
+func (ps *ProfileStore) SetRating(driverID string, average float64, count int32) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	existing, ok := ps.profiles[driverID]
+	if !ok {
+		return nil
+	}
+
+	profile := proto.Clone(existing).(*pb.DriverProfile)
+	profile.Rating, profile.RatingCount = average, count
+
+	_, err := ps.put(profile)
+	return err
+}
+
+func (ps *ProfileStore) Delete(driverID string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	profile, ok := ps.profiles[driverID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrProfileNotFound, driverID)
+	}
+
+	delete(ps.profiles, driverID)
+	if err := ps.save(); err != nil {
+		ps.profiles[driverID] = profile
+		return err
+	}
+
+	return nil
+}
+
+// List returns the profiles sorted by driver ID
+func (ps *ProfileStore) List() []*pb.DriverProfile {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	return ps.sorted(true)
+}
+
+// put must be called with the mutex held
+func (ps *ProfileStore) put(profile *pb.DriverProfile) (*pb.DriverProfile, error) {
+	previous, existed := ps.profiles[profile.DriverID]
+
+	ps.profiles[profile.DriverID] = proto.Clone(profile).(*pb.DriverProfile)
+	if err := ps.save(); err != nil {
+		if existed {
+			ps.profiles[profile.DriverID] = previous
+		} else {
+			delete(ps.profiles, profile.DriverID)
+		}
+		return nil, err
+	}
+
+	return proto.Clone(profile).(*pb.DriverProfile), nil
+}
+
+// save writes the profiles to a temporary file first, so a crash never leaves a truncated file.
+// It must be called with the mutex held.
+func (ps *ProfileStore) save() error {
+	if ps.path == "" {
+		return nil
+	}
+
+	data, err := protojson.MarshalOptions{Multiline: true}.Marshal(
+		&pb.ListDriverProfilesResponse{Profiles: ps.sorted(false)},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the driver profiles: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(ps.path), ".driver-profiles-*")
+	if err != nil {
+		return fmt.Errorf("failed to save the driver profiles: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save the driver profiles: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save the driver profiles: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), ps.path); err != nil {
+		return fmt.Errorf("failed to save the driver profiles: %w", err)
+	}
+
+	return nil
+}
+
+// sorted must be called with the mutex held
+func (ps *ProfileStore) sorted(clone bool) []*pb.DriverProfile {
+	profiles := make([]*pb.DriverProfile, 0, len(ps.profiles))
+	for _, profile := range ps.profiles {
+		if clone {
+			profile = proto.Clone(profile).(*pb.DriverProfile)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	slices.SortFunc(profiles, func(a, b *pb.DriverProfile) int {
+		return strings.Compare(a.DriverID, b.DriverID)
+	})
+
+	return profiles
+}
+
+func validateProfile(profile *pb.DriverProfile) error {
+	if profile == nil {
+		return fmt.Errorf("%w: the profile is required", ErrInvalidProfile)
+	}
+
+	switch {
+	case strings.TrimSpace(profile.DriverID) == "":
+		return fmt.Errorf("%w: the driver ID is required", ErrInvalidProfile)
+	case strings.TrimSpace(profile.Name) == "":
+		return fmt.Errorf("%w: the name of driver %s is required", ErrInvalidProfile, profile.DriverID)
+	case strings.TrimSpace(profile.LicenseNumber) == "":
+		return fmt.Errorf("%w: the license number of driver %s is required", ErrInvalidProfile, profile.DriverID)
+	}
+
+	vehicle := profile.Vehicle
+	switch {
+	case vehicle == nil:
+		return fmt.Errorf("%w: the vehicle of driver %s is required", ErrInvalidProfile, profile.DriverID)
+	case strings.TrimSpace(vehicle.Plate) == "":
+		return fmt.Errorf("%w: the plate of driver %s is required", ErrInvalidProfile, profile.DriverID)
+	case vehicle.SeatCapacity < 1 || vehicle.SeatCapacity > maxVehicleSeats:
+		return fmt.Errorf(
+			"%w: the vehicle of driver %s has %d seats, between 1 and %d are expected",
+			ErrInvalidProfile, profile.DriverID, vehicle.SeatCapacity, maxVehicleSeats,
+		)
+	case len(vehicle.PackageSlugs) == 0:
+		return fmt.Errorf("%w: the vehicle of driver %s has no packages", ErrInvalidProfile, profile.DriverID)
+	}
+
+	for _, slug := range vehicle.PackageSlugs {
+		if !slices.Contains(PackageSlugs, slug) {
+			return fmt.Errorf("%w: unknown package %q", ErrInvalidProfile, slug)
+		}
+		if vehicle.SeatCapacity < SeatCapacity(slug) {
+			return fmt.Errorf(
+				"%w: %d seats are not enough for %s, %d are required",
+				ErrInvalidProfile, vehicle.SeatCapacity, slug, SeatCapacity(slug),
+			)
+		}
+	}
+
+	return nil
+}
+
+// checkPackage tells whether the driver can go online for the package
+func checkPackage(profile *pb.DriverProfile, packageSlug string) error {
+	if !slices.Contains(profile.GetVehicle().GetPackageSlugs(), packageSlug) {
+		return fmt.Errorf(
+			"%w: driver %s can drive %s, not %s",
+			ErrPackageNotAllowed, profile.DriverID, strings.Join(profile.GetVehicle().GetPackageSlugs(), ", "), packageSlug,
+		)
+	}
+
+	return nil
+}
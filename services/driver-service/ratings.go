@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// rateeRoleDriver marks the ratings about drivers, the trip service keeps the ratings and their averages
+const rateeRoleDriver = "driver"
+
+type driverRating struct {
+	average float64
+	count   int32
+}
+
+// SetDriverRating records the rating summary of the driver, for the matching and the riders to see
+func (s *Service) SetDriverRating(driverID string, average float64, count int32) error {
+	s.mu.Lock()
+	s.ratings[driverID] = driverRating{average: average, count: count}
+	if d, ok := s.drivers[driverID]; ok {
+		d.Driver.Rating = average
+		d.Driver.RatingCount = count
+	}
+	s.mu.Unlock()
+
+	return s.profiles.SetRating(driverID, average, count)
+}
+
+type ratingConsumer struct {
+	rabbitMQ *messaging.RabbitMQ
+	service  *Service
+}
+
+func NewRatingConsumer(rabbitMQ *messaging.RabbitMQ, service *Service) Consumer {
+	return &ratingConsumer{
+		rabbitMQ: rabbitMQ,
+		service:  service,
+	}
+}
+
+func (c *ratingConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.DriverRatingsQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.TripRatedData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the rating event: %v", err)
+			}
+
+			// Riders are rated too, they're none of our business
+			if payload.Rating.GetRateeRole() != rateeRoleDriver {
+				return nil
+			}
+
+			summary := payload.Summary
+			log.Printf("Driver %s is rated %.2f over %d trips", summary.GetUserID(), summary.GetAverage(), summary.GetCount())
+
+			return c.service.SetDriverRating(summary.GetUserID(), summary.GetAverage(), summary.GetCount())
+		},
+	)
+}
@@ -1,71 +1,276 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	math "math/rand/v2"
 	"sync"
+	"time"
 
 	pb "ride-sharing/shared/proto/driver"
+	tripPb "ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/util"
 
 	"github.com/mmcloughlin/geohash"
+	"google.golang.org/protobuf/proto"
 )
 
 type driverInMap struct {
 	Driver *pb.Driver
-	// Index int
-	// TODO: route
+
+	lastHeartbeat time.Time
+	// trip is the trip the driver accepted, its rider follows the driver on the map
+	trip *tripPb.Trip
+	// tripStatus is the status of the driver on its trip when it went offline, it's resumed with the heartbeats
+	tripStatus string
+
+	// The route the driver follows in simulation mode, as [lat, lng] points.
+	// routeIndex is the point the driver last passed and routeOffset the meters driven since.
+	route       [][]float64
+	routeIndex  int
+	routeOffset float64
+	// routeLeg tells what the route leads to, see the simulator
+	routeLeg string
 }
 
 type Service struct {
-	drivers []*driverInMap
+	drivers map[string]*driverInMap
+	index   *spatialIndex
 	mu      sync.RWMutex
+	// routes the drivers start on and patrol in simulation mode, as [lat, lng] points
+	routes [][][]float64
+
+	watchers *locationWatchers
+
+	// ratings are the latest rating summaries of the drivers, kept for the drivers without a profile
+	ratings map[string]driverRating
+
+	profiles *ProfileStore
+	// requireProfiles refuses the drivers without a profile, otherwise they get a made up demo profile.
+	// It's only turned off in simulation mode.
+	requireProfiles bool
+
+	// shifts are kept across registrations, the driving time limits apply per driver and not per connection
+	shifts   map[string]*shift
+	shiftCfg shiftConfig
+
+	// presences are the statuses of the unregistered drivers, resumed when they register again
+	presences map[string]*driverPresence
 }
 
-func newService() *Service {
+func newService(
+	routes [][][]float64,
+	profiles *ProfileStore,
+	requireProfiles bool,
+	shiftCfg shiftConfig,
+) *Service {
 	return &Service{
-		drivers: make([]*driverInMap, 0),
+		drivers:         make(map[string]*driverInMap),
+		index:           newSpatialIndex(spatialIndexPrecision),
+		routes:          routes,
+		watchers:        newLocationWatchers(),
+		ratings:         make(map[string]driverRating),
+		profiles:        profiles,
+		requireProfiles: requireProfiles,
+		shifts:          make(map[string]*shift),
+		shiftCfg:        shiftCfg,
+		presences:       make(map[string]*driverPresence),
 	}
 }
 
+// RegisterDriver puts the driver online for the package, which its vehicle has to qualify for
 func (s *Service) RegisterDriver(driverID string, packageSlug string) (*pb.Driver, error) {
+	profile, err := s.profiles.Get(driverID)
+	switch {
+	case err == nil:
+		if err := checkPackage(profile, packageSlug); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, ErrProfileNotFound) && !s.requireProfiles:
+		profile = nil
+	default:
+		return nil, err
+	}
+
+	return s.register(driverID, packageSlug, profile), nil
+}
+
+// register puts the driver online, drivers without a profile are made up
+func (s *Service) register(driverID, packageSlug string, profile *pb.DriverProfile) *pb.Driver {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	randomIndex := math.IntN(len(PredefinedRoutes))
-	randomRoute := PredefinedRoutes[randomIndex]
+	randomIndex := math.IntN(len(s.routes))
+	randomRoute := s.routes[randomIndex]
 
-	randomPlate := GenerateRandomPlate()
-	randomAvatar := util.GetRandomAvatar(randomIndex)
+	location := &pb.Location{Latitude: randomRoute[0][0], Longitude: randomRoute[0][1]}
 
-	// we can ignore this property for now, but it must be sent to the frontend.
-	geohash := geohash.Encode(randomRoute[0][0], randomRoute[0][1])
+	// Drivers reconnecting during a required break resume it, the others resume where they left off
+	now := time.Now()
+	status := s.resumeStatus(driverID, now)
+	presence, resumed := s.presences[driverID]
+	if resumed {
+		delete(s.presences, driverID)
+		location = presence.location
+		if presence.status != "" {
+			status = presence.status
+		}
+	}
 
 	driver := &pb.Driver{
-		Id:             driverID,
-		Geohash:        geohash,
-		Location:       &pb.Location{Latitude: randomRoute[0][0], Longitude: randomRoute[0][1]},
-		Name:           "Lando Norris",
-		PackageSlug:    packageSlug,
-		ProfilePicture: randomAvatar,
-		CarPlate:       randomPlate,
+		Id: driverID,
+		// The geohash is sent to the frontend and keys the driver in the spatial index
+		Geohash:     geohash.Encode(location.Latitude, location.Longitude),
+		Location:    location,
+		PackageSlug: packageSlug,
+		Status:      status,
 	}
 
-	s.drivers = append(s.drivers, &driverInMap{Driver: driver})
+	if profile != nil {
+		driver.Name = profile.Name
+		driver.ProfilePicture = profile.ProfilePicture
+		driver.CarPlate = profile.Vehicle.Plate
+		driver.SeatCapacity = profile.Vehicle.SeatCapacity
+		driver.Vehicle = profile.Vehicle
+		driver.Rating = profile.Rating
+		driver.RatingCount = profile.RatingCount
+	} else {
+		driver.Name = "Lando Norris"
+		driver.ProfilePicture = util.GetRandomAvatar(randomIndex)
+		driver.CarPlate = GenerateRandomPlate()
+		driver.SeatCapacity = SeatCapacity(packageSlug)
+		if rating, ok := s.ratings[driverID]; ok {
+			driver.Rating = rating.average
+			driver.RatingCount = rating.count
+		}
+	}
+
+	d := &driverInMap{
+		Driver:        driver,
+		lastHeartbeat: time.Now(),
+		route:         randomRoute,
+		routeLeg:      routeLegPatrol,
+	}
+	if resumed {
+		d.trip = presence.trip
+	}
+	s.drivers[driverID] = d
+	s.index.upsert(d)
 
-	return driver, nil
+	s.shiftOf(driverID).setStatus(status, now, s.shiftCfg)
+	s.enforceShift(driverID, now)
+
+	return proto.Clone(driver).(*pb.Driver)
 }
 
 func (s *Service) UnregisterDriver(driverID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	filtered := s.drivers[:0] // reuse underlying array
+	if d, ok := s.drivers[driverID]; ok {
+		s.shiftOf(driverID).setStatus(DriverStatusOffline, time.Now(), s.shiftCfg)
+		s.presences[driverID] = newDriverPresence(d)
+	}
+
+	delete(s.drivers, driverID)
+	s.index.remove(driverID)
+	s.watchers.closeAll(driverID)
+}
+
+// GetDriver returns a copy of the registered driver
+func (s *Service) GetDriver(driverID string) (*pb.Driver, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.drivers[driverID]
+	if !ok {
+		return nil, false
+	}
+
+	return proto.Clone(d.Driver).(*pb.Driver), true
+}
+
+// UpdateDriverLocation moves the driver, keeping the geohash and the spatial index up to date.
+// It returns the moved driver along with the rider assigned to it, if any.
+func (s *Service) UpdateDriverLocation(driverID string, lat, lng float64) (*pb.Driver, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.drivers[driverID]
+	if !ok {
+		return nil, "", fmt.Errorf("driver %s is not registered", driverID)
+	}
+
+	d.Driver.Location = &pb.Location{Latitude: lat, Longitude: lng}
+	d.Driver.Geohash = geohash.Encode(lat, lng)
+	s.index.upsert(d)
+
+	driver := proto.Clone(d.Driver).(*pb.Driver)
+	s.watchers.notify(driver)
+
+	return driver, d.trip.GetUserID(), nil
+}
+
+// AssignTrip links the driver to the trip it accepted
+func (s *Service) AssignTrip(driverID string, trip *tripPb.Trip) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d, ok := s.drivers[driverID]; ok {
+		d.trip = trip
+	}
+}
+
+// ReleaseTrip makes the driver available again if it's still heading to, or driving, the trip.
+// Drivers who went offline on the trip stay offline and don't resume it.
+func (s *Service) ReleaseTrip(driverID, tripID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.drivers[driverID]
+	if !ok {
+		return s.presences[driverID].releaseTrip(tripID)
+	}
+	if d.trip.GetId() != tripID {
+		return false
+	}
+	if d.Driver.Status == DriverStatusOffline {
+		d.trip, d.tripStatus = nil, ""
+		return true
+	}
+	if !isOnTrip(d.Driver.Status) {
+		return false
+	}
+
+	return s.transition(driverID, DriverStatusAvailable) == nil
+}
+
+// DriverMatch is a driver found around a point
+type DriverMatch struct {
+	Driver   *pb.Driver
+	Distance float64 // meters
+}
+
+// FindNearbyDrivers returns the available drivers of the package within the radius, closest first
+func (s *Service) FindNearbyDrivers(
+	lat, lng float64,
+	radiusMeters float64,
+	packageSlug string,
+	limit int,
+) []*DriverMatch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	nearby := s.index.nearby(lat, lng, radiusMeters, packageSlug, limit, isMatchable)
 
-	for _, d := range s.drivers {
-		if d.Driver.Id != driverID {
-			filtered = append(filtered, d)
+	matches := make([]*DriverMatch, len(nearby))
+	for i, n := range nearby {
+		// Copies, the drivers keep moving once the lock is released
+		matches[i] = &DriverMatch{
+			Driver:   proto.Clone(n.driver.Driver).(*pb.Driver),
+			Distance: n.distance,
 		}
 	}
 
-	s.drivers = filtered
+	return matches
 }
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"ride-sharing/shared/env"
+	pb "ride-sharing/shared/proto/driver"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Reasons of the required breaks
+const (
+	BreakReasonContinuous = "continuous_limit"
+	BreakReasonDaily      = "daily_limit"
+)
+
+var (
+	ErrBreakRequired = errors.New("the driver is on a required break")
+	ErrShiftNotFound = errors.New("shift not found")
+)
+
+// shiftConfig are the driving time limits, a zero limit is disabled
+type shiftConfig struct {
+	// maxContinuous is the online time allowed without a break of at least minBreak
+	maxContinuous time.Duration
+	minBreak      time.Duration
+	// maxDaily is the online time allowed per day, the drivers are on a break until the next day past it
+	maxDaily time.Duration
+	// location is where the days start and end
+	location *time.Location
+}
+
+func shiftConfigFromEnv() (shiftConfig, error) {
+	location, err := loadTimezone("DRIVER_SHIFT_TIMEZONE")
+	if err != nil {
+		return shiftConfig{}, err
+	}
+
+	return shiftConfig{
+		maxContinuous: env.GetDuration("DRIVER_MAX_CONTINUOUS_DRIVING", 4*time.Hour+30*time.Minute),
+		minBreak:      env.GetDuration("DRIVER_MIN_BREAK", 45*time.Minute),
+		maxDaily:      env.GetDuration("DRIVER_MAX_DAILY_DRIVING", 10*time.Hour),
+		location:      location,
+	}, nil
+}
+
+// shift is the working time of a driver. It's kept once the driver goes offline,
+// so reconnecting doesn't start the counters over.
+type shift struct {
+	status string
+	// since is when the counters were last brought up to date
+	since time.Time
+	// day is the start of the day the counters are for
+	day time.Time
+	// startedAt is when the driver first went online that day
+	startedAt time.Time
+
+	online     time.Duration
+	onTrip     time.Duration
+	continuous time.Duration
+
+	// restStartedAt is when the driver last went offline or on a break, zero when it never worked
+	restStartedAt time.Time
+	breakUntil    time.Time
+	breakReason   string
+}
+
+// isWorking tells whether the time spent in the status counts as online time
+func isWorking(status string) bool {
+	return status != DriverStatusOffline && status != DriverStatusBreak
+}
+
+func isOnTrip(status string) bool {
+	return status == DriverStatusEnRoute || status == DriverStatusOnTrip
+}
+
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+func newShift(now time.Time, loc *time.Location) *shift {
+	return &shift{
+		status: DriverStatusOffline,
+		since:  now,
+		day:    startOfDay(now, loc),
+	}
+}
+
+// advance brings the counters up to now, the daily counters start over at midnight
+func (sh *shift) advance(now time.Time) {
+	for {
+		nextDay := sh.day.AddDate(0, 0, 1)
+
+		end := now
+		if !now.Before(nextDay) {
+			end = nextDay
+		}
+
+		if end.After(sh.since) {
+			elapsed := end.Sub(sh.since)
+			if isWorking(sh.status) {
+				sh.online += elapsed
+				sh.continuous += elapsed
+			}
+			if isOnTrip(sh.status) {
+				sh.onTrip += elapsed
+			}
+			sh.since = end
+		}
+
+		if now.Before(nextDay) {
+			return
+		}
+
+		sh.day = nextDay
+		sh.online = 0
+		sh.onTrip = 0
+		sh.startedAt = time.Time{}
+		if isWorking(sh.status) {
+			sh.startedAt = nextDay
+		}
+	}
+}
+
+// rested tells whether the current rest is long enough to start the continuous time over
+func (sh *shift) rested(now time.Time, cfg shiftConfig) bool {
+	return sh.restStartedAt.IsZero() || now.Sub(sh.restStartedAt) >= cfg.minBreak
+}
+
+func (sh *shift) setStatus(to string, now time.Time, cfg shiftConfig) {
+	sh.advance(now)
+
+	switch {
+	case isWorking(to) && !isWorking(sh.status):
+		if sh.rested(now, cfg) {
+			sh.continuous = 0
+		}
+		if sh.startedAt.IsZero() {
+			sh.startedAt = now
+		}
+		sh.breakUntil = time.Time{}
+		sh.breakReason = ""
+	case !isWorking(to) && isWorking(sh.status):
+		sh.restStartedAt = now
+	}
+
+	sh.status = to
+}
+
+// onRequiredBreak tells whether the driver has to stay on a break
+func (sh *shift) onRequiredBreak(now time.Time) bool {
+	return now.Before(sh.breakUntil)
+}
+
+// requiredBreak returns until when the driver has to take a break, if it went past a limit
+func (sh *shift) requiredBreak(now time.Time, cfg shiftConfig) (time.Time, string, bool) {
+	sh.advance(now)
+
+	if cfg.maxDaily > 0 && sh.online >= cfg.maxDaily {
+		return sh.day.AddDate(0, 0, 1), BreakReasonDaily, true
+	}
+	if cfg.maxContinuous > 0 && sh.continuous >= cfg.maxContinuous {
+		return now.Add(cfg.minBreak), BreakReasonContinuous, true
+	}
+
+	return time.Time{}, "", false
+}
+
+func (sh *shift) toProto(driverID string, now time.Time, cfg shiftConfig) *pb.ShiftSummary {
+	// Copy, the summary doesn't move the counters
+	c := *sh
+	c.advance(now)
+
+	continuous := c.continuous
+	if !isWorking(c.status) && c.rested(now, cfg) {
+		continuous = 0
+	}
+
+	summary := &pb.ShiftSummary{
+		DriverID:             driverID,
+		Status:               c.status,
+		OnlineSeconds:        int64(c.online.Seconds()),
+		OnTripSeconds:        int64(c.onTrip.Seconds()),
+		ContinuousSeconds:    int64(continuous.Seconds()),
+		MaxContinuousSeconds: int64(cfg.maxContinuous.Seconds()),
+		MaxDailySeconds:      int64(cfg.maxDaily.Seconds()),
+	}
+	if !c.startedAt.IsZero() {
+		summary.StartedAt = timestamppb.New(c.startedAt)
+	}
+	if c.onRequiredBreak(now) {
+		summary.BreakUntil = timestamppb.New(c.breakUntil)
+		summary.BreakReason = c.breakReason
+	}
+
+	return summary
+}
+
+// shiftOf returns the shift of the driver, starting one if needed. It must be called with the mutex held.
+func (s *Service) shiftOf(driverID string) *shift {
+	sh, ok := s.shifts[driverID]
+	if !ok {
+		sh = newShift(time.Now(), s.shiftCfg.location)
+		s.shifts[driverID] = sh
+	}
+
+	return sh
+}
+
+// enforceShift sends the available driver on a break when it went past a limit.
+// Drivers are never interrupted during a trip, they're sent on a break once it's over.
+// It must be called with the mutex held.
+func (s *Service) enforceShift(driverID string, now time.Time) {
+	d, ok := s.drivers[driverID]
+	if !ok || d.Driver.Status != DriverStatusAvailable {
+		return
+	}
+
+	sh := s.shiftOf(driverID)
+	until, reason, ok := sh.requiredBreak(now, s.shiftCfg)
+	if !ok {
+		return
+	}
+
+	if err := s.transition(driverID, DriverStatusBreak); err != nil {
+		log.Printf("Failed to send driver %s on a break: %v", driverID, err)
+		return
+	}
+
+	sh.breakUntil = until
+	sh.breakReason = reason
+
+	log.Printf("Driver %s is on a required break until %s (%s)", driverID, until.Format(time.RFC3339), reason)
+}
+
+// checkShift refuses to put the driver back to work during a required break.
+// It must be called with the mutex held.
+func (s *Service) checkShift(driverID, to string, now time.Time) error {
+	sh := s.shiftOf(driverID)
+	if isWorking(to) && sh.onRequiredBreak(now) {
+		return fmt.Errorf("%w until %s", ErrBreakRequired, sh.breakUntil.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// resumeStatus is the status a driver coming back online starts in.
+// It must be called with the mutex held.
+func (s *Service) resumeStatus(driverID string, now time.Time) string {
+	if s.shiftOf(driverID).onRequiredBreak(now) {
+		return DriverStatusBreak
+	}
+
+	return DriverStatusAvailable
+}
+
+// ShiftSummary returns the working time of the driver today
+func (s *Service) ShiftSummary(driverID string) (*pb.ShiftSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sh, ok := s.shifts[driverID]
+	if !ok {
+		return nil, fmt.Errorf("%w: driver %s", ErrShiftNotFound, driverID)
+	}
+
+	return sh.toProto(driverID, time.Now(), s.shiftCfg), nil
+}
+
+// EnforceShiftLimits sends the available drivers past their limits on a break, and forgets
+// the shifts of the drivers gone offline which have nothing left to enforce.
+func (s *Service) EnforceShiftLimits(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for id := range s.drivers {
+				s.enforceShift(id, now)
+			}
+			for id, sh := range s.shifts {
+				if _, ok := s.drivers[id]; ok {
+					continue
+				}
+
+				sh.advance(now)
+				if sh.online == 0 && !sh.onRequiredBreak(now) && sh.rested(now, s.shiftCfg) {
+					delete(s.shifts, id)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testShiftConfig = shiftConfig{
+	maxContinuous: 4 * time.Hour,
+	minBreak:      30 * time.Minute,
+	maxDaily:      10 * time.Hour,
+	location:      time.UTC,
+}
+
+func TestShiftAdvanceDayRollover(t *testing.T) {
+	start := time.Date(2024, time.March, 13, 22, 0, 0, 0, time.UTC)
+	sh := newShift(start, time.UTC)
+	sh.setStatus(DriverStatusAvailable, start, testShiftConfig)
+	sh.setStatus(DriverStatusOnTrip, start.Add(time.Hour), testShiftConfig)
+
+	// Driving past midnight, until 1am
+	sh.advance(start.Add(3 * time.Hour))
+
+	if want := time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC); !sh.day.Equal(want) {
+		t.Errorf("day = %s, want %s", sh.day, want)
+	}
+	// The daily counters start over at midnight, the continuous time doesn't
+	if sh.online != time.Hour || sh.onTrip != time.Hour {
+		t.Errorf("online %s and on trip %s, want 1h and 1h", sh.online, sh.onTrip)
+	}
+	if sh.continuous != 3*time.Hour {
+		t.Errorf("continuous = %s, want 3h", sh.continuous)
+	}
+	if want := time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC); !sh.startedAt.Equal(want) {
+		t.Errorf("started at %s, want %s", sh.startedAt, want)
+	}
+
+	// Offline over a whole day
+	sh.setStatus(DriverStatusOffline, start.Add(3*time.Hour), testShiftConfig)
+	sh.advance(start.Add(50 * time.Hour))
+	if sh.online != 0 || !sh.startedAt.IsZero() {
+		t.Errorf("online %s since %s, want nothing two days later", sh.online, sh.startedAt)
+	}
+}
+
+func TestShiftContinuousTime(t *testing.T) {
+	start := time.Date(2024, time.March, 13, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		rest           time.Duration
+		wantContinuous time.Duration
+	}{
+		{
+			name:           "short break",
+			rest:           10 * time.Minute,
+			wantContinuous: 3 * time.Hour,
+		},
+		{
+			name:           "long enough break",
+			rest:           30 * time.Minute,
+			wantContinuous: time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := newShift(start, time.UTC)
+			sh.setStatus(DriverStatusAvailable, start, testShiftConfig)
+			sh.setStatus(DriverStatusBreak, start.Add(2*time.Hour), testShiftConfig)
+
+			back := start.Add(2*time.Hour + tt.rest)
+			sh.setStatus(DriverStatusAvailable, back, testShiftConfig)
+			sh.advance(back.Add(time.Hour))
+
+			if sh.continuous != tt.wantContinuous {
+				t.Errorf("continuous = %s, want %s", sh.continuous, tt.wantContinuous)
+			}
+			if sh.online != 3*time.Hour {
+				t.Errorf("online = %s, want 3h", sh.online)
+			}
+		})
+	}
+}
+
+func TestShiftRequiredBreak(t *testing.T) {
+	start := time.Date(2024, time.March, 13, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		cfg        shiftConfig
+		worked     time.Duration
+		wantBreak  bool
+		wantUntil  time.Time
+		wantReason string
+	}{
+		{
+			name:   "under the limits",
+			cfg:    testShiftConfig,
+			worked: 3 * time.Hour,
+		},
+		{
+			name:       "continuous limit",
+			cfg:        testShiftConfig,
+			worked:     4 * time.Hour,
+			wantBreak:  true,
+			wantUntil:  start.Add(4*time.Hour + 30*time.Minute),
+			wantReason: BreakReasonContinuous,
+		},
+		{
+			name:       "daily limit",
+			cfg:        shiftConfig{maxDaily: 2 * time.Hour, location: time.UTC},
+			worked:     2 * time.Hour,
+			wantBreak:  true,
+			wantUntil:  time.Date(2024, time.March, 14, 0, 0, 0, 0, time.UTC),
+			wantReason: BreakReasonDaily,
+		},
+		{
+			name:   "limits disabled",
+			cfg:    shiftConfig{location: time.UTC},
+			worked: 12 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh := newShift(start, time.UTC)
+			sh.setStatus(DriverStatusAvailable, start, tt.cfg)
+
+			until, reason, ok := sh.requiredBreak(start.Add(tt.worked), tt.cfg)
+			if ok != tt.wantBreak || !until.Equal(tt.wantUntil) || reason != tt.wantReason {
+				t.Errorf("requiredBreak() = %s, %q, %v, want %s, %q, %v",
+					until, reason, ok, tt.wantUntil, tt.wantReason, tt.wantBreak)
+			}
+		})
+	}
+}
+
+func TestCheckShift(t *testing.T) {
+	s := newService([][][]float64{{{40.7128, -74.0060}}}, nil, false, testShiftConfig)
+	s.register("driver", "sedan", nil)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sh := s.shiftOf("driver")
+	sh.breakUntil = now.Add(time.Hour)
+	sh.breakReason = BreakReasonContinuous
+
+	if err := s.checkShift("driver", DriverStatusAvailable, now); !errors.Is(err, ErrBreakRequired) {
+		t.Errorf("checkShift() during the break error = %v, want %v", err, ErrBreakRequired)
+	}
+	if err := s.checkShift("driver", DriverStatusOffline, now); err != nil {
+		t.Errorf("checkShift() going offline error = %v", err)
+	}
+	if status := s.resumeStatus("driver", now); status != DriverStatusBreak {
+		t.Errorf("resumeStatus() = %s, want %s", status, DriverStatusBreak)
+	}
+	if status := s.resumeStatus("driver", now.Add(2*time.Hour)); status != DriverStatusAvailable {
+		t.Errorf("resumeStatus() after the break = %s, want %s", status, DriverStatusAvailable)
+	}
+}
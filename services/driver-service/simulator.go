@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	math "math/rand/v2"
+	"slices"
+	"time"
+
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/osrm"
+	pb "ride-sharing/shared/proto/driver"
+	tripPb "ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
+	"ride-sharing/shared/util"
+
+	"github.com/mmcloughlin/geohash"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// What the route of a simulated driver leads to
+const (
+	// routeLegPatrol is a predefined route driven back and forth while waiting for trips
+	routeLegPatrol = "patrol"
+	// routeLegPickup leads to the pickup of the accepted trip
+	routeLegPickup = "pickup"
+	// routeLegDropoff leads to the destination of the trip
+	routeLegDropoff = "dropoff"
+)
+
+type simulationConfig struct {
+	tick     time.Duration
+	speedKmh float64
+	// bots is the number of drivers registered by the simulator itself,
+	// they accept every offer and drive the trips to the end
+	bots int
+}
+
+func simulationConfigFromEnv() simulationConfig {
+	return simulationConfig{
+		tick:     env.GetDuration("DRIVER_SIMULATION_TICK", time.Second),
+		speedKmh: env.GetFloat("DRIVER_SIMULATION_SPEED_KMH", 30),
+		bots:     env.GetInt("DRIVER_SIMULATION_BOTS", 0),
+	}
+}
+
+// Simulator moves the registered drivers along their routes and emits their locations,
+// so the whole flow can be demoed and load tested without real phones.
+type Simulator struct {
+	service        *Service
+	dispatcher     *Dispatcher
+	locationFanout *LocationFanout
+	router         *osrm.Client
+	// tripService completes the trips the bots drove to the end
+	tripService tripPb.TripServiceClient
+	cfg         simulationConfig
+	bots        map[string]bool
+}
+
+func NewSimulator(
+	service *Service,
+	dispatcher *Dispatcher,
+	locationFanout *LocationFanout,
+	router *osrm.Client,
+	tripService tripPb.TripServiceClient,
+	cfg simulationConfig,
+) *Simulator {
+	return &Simulator{
+		service:        service,
+		dispatcher:     dispatcher,
+		locationFanout: locationFanout,
+		router:         router,
+		tripService:    tripService,
+		cfg:            cfg,
+		bots:           make(map[string]bool),
+	}
+}
+
+func (sim *Simulator) Run(ctx context.Context) {
+	for i := range sim.cfg.bots {
+		id := fmt.Sprintf("sim-driver-%d", i+1)
+		// Bots don't need a profile
+		sim.service.register(id, PackageSlugs[i%len(PackageSlugs)], nil)
+		sim.bots[id] = true
+	}
+
+	log.Printf("Simulating the drivers movements, with %d bot drivers", len(sim.bots))
+
+	ticker := time.NewTicker(sim.cfg.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			for id := range sim.bots {
+				sim.service.UnregisterDriver(id)
+			}
+			return
+		case <-ticker.C:
+			sim.step(ctx)
+		}
+	}
+}
+
+func (sim *Simulator) step(ctx context.Context) {
+	for id := range sim.bots {
+		driverStatus, err := sim.service.Heartbeat(id)
+		if err != nil {
+			log.Printf("Failed to keep the simulated driver %s online: %v", id, err)
+			continue
+		}
+
+		// Bots get back to work as soon as their required breaks are over
+		if driverStatus == DriverStatusBreak {
+			_ = sim.service.TransitionDriver(id, DriverStatusAvailable)
+		}
+
+		if tripID, ok := sim.dispatcher.OfferedTrip(id); ok {
+			if err := sim.dispatcher.Accept(ctx, tripID, id); err != nil {
+				log.Printf("Simulated driver %s failed to accept trip %s: %v", id, tripID, err)
+			}
+		}
+	}
+
+	meters := sim.cfg.speedKmh / 3.6 * sim.cfg.tick.Seconds()
+	moves, plans := sim.service.stepSimulation(meters)
+
+	for _, move := range moves {
+		sim.locationFanout.Publish(ctx, move.driver, move.riderID)
+
+		if move.arrived && sim.bots[move.driver.Id] {
+			sim.finishLeg(ctx, move.driver.Id, move.tripID, move.leg)
+		}
+	}
+
+	for _, plan := range plans {
+		sim.service.setRoute(plan.driverID, plan.leg, sim.planRoute(ctx, plan))
+	}
+}
+
+// finishLeg moves the bot to the next step of its trip, the real drivers do it through their app.
+// The trip service completes the trip, the bot is available again with trip.event.completed.
+func (sim *Simulator) finishLeg(ctx context.Context, driverID, tripID, leg string) {
+	switch leg {
+	case routeLegPickup:
+		sim.service.TransitionDriverFrom(driverID, DriverStatusEnRoute, DriverStatusOnTrip)
+	case routeLegDropoff:
+		_, err := sim.tripService.CompleteTrip(ctx, &tripPb.CompleteTripReq{TripID: tripID, DriverID: driverID})
+		if err != nil && status.Code(err) != codes.AlreadyExists {
+			// Parked bots don't try again, they go back to work without the trip
+			log.Printf("Simulated driver %s failed to complete trip %s: %v", driverID, tripID, err)
+			sim.service.ReleaseTrip(driverID, tripID)
+		}
+	}
+}
+
+// planRoute follows the roads when OSRM is reachable and goes straight otherwise
+func (sim *Simulator) planRoute(ctx context.Context, plan routePlan) [][]float64 {
+	if plan.to == nil {
+		// Back to patrolling, from where the driver is
+		patrol := sim.service.routes[math.IntN(len(sim.service.routes))]
+		return append([][]float64{plan.from}, patrol...)
+	}
+
+	res, err := sim.router.Route(ctx, []*types.Coordinate{
+		{Latitude: plan.from[0], Longitude: plan.from[1]},
+		{Latitude: plan.to[0], Longitude: plan.to[1]},
+	})
+	if err != nil {
+		log.Printf("Failed to plan the %s route of driver %s, going straight: %v", plan.leg, plan.driverID, err)
+		return [][]float64{plan.from, plan.to}
+	}
+
+	// GeoJSON coordinates are [lng, lat]
+	coordinates := res.Routes[0].Geometry.Coordinates
+	route := make([][]float64, 0, len(coordinates))
+	for _, c := range coordinates {
+		if len(c) >= 2 {
+			route = append(route, []float64{c[1], c[0]})
+		}
+	}
+	if len(route) < 2 {
+		log.Printf("The %s route of driver %s has no geometry, going straight", plan.leg, plan.driverID)
+		return [][]float64{plan.from, plan.to}
+	}
+
+	return route
+}
+
+// simulatedMove is a driver moved by a simulation step
+type simulatedMove struct {
+	driver  *pb.Driver
+	riderID string
+	tripID  string
+	leg     string
+	// arrived is set when the driver reached the end of its route
+	arrived bool
+}
+
+// routePlan asks for a new route, when the driver status no longer matches its route
+type routePlan struct {
+	driverID string
+	leg      string
+	from     []float64
+	// to is nil for a patrol route
+	to []float64
+}
+
+// stepSimulation moves every driver on the road by the distance
+func (s *Service) stepSimulation(meters float64) ([]simulatedMove, []routePlan) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var moves []simulatedMove
+	var plans []routePlan
+
+	for id, d := range s.drivers {
+		leg, to, ok := d.wantedLeg()
+		if !ok {
+			continue
+		}
+
+		if leg != d.routeLeg {
+			location := d.Driver.GetLocation()
+			plans = append(plans, routePlan{
+				driverID: id,
+				leg:      leg,
+				from:     []float64{location.GetLatitude(), location.GetLongitude()},
+				to:       to,
+			})
+			continue
+		}
+
+		if len(d.route) < 2 || d.routeIndex >= len(d.route)-1 {
+			// Parked at the end of its route
+			continue
+		}
+
+		arrived := d.advance(meters)
+		s.index.upsert(d)
+
+		driver := proto.Clone(d.Driver).(*pb.Driver)
+		s.watchers.notify(driver)
+
+		moves = append(moves, simulatedMove{
+			driver:  driver,
+			riderID: d.trip.GetUserID(),
+			tripID:  d.trip.GetId(),
+			leg:     leg,
+			arrived: arrived,
+		})
+	}
+
+	return moves, plans
+}
+
+// setRoute starts the driver on the route, unless its status changed while the route was planned
+func (s *Service) setRoute(driverID, leg string, route [][]float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.drivers[driverID]
+	if !ok {
+		return
+	}
+
+	if wanted, _, ok := d.wantedLeg(); !ok || wanted != leg {
+		return
+	}
+
+	d.route = route
+	d.routeIndex = 0
+	d.routeOffset = 0
+	d.routeLeg = leg
+}
+
+// wantedLeg returns the route the driver should be on given its status, and its target.
+// Drivers which aren't driving have none.
+func (d *driverInMap) wantedLeg() (string, []float64, bool) {
+	switch d.Driver.Status {
+	case DriverStatusAvailable, DriverStatusOffered:
+		return routeLegPatrol, nil, true
+	case DriverStatusEnRoute:
+		if pickup := d.trip.GetPickup(); pickup != nil {
+			return routeLegPickup, []float64{pickup.Latitude, pickup.Longitude}, true
+		}
+	case DriverStatusOnTrip:
+		if destination := d.trip.GetDestination(); destination != nil {
+			return routeLegDropoff, []float64{destination.Latitude, destination.Longitude}, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// advance drives the meters along the route and reports whether the driver reached its end.
+// Patrol routes are driven back and forth instead.
+func (d *driverInMap) advance(meters float64) bool {
+	for meters > 0 && d.routeIndex < len(d.route)-1 {
+		from, to := d.route[d.routeIndex], d.route[d.routeIndex+1]
+		left := util.HaversineMeters(from[0], from[1], to[0], to[1]) - d.routeOffset
+
+		if meters < left {
+			d.routeOffset += meters
+			break
+		}
+
+		meters -= left
+		d.routeIndex++
+		d.routeOffset = 0
+	}
+
+	arrived := d.routeIndex >= len(d.route)-1
+	lat, lng := d.routePosition()
+	d.Driver.Location = &pb.Location{Latitude: lat, Longitude: lng}
+	d.Driver.Geohash = geohash.Encode(lat, lng)
+
+	if arrived && d.routeLeg == routeLegPatrol {
+		// The predefined routes are shared, reverse a copy
+		d.route = slices.Clone(d.route)
+		slices.Reverse(d.route)
+		d.routeIndex = 0
+		d.routeOffset = 0
+		return false
+	}
+
+	return arrived
+}
+
+func (d *driverInMap) routePosition() (float64, float64) {
+	if d.routeIndex >= len(d.route)-1 {
+		last := d.route[len(d.route)-1]
+		return last[0], last[1]
+	}
+
+	from, to := d.route[d.routeIndex], d.route[d.routeIndex+1]
+	length := util.HaversineMeters(from[0], from[1], to[0], to[1])
+	if length == 0 {
+		return from[0], from[1]
+	}
+
+	// Segments are short enough for a linear interpolation
+	f := d.routeOffset / length
+	return from[0] + (to[0]-from[0])*f, from[1] + (to[1]-from[1])*f
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"math"
+	"slices"
+
+	"ride-sharing/shared/util"
+
+	"github.com/mmcloughlin/geohash"
+)
+
+// Precision of the index cells, 6 characters is a ~1.2km x 0.6km cell
+const spatialIndexPrecision = 6
+
+type nearbyDriver struct {
+	driver   *driverInMap
+	distance float64 // meters
+}
+
+// spatialIndex buckets drivers by the prefix of their geohash, so nearby drivers can be found
+// by only looking at the cells around a point.
+// It isn't safe for concurrent use on its own, the Service mutex protects it.
+type spatialIndex struct {
+	precision uint
+	cells     map[string]map[string]*driverInMap
+	// cellOf remembers where every driver is indexed, to move them without scanning
+	cellOf map[string]string
+}
+
+func newSpatialIndex(precision uint) *spatialIndex {
+	return &spatialIndex{
+		precision: precision,
+		cells:     make(map[string]map[string]*driverInMap),
+		cellOf:    make(map[string]string),
+	}
+}
+
+// upsert (re)indexes the driver under the cell of its current geohash
+func (idx *spatialIndex) upsert(d *driverInMap) {
+	id := d.Driver.Id
+	cell := d.Driver.Geohash[:min(int(idx.precision), len(d.Driver.Geohash))]
+
+	if previous, ok := idx.cellOf[id]; ok {
+		if previous == cell {
+			return
+		}
+		idx.removeFromCell(previous, id)
+	}
+
+	if _, ok := idx.cells[cell]; !ok {
+		idx.cells[cell] = make(map[string]*driverInMap)
+	}
+
+	idx.cells[cell][id] = d
+	idx.cellOf[id] = cell
+}
+
+func (idx *spatialIndex) remove(driverID string) {
+	cell, ok := idx.cellOf[driverID]
+	if !ok {
+		return
+	}
+
+	idx.removeFromCell(cell, driverID)
+	delete(idx.cellOf, driverID)
+}
+
+func (idx *spatialIndex) removeFromCell(cell, driverID string) {
+	delete(idx.cells[cell], driverID)
+	if len(idx.cells[cell]) == 0 {
+		delete(idx.cells, cell)
+	}
+}
+
+// nearby returns the drivers within the radius of the point, closest first.
+// An empty packageSlug matches every package and a limit <= 0 returns every match.
+func (idx *spatialIndex) nearby(
+	lat, lng float64,
+	radiusMeters float64,
+	packageSlug string,
+	limit int,
+	accept func(*driverInMap) bool,
+) []nearbyDriver {
+	matches := make([]nearbyDriver, 0)
+
+	for _, cell := range idx.cellsWithin(lat, lng, radiusMeters) {
+		for _, d := range idx.cells[cell] {
+			if packageSlug != "" && d.Driver.PackageSlug != packageSlug {
+				continue
+			}
+
+			if accept != nil && !accept(d) {
+				continue
+			}
+
+			location := d.Driver.Location
+			distance := util.HaversineMeters(lat, lng, location.Latitude, location.Longitude)
+			if distance <= radiusMeters {
+				matches = append(matches, nearbyDriver{driver: d, distance: distance})
+			}
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b nearbyDriver) int {
+		switch {
+		case a.distance < b.distance:
+			return -1
+		case a.distance > b.distance:
+			return 1
+		}
+		return 0
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+// cellsWithin expands from the cell of the point through the neighbor cells,
+// as long as they are (at least partially) within the radius.
+// Only cells with drivers are returned.
+func (idx *spatialIndex) cellsWithin(lat, lng, radiusMeters float64) []string {
+	origin := geohash.EncodeWithPrecision(lat, lng, idx.precision)
+
+	visited := map[string]bool{origin: true}
+	queue := []string{origin}
+	withDrivers := make([]string, 0)
+
+	for len(queue) > 0 {
+		cell := queue[0]
+		queue = queue[1:]
+
+		if _, ok := idx.cells[cell]; ok {
+			withDrivers = append(withDrivers, cell)
+		}
+
+		for _, neighbor := range geohash.Neighbors(cell) {
+			if visited[neighbor] {
+				continue
+			}
+			visited[neighbor] = true
+
+			if distanceToBox(lat, lng, geohash.BoundingBox(neighbor)) <= radiusMeters {
+				queue = append(queue, neighbor)
+			}
+		}
+	}
+
+	return withDrivers
+}
+
+// distanceToBox returns the distance in meters from the point to the closest point of the box
+func distanceToBox(lat, lng float64, box geohash.Box) float64 {
+	// The boxes across the antimeridian are closer the other way around the globe
+	center := (box.MinLng + box.MaxLng) / 2
+	if lng-center > 180 {
+		lng -= 360
+	} else if center-lng > 180 {
+		lng += 360
+	}
+
+	closestLat := math.Max(box.MinLat, math.Min(lat, box.MaxLat))
+	closestLng := math.Max(box.MinLng, math.Min(lng, box.MaxLng))
+
+	return util.HaversineMeters(lat, lng, closestLat, closestLng)
+}
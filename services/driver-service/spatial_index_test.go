@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	pb "ride-sharing/shared/proto/driver"
+
+	"github.com/mmcloughlin/geohash"
+)
+
+func testDriver(id, packageSlug string, lat, lng float64) *driverInMap {
+	return &driverInMap{
+		Driver: &pb.Driver{
+			Id:          id,
+			PackageSlug: packageSlug,
+			Geohash:     geohash.Encode(lat, lng),
+			Location:    &pb.Location{Latitude: lat, Longitude: lng},
+		},
+	}
+}
+
+func nearbyIDs(matches []nearbyDriver) []string {
+	ids := make([]string, len(matches))
+	for i, match := range matches {
+		ids[i] = match.driver.Driver.Id
+	}
+
+	return ids
+}
+
+func TestSpatialIndexNearby(t *testing.T) {
+	// Cell 6 characters wide around the point, whose edges the drivers below straddle
+	const lat, lng = 40.7128, -74.0060
+	box := geohash.BoundingBox(geohash.EncodeWithPrecision(lat, lng, spatialIndexPrecision))
+
+	tests := []struct {
+		name         string
+		drivers      []*driverInMap
+		lat, lng     float64
+		radiusMeters float64
+		packageSlug  string
+		limit        int
+		want         []string
+	}{
+		{
+			name: "closest first",
+			drivers: []*driverInMap{
+				testDriver("far", "sedan", lat+0.004, lng),
+				testDriver("near", "sedan", lat+0.001, lng),
+				testDriver("here", "sedan", lat, lng),
+			},
+			lat: lat, lng: lng, radiusMeters: 1_000,
+			want: []string{"here", "near", "far"},
+		},
+		{
+			name: "radius crossing the cell boundary",
+			drivers: []*driverInMap{
+				// Just past the north edge of the cell of the point, in the neighbor cell
+				testDriver("north", "sedan", box.MaxLat+0.0005, lng),
+				// Two cells away east, still within the radius
+				testDriver("east", "sedan", lat, box.MaxLng+(box.MaxLng-box.MinLng)+0.001),
+			},
+			lat: box.MaxLat - 0.0005, lng: lng, radiusMeters: 5_000,
+			want: []string{"north", "east"},
+		},
+		{
+			name: "outside of the radius in a neighbor cell",
+			drivers: []*driverInMap{
+				testDriver("outside", "sedan", box.MaxLat+0.0005, lng),
+			},
+			lat: box.MinLat + 0.0001, lng: lng, radiusMeters: 50,
+			want: []string{},
+		},
+		{
+			name: "on the radius",
+			drivers: []*driverInMap{
+				testDriver("edge", "sedan", lat+0.001, lng),
+			},
+			lat: lat, lng: lng, radiusMeters: 111.2,
+			want: []string{"edge"},
+		},
+		{
+			name: "zero radius only matches the point",
+			drivers: []*driverInMap{
+				testDriver("here", "sedan", lat, lng),
+				testDriver("near", "sedan", lat+0.0001, lng),
+			},
+			lat: lat, lng: lng, radiusMeters: 0,
+			want: []string{"here"},
+		},
+		{
+			name: "package filter",
+			drivers: []*driverInMap{
+				testDriver("sedan", "sedan", lat, lng),
+				testDriver("van", "van", lat+0.001, lng),
+			},
+			lat: lat, lng: lng, radiusMeters: 1_000, packageSlug: "van",
+			want: []string{"van"},
+		},
+		{
+			name: "every package without a filter",
+			drivers: []*driverInMap{
+				testDriver("sedan", "sedan", lat, lng),
+				testDriver("van", "van", lat+0.001, lng),
+			},
+			lat: lat, lng: lng, radiusMeters: 1_000,
+			want: []string{"sedan", "van"},
+		},
+		{
+			name: "limit",
+			drivers: []*driverInMap{
+				testDriver("a", "sedan", lat, lng),
+				testDriver("b", "sedan", lat+0.001, lng),
+				testDriver("c", "sedan", lat+0.002, lng),
+			},
+			lat: lat, lng: lng, radiusMeters: 1_000, limit: 2,
+			want: []string{"a", "b"},
+		},
+		{
+			name: "across the antimeridian",
+			drivers: []*driverInMap{
+				testDriver("west", "sedan", 10, -179.9995),
+				testDriver("east", "sedan", 10, 179.9990),
+			},
+			lat: 10, lng: 179.9998, radiusMeters: 500,
+			want: []string{"west", "east"},
+		},
+		{
+			name: "near the pole",
+			drivers: []*driverInMap{
+				testDriver("pole", "sedan", 89.9995, 10),
+				testDriver("other side", "sedan", 89.9995, -170),
+			},
+			lat: 89.9999, lng: 10, radiusMeters: 500,
+			want: []string{"pole", "other side"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idx := newSpatialIndex(spatialIndexPrecision)
+			for _, d := range tt.drivers {
+				idx.upsert(d)
+			}
+
+			got := nearbyIDs(idx.nearby(tt.lat, tt.lng, tt.radiusMeters, tt.packageSlug, tt.limit, nil))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("nearby() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpatialIndexUpsertMovesDriver(t *testing.T) {
+	idx := newSpatialIndex(spatialIndexPrecision)
+
+	d := testDriver("driver", "sedan", 40.7128, -74.0060)
+	idx.upsert(d)
+
+	// Moves about 10km north, out of the cells around the first location
+	d.Driver.Location = &pb.Location{Latitude: 40.8028, Longitude: -74.0060}
+	d.Driver.Geohash = geohash.Encode(40.8028, -74.0060)
+	idx.upsert(d)
+
+	if got := idx.nearby(40.7128, -74.0060, 1_000, "", 0, nil); len(got) != 0 {
+		t.Errorf("driver still found at its previous location: %v", nearbyIDs(got))
+	}
+	if got := nearbyIDs(idx.nearby(40.8028, -74.0060, 1_000, "", 0, nil)); !slices.Equal(got, []string{"driver"}) {
+		t.Errorf("nearby() at the new location = %v, want [driver]", got)
+	}
+	if len(idx.cells) != 1 {
+		t.Errorf("%d cells indexed, want 1", len(idx.cells))
+	}
+
+	idx.remove("driver")
+	if len(idx.cells) != 0 || len(idx.cellOf) != 0 {
+		t.Errorf("index not empty after the removal: %d cells, %d drivers", len(idx.cells), len(idx.cellOf))
+	}
+}
+
+func TestSpatialIndexNearbyAccept(t *testing.T) {
+	idx := newSpatialIndex(spatialIndexPrecision)
+	idx.upsert(testDriver("busy", "sedan", 40.7128, -74.0060))
+	idx.upsert(testDriver("free", "sedan", 40.7138, -74.0060))
+
+	got := nearbyIDs(idx.nearby(40.7128, -74.0060, 1_000, "", 0, func(d *driverInMap) bool {
+		return d.Driver.Id != "busy"
+	}))
+	if !slices.Equal(got, []string{"free"}) {
+		t.Errorf("nearby() = %v, want [free]", got)
+	}
+}
+
+// benchmarkDrivers spreads the drivers over a ~50km square
+func benchmarkDrivers(n int) []*driverInMap {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	drivers := make([]*driverInMap, n)
+	for i := range drivers {
+		drivers[i] = testDriver(
+			fmt.Sprintf("driver-%d", i),
+			[]string{"sedan", "suv", "van"}[i%3],
+			40.5+r.Float64()*0.45,
+			-74.25+r.Float64()*0.6,
+		)
+	}
+
+	return drivers
+}
+
+func BenchmarkSpatialIndexNearby(b *testing.B) {
+	idx := newSpatialIndex(spatialIndexPrecision)
+	for _, d := range benchmarkDrivers(100_000) {
+		idx.upsert(d)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.nearby(40.7128, -74.0060, 2_000, "sedan", 10, nil)
+	}
+}
+
+func BenchmarkSpatialIndexUpsert(b *testing.B) {
+	drivers := benchmarkDrivers(100_000)
+	idx := newSpatialIndex(spatialIndexPrecision)
+	for _, d := range drivers {
+		idx.upsert(d)
+	}
+
+	// Every driver moves to the location of another one, switching cells most of the time
+	moves := benchmarkDrivers(100_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d := drivers[i%len(drivers)]
+		to := moves[(i*7919)%len(moves)].Driver
+		d.Driver.Location = to.Location
+		d.Driver.Geohash = to.Geohash
+		idx.upsert(d)
+	}
+}
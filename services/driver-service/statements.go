@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"ride-sharing/shared/env"
+	pb "ride-sharing/shared/proto/driver"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Statement periods
+const (
+	PeriodDaily  = "daily"
+	PeriodWeekly = "weekly"
+)
+
+var (
+	ErrInvalidPeriod     = errors.New("invalid statement period")
+	ErrInvalidAdjustment = errors.New("invalid earnings adjustment")
+)
+
+// statementLocation is where the days of the statements start and end, DRIVER_STATEMENT_TIMEZONE loaded at startup
+var statementLocation = time.UTC
+
+// loadTimezone loads the timezone named by the environment variable, UTC by default
+func loadTimezone(variable string) (*time.Location, error) {
+	name := env.GetString(variable, "UTC")
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %w", variable, name, err)
+	}
+
+	return loc, nil
+}
+
+// periodBounds returns the [from, to) range of the day or week (starting on Monday) the date is in
+func periodBounds(period string, date time.Time) (time.Time, time.Time, error) {
+	date = date.In(statementLocation)
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, statementLocation)
+
+	switch period {
+	case PeriodDaily:
+		return day, day.AddDate(0, 0, 1), nil
+	case PeriodWeekly:
+		// time.Sunday is 0, Monday starts the week
+		monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return monday, monday.AddDate(0, 0, 7), nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("%w: %q, %s or %s are expected", ErrInvalidPeriod, period, PeriodDaily, PeriodWeekly)
+}
+
+// Statement returns what the driver earned over the day or week the date is in
+func (e *Earnings) Statement(driverID, period string, date time.Time) (*pb.DriverStatement, error) {
+	from, to, err := periodBounds(period, date)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &pb.DriverStatement{
+		DriverID: driverID,
+		Period:   period,
+		From:     timestamppb.New(from),
+		To:       timestamppb.New(to),
+	}
+
+	for _, tx := range e.ledger.DriverTransactions(driverID, from, to) {
+		line := toStatementLine(tx, driverID)
+		statement.Lines = append(statement.Lines, line)
+
+		switch tx.kind {
+		case TransactionEarning:
+			statement.EarningsInCents += line.AmountInCents
+		case TransactionFee:
+			statement.FeesInCents += line.AmountInCents
+		case TransactionTip:
+			statement.TipsInCents += line.AmountInCents
+		case TransactionAdjustment:
+			statement.AdjustmentsInCents += line.AmountInCents
+		}
+		statement.NetInCents += line.AmountInCents
+	}
+
+	return statement, nil
+}
+
+func toStatementLine(tx *ledgerTransaction, driverID string) *pb.StatementLine {
+	return &pb.StatementLine{
+		TransactionID: tx.id,
+		CreatedAt:     timestamppb.New(tx.createdAt),
+		TripID:        tx.tripID,
+		Kind:          tx.kind,
+		AmountInCents: tx.amountFor(driverAccount(driverID)),
+		Description:   tx.description,
+	}
+}
+
+// statementCSV renders the statement lines followed by the totals, amounts in cents
+func statementCSV(statement *pb.DriverStatement) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+
+	rows := [][]string{{"date", "transaction_id", "trip_id", "kind", "description", "amount_cents"}}
+	for _, line := range statement.Lines {
+		rows = append(rows, []string{
+			line.CreatedAt.AsTime().In(statementLocation).Format(time.RFC3339),
+			line.TransactionID,
+			line.TripID,
+			line.Kind,
+			line.Description,
+			strconv.FormatInt(line.AmountInCents, 10),
+		})
+	}
+
+	totals := []struct {
+		name   string
+		amount int64
+	}{
+		{"total earnings", statement.EarningsInCents},
+		{"total fees", statement.FeesInCents},
+		{"total tips", statement.TipsInCents},
+		{"total adjustments", statement.AdjustmentsInCents},
+		{"net", statement.NetInCents},
+	}
+	for _, total := range totals {
+		rows = append(rows, []string{"", "", "", "", total.name, strconv.FormatInt(total.amount, 10)})
+	}
+
+	if err := w.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("failed to write the statement CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func statementFilename(statement *pb.DriverStatement) string {
+	return fmt.Sprintf(
+		"statement-%s-%s-%s.csv",
+		statement.DriverID,
+		statement.Period,
+		statement.From.AsTime().In(statementLocation).Format(time.DateOnly),
+	)
+}
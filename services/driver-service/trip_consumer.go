@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 
+	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/messaging"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -13,19 +16,83 @@ type Consumer interface {
 	Listen() error
 }
 
+// tripConsumer starts and cancels the dispatches of the trips, and releases the drivers whose
+// assignment was rejected
 type tripConsumer struct {
-	rabbitMQ *messaging.RabbitMQ
+	rabbitMQ   *messaging.RabbitMQ
+	dispatcher *Dispatcher
 }
 
-func NewTripConsumer(rabbitMQ *messaging.RabbitMQ) Consumer {
-	return &tripConsumer{rabbitMQ}
+func NewTripConsumer(rabbitMQ *messaging.RabbitMQ, dispatcher *Dispatcher) Consumer {
+	return &tripConsumer{
+		rabbitMQ:   rabbitMQ,
+		dispatcher: dispatcher,
+	}
 }
 
 func (c *tripConsumer) Listen() error {
 	return c.rabbitMQ.ConsumeMessages(
-		"hello",
+		messaging.FindAvailableDriversQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.TripEventData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the trip event: %v", err)
+			}
+
+			switch msg.RoutingKey {
+			case contracts.TripEventCreated:
+				return c.dispatcher.Start(ctx, payload.Trip)
+			case contracts.TripEventCancelled:
+				c.dispatcher.Cancel(payload.Trip.GetId())
+				return nil
+			case contracts.TripEventDriverRejected:
+				c.dispatcher.Reject(payload.Trip.GetId(), payload.Trip.GetDriver().GetId())
+				return nil
+			}
+
+			log.Printf("Unknown trip event: %s", msg.RoutingKey)
+			return nil
+		},
+	)
+}
+
+// completionConsumer makes the drivers available again once their trip is over
+type completionConsumer struct {
+	rabbitMQ *messaging.RabbitMQ
+	service  *Service
+}
+
+func NewCompletionConsumer(rabbitMQ *messaging.RabbitMQ, service *Service) Consumer {
+	return &completionConsumer{
+		rabbitMQ: rabbitMQ,
+		service:  service,
+	}
+}
+
+func (c *completionConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.DriverTripCompletedQueue,
 		func(ctx context.Context, msg amqp.Delivery) error {
-			log.Printf("Driver service received message: %v\n", msg)
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.TripEventData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the trip event: %v", err)
+			}
+
+			driverID := payload.Trip.GetDriver().GetId()
+
+			// The driver may have switched to on_trip through its app, or not, or went offline on the trip
+			c.service.ReleaseTrip(driverID, payload.Trip.GetId())
+
 			return nil
 		},
 	)
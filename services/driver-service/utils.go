@@ -2,7 +2,7 @@ package main
 
 import "math/rand"
 
-// PredefinedRoutes for drivers
+// PredefinedRoutes for drivers, used when no routes file is configured
 // (these are San Francisco routes, get these coordinates from Google Maps for example and build a custom route if you want)
 var PredefinedRoutes = [][][]float64{
 	{
@@ -46,6 +46,18 @@ var PredefinedRoutes = [][][]float64{
 	},
 }
 
+// SeatCapacity returns how many riders fit in a vehicle of the given package
+func SeatCapacity(packageSlug string) int32 {
+	switch packageSlug {
+	case "van":
+		return 6
+	case "suv":
+		return 5
+	default:
+		return 4
+	}
+}
+
 func GenerateRandomPlate() string {
 	letters := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	plate := ""
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sync"
+
+	pb "ride-sharing/shared/proto/driver"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// locationWatchers streams the moves of the drivers to their watchers.
+// A slow watcher only gets the latest location, the ones in between are dropped.
+type locationWatchers struct {
+	mu   sync.Mutex
+	subs map[string]map[chan *pb.Driver]struct{}
+}
+
+func newLocationWatchers() *locationWatchers {
+	return &locationWatchers{
+		subs: make(map[string]map[chan *pb.Driver]struct{}),
+	}
+}
+
+func (w *locationWatchers) subscribe(driverID string) (<-chan *pb.Driver, func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan *pb.Driver, 1)
+	if w.subs[driverID] == nil {
+		w.subs[driverID] = make(map[chan *pb.Driver]struct{})
+	}
+	w.subs[driverID][ch] = struct{}{}
+
+	unsubscribe := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		if _, ok := w.subs[driverID][ch]; ok {
+			delete(w.subs[driverID], ch)
+			close(ch)
+		}
+		if len(w.subs[driverID]) == 0 {
+			delete(w.subs, driverID)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+// notify never blocks, the caller holds the service mutex
+func (w *locationWatchers) notify(driver *pb.Driver) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subs[driver.Id] {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- proto.Clone(driver).(*pb.Driver)
+	}
+}
+
+// closeAll ends the watches of the driver
+func (w *locationWatchers) closeAll(driverID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for ch := range w.subs[driverID] {
+		close(ch)
+	}
+	delete(w.subs, driverID)
+}
+
+// WatchDriver returns the driver and the channel its next locations are sent to,
+// which is closed when the driver unregisters
+func (s *Service) WatchDriver(driverID string) (*pb.Driver, <-chan *pb.Driver, func(), bool) {
+	// Subscribing under the service mutex, so no move happens between the snapshot and the subscription
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	d, ok := s.drivers[driverID]
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	updates, unsubscribe := s.watchers.subscribe(driverID)
+
+	return proto.Clone(d.Driver).(*pb.Driver), updates, unsubscribe, true
+}
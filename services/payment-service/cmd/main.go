@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/infrastructure/events"
+	infraGRPC "ride-sharing/services/payment-service/internal/infrastructure/grpc"
+	"ride-sharing/services/payment-service/internal/infrastructure/poller"
+	"ride-sharing/services/payment-service/internal/infrastructure/providers"
+	"ride-sharing/services/payment-service/internal/infrastructure/repository"
+	"ride-sharing/services/payment-service/internal/service"
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/messaging"
+
+	"google.golang.org/grpc"
+)
+
+const GRPCAddr = ":9084"
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	providerCfg := providers.ConfigFromEnv()
+	provider, err := providers.NewProvider(providerCfg)
+	if err != nil {
+		log.Fatalf("Failed to create the payment provider: %v", err)
+	}
+	log.Printf("Using the %s payment provider", providerCfg.Provider)
+
+	inMemRepo := repository.NewInMemRepository()
+	serviceCfg := service.ConfigFromEnv()
+	svc, err := service.NewService(inMemRepo, provider, serviceCfg)
+	if err != nil {
+		log.Fatalf("Failed to create the payment service: %v", err)
+	}
+	log.Printf("Payments are in the %s mode", serviceCfg.Mode)
+
+	listener, err := net.Listen("tcp", GRPCAddr)
+	if err != nil {
+		log.Fatalf("Failed to start gRPC listener on %s: %v", GRPCAddr, err)
+	}
+
+	rabbitMQURI := env.GetString(env.RabbitMQ.URI, env.RabbitMQDefaults.URI)
+	rabbitMQ, err := messaging.NewRabbitMQ(rabbitMQURI)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer rabbitMQ.Close()
+
+	log.Println("Successfully connected to RabbitMQ")
+
+	publisher := events.NewPublisher(rabbitMQ)
+
+	tripConsumer := events.NewTripConsumer(rabbitMQ, svc, publisher)
+	if err := tripConsumer.Listen(); err != nil {
+		log.Fatalf("Failed to listen to the trip events: %v", err)
+	}
+
+	paymentConsumer := events.NewPaymentConsumer(rabbitMQ, svc, publisher)
+	if err := paymentConsumer.Listen(); err != nil {
+		log.Fatalf("Failed to listen to the payment events: %v", err)
+	}
+
+	// The webhooks report the payments as they happen, polling catches the missed ones
+	if pollInterval := env.GetDuration("PAYMENT_POLL_INTERVAL", 5*time.Second); pollInterval > 0 {
+		paymentPoller := poller.NewPoller(svc, publisher, pollInterval)
+		go paymentPoller.Run(ctx)
+	}
+
+	grpcServer := grpc.NewServer()
+	_ = infraGRPC.NewHandler(grpcServer, svc, publisher)
+
+	log.Printf("Starting the gRPC server of Payment Service on addr: %s", listener.Addr().String())
+
+	serverErrorsChan := make(chan error, 1)
+	shutdown := make(chan os.Signal, 1)
+	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			serverErrorsChan <- err
+		}
+	}()
+
+	handleShutdown(grpcServer, serverErrorsChan, shutdown)
+}
+
+func handleShutdown(
+	server *grpc.Server,
+	serverErrorsChan chan error,
+	shutdown chan os.Signal,
+) {
+	select {
+	case err := <-serverErrorsChan:
+		log.Printf("Error starting the server: %v\n", err)
+		log.Println("Shutting down the gRPC server...")
+		// Stop receiving signals since we're shutting down
+		signal.Stop(shutdown)
+		close(serverErrorsChan)
+		server.GracefulStop()
+	case sig := <-shutdown:
+		log.Printf("Server is shutting down due to: %v signal\n", sig)
+		// Stop receiving signals since we're shutting down
+		signal.Stop(shutdown)
+		close(serverErrorsChan)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// An empty struct channel to signal when GracefulStop completes so we can log that
+		done := make(chan struct{})
+		go func() {
+			log.Println("Shutting down the gRPC server gracefully...")
+			server.GracefulStop()
+			close(done)
+		}()
+
+		// Wait for graceful stop or timeout
+		select {
+		case <-done:
+			log.Println("gRPC server shut down gracefully")
+		case <-ctx.Done():
+			log.Printf("Graceful shutdown timeout exceeded, forcing stop: %v\n", ctx.Err())
+			server.Stop()
+		}
+	}
+}
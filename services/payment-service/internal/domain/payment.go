@@ -0,0 +1,397 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "ride-sharing/shared/proto/payment"
+	tripPb "ride-sharing/shared/proto/trip"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Payment modes
+const (
+	// PaymentModeCharge charges the quoted fare through the checkout session
+	PaymentModeCharge = "charge"
+	// PaymentModeHold places a hold for the quoted fare plus a buffer through the checkout session,
+	// the final fare is captured once the trip completes
+	PaymentModeHold = "hold"
+)
+
+// Payment kinds
+const (
+	// PaymentKindFare is the fare of the trip
+	PaymentKindFare = "fare"
+	// PaymentKindTip is what the rider adds for the driver once the trip completed, charged on its own session
+	PaymentKindTip = "tip"
+	// PaymentKindBalance is the part of the final fare over what the hold covered, charged on its own session
+	// once the hold is captured
+	PaymentKindBalance = "balance"
+)
+
+// Payment statuses
+const (
+	// PaymentStatusPending is waiting for the rider to pay through the checkout session
+	PaymentStatusPending = "pending"
+	// PaymentStatusAuthorized is a hold on the funds of the rider, waiting for the trip to complete
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusSucceeded  = "succeeded"
+	PaymentStatusFailed     = "failed"
+	// PaymentStatusCancelled is a session that expired or whose trip was cancelled before the payment
+	PaymentStatusCancelled = "cancelled"
+	// PaymentStatusRefunded is a payment refunded in full, partially refunded payments stay succeeded
+	PaymentStatusRefunded = "refunded"
+)
+
+// Refund statuses
+const (
+	// RefundStatusPending is a refund the provider is being asked for
+	RefundStatusPending   = "pending"
+	RefundStatusSucceeded = "succeeded"
+)
+
+// Refund reasons
+const (
+	RefundReasonRequestedByCustomer = "requested_by_customer"
+	RefundReasonDuplicate           = "duplicate"
+	RefundReasonFraudulent          = "fraudulent"
+	RefundReasonTripCancelled       = "trip_cancelled"
+	RefundReasonServiceIssue        = "service_issue"
+	RefundReasonFareAdjustment      = "fare_adjustment"
+)
+
+var RefundReasons = []string{
+	RefundReasonRequestedByCustomer,
+	RefundReasonDuplicate,
+	RefundReasonFraudulent,
+	RefundReasonTripCancelled,
+	RefundReasonServiceIssue,
+	RefundReasonFareAdjustment,
+}
+
+// Checkout session statuses, as reported by the payment providers
+const (
+	SessionStatusOpen    = "open"
+	SessionStatusPaid    = "paid"
+	SessionStatusFailed  = "failed"
+	SessionStatusExpired = "expired"
+	// SessionStatusAuthorized is a completed session whose funds are held until they're captured
+	SessionStatusAuthorized = "authorized"
+)
+
+var (
+	// ErrPaymentNotFound is returned when there's no payment for the trip and rider
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrPaymentExists is returned when the rider already has a payment for the trip
+	ErrPaymentExists = errors.New("payment already exists")
+	// ErrInvalidAmount is returned when the amount to charge isn't positive
+	ErrInvalidAmount = errors.New("invalid payment amount")
+	// ErrPaymentStatusChanged is returned when the payment moved on since it was read
+	ErrPaymentStatusChanged = errors.New("payment status changed")
+	// ErrInvalidRefund is returned when the refund request is incomplete or inconsistent
+	ErrInvalidRefund = errors.New("invalid refund")
+	// ErrPaymentNotRefundable is returned when the payment wasn't captured, or was refunded in full
+	ErrPaymentNotRefundable = errors.New("payment can't be refunded")
+	// ErrRefundExceedsPayment is returned when the refund is more than what's left of the payment
+	ErrRefundExceedsPayment = errors.New("refund exceeds the payment")
+)
+
+// PaymentModel is what a rider pays for a trip. Pool trips have one payment per rider.
+type PaymentModel struct {
+	TripID   string
+	UserID   string
+	DriverID string
+	// Kind is fare, tip or balance, a rider has at most one of each per trip
+	Kind string
+	// PayerID is who's charged for the rider, the rider themselves unless someone covered them
+	PayerID string
+	// FallbackPayerID covers the payment when the rider doesn't pay, the owner of the trip for the
+	// participants of a split fare
+	FallbackPayerID string
+	// AmountInCents is in the smallest unit of the currency
+	AmountInCents int64
+	Currency      string
+	Status        string
+	SessionID     string
+	CheckoutURL   string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+
+	// HoldInCents is what the session holds in the hold mode, 0 when the fare is charged up front
+	HoldInCents int64
+	// CaptureInCents is the final fare to capture, set when the trip completes
+	CaptureInCents int64
+
+	// RefundedInCents is the total of the refunds, pending ones included
+	RefundedInCents int64
+	Refunds         []*RefundModel
+}
+
+// RefundModel gives back part of a payment
+type RefundModel struct {
+	ID string
+	// ReferenceID is given by the caller, the same reference is only refunded once
+	ReferenceID      string
+	AmountInCents    int64
+	Reason           string
+	Status           string
+	ProviderRefundID string
+	CreatedAt        time.Time
+}
+
+// IsFinal tells whether the payment won't be settled anymore, refunds may still change it
+func (p *PaymentModel) IsFinal() bool {
+	return p.Status != PaymentStatusPending && p.Status != PaymentStatusAuthorized
+}
+
+// Payer returns who's charged for the payment
+func (p *PaymentModel) Payer() string {
+	if p.PayerID != "" {
+		return p.PayerID
+	}
+
+	return p.UserID
+}
+
+// NeedsFallback tells whether the payment is charged to the fallback payer when it ends up in the status.
+// Only the failed payments are, the expired and cancelled sessions weren't refused by the payer.
+func (p *PaymentModel) NeedsFallback(status string) bool {
+	if status != PaymentStatusFailed {
+		return false
+	}
+
+	return p.FallbackPayerID != "" && p.Payer() != p.FallbackPayerID
+}
+
+// IsTip tells whether the payment is a tip for the driver rather than the fare
+func (p *PaymentModel) IsTip() bool {
+	return p.Kind == PaymentKindTip
+}
+
+// IsBalance tells whether the payment is the rest of a final fare the hold didn't cover
+func (p *PaymentModel) IsBalance() bool {
+	return p.Kind == PaymentKindBalance
+}
+
+// IsHold tells whether the session only holds the funds, the payment being captured when the trip completes
+func (p *PaymentModel) IsHold() bool {
+	return p.HoldInCents > 0
+}
+
+// NetInCents is what the rider paid minus the refunds
+func (p *PaymentModel) NetInCents() int64 {
+	if p.Status != PaymentStatusSucceeded && p.Status != PaymentStatusRefunded {
+		return 0
+	}
+
+	return p.AmountInCents - p.RefundedInCents
+}
+
+// RefundableInCents is what can still be refunded, only captured payments can be
+func (p *PaymentModel) RefundableInCents() int64 {
+	if p.Status != PaymentStatusSucceeded {
+		return 0
+	}
+
+	return p.AmountInCents - p.RefundedInCents
+}
+
+// FindRefund returns the refund with the reference, if any
+func (p *PaymentModel) FindRefund(referenceID string) *RefundModel {
+	for _, refund := range p.Refunds {
+		if refund.ReferenceID == referenceID {
+			return refund
+		}
+	}
+
+	return nil
+}
+
+func (p *PaymentModel) ToProto() *pb.Payment {
+	payment := &pb.Payment{
+		TripID:          p.TripID,
+		UserID:          p.UserID,
+		DriverID:        p.DriverID,
+		AmountInCents:   p.AmountInCents,
+		RefundedInCents: p.RefundedInCents,
+		NetInCents:      p.NetInCents(),
+		Currency:        p.Currency,
+		Status:          p.Status,
+	}
+
+	for _, refund := range p.Refunds {
+		payment.Refunds = append(payment.Refunds, refund.ToProto())
+	}
+
+	return payment
+}
+
+func (r *RefundModel) ToProto() *pb.Refund {
+	return &pb.Refund{
+		Id:            r.ID,
+		ReferenceID:   r.ReferenceID,
+		AmountInCents: r.AmountInCents,
+		Reason:        r.Reason,
+		Status:        r.Status,
+		CreatedAt:     timestamppb.New(r.CreatedAt),
+	}
+}
+
+// SessionRequest is the checkout session to create for a payment
+type SessionRequest struct {
+	TripID string
+	UserID string
+	// Kind and DriverID are reported back by the webhooks, the tips go to the driver
+	Kind     string
+	DriverID string
+	// PayerID is who goes through the checkout when it's not the rider, it's reported back by the webhooks
+	PayerID       string
+	AmountInCents int64
+	Currency      string
+	Description   string
+	// CaptureLater only holds the amount once the rider went through the checkout
+	CaptureLater bool
+	// IdempotencyKey makes retries create the session only once
+	IdempotencyKey string
+}
+
+// CaptureRequest charges part or all of what an authorized session holds
+type CaptureRequest struct {
+	SessionID     string
+	AmountInCents int64
+	// IdempotencyKey makes retries capture only once
+	IdempotencyKey string
+}
+
+// Session is a checkout session of a payment provider
+type Session struct {
+	ID string
+	// URL is where the rider pays, empty when the client redirects by itself
+	URL    string
+	Status string
+}
+
+// RefundRequest gives back part of what was paid through a checkout session
+type RefundRequest struct {
+	SessionID     string
+	AmountInCents int64
+	Reason        string
+	// IdempotencyKey makes retries refund only once
+	IdempotencyKey string
+}
+
+// ProviderRefund is a refund made by a payment provider
+type ProviderRefund struct {
+	ID     string
+	Status string
+}
+
+// PaymentProvider creates the checkout sessions the riders pay through
+type PaymentProvider interface {
+	CreateSession(ctx context.Context, req *SessionRequest) (*Session, error)
+	GetSession(ctx context.Context, sessionID string) (*Session, error)
+	// ExpireSession closes an open session, the rider can't pay through it anymore
+	ExpireSession(ctx context.Context, sessionID string) error
+	// Refund gives back part or all of what was paid through the session
+	Refund(ctx context.Context, req *RefundRequest) (*ProviderRefund, error)
+	// Capture charges the amount, at most what's held, of an authorized session
+	Capture(ctx context.Context, req *CaptureRequest) error
+	// ReleaseHold gives the held funds of an authorized session back to the rider
+	ReleaseHold(ctx context.Context, sessionID string) error
+}
+
+// PaymentRepository stores the payments, the payments it returns are copies
+type PaymentRepository interface {
+	// CreatePayment fails with ErrPaymentExists when the rider already has a payment for the trip
+	CreatePayment(ctx context.Context, payment *PaymentModel) error
+	GetPayment(ctx context.Context, tripID, userID string) (*PaymentModel, error)
+	// SetPaymentStatus moves the payment from one status to another and returns it updated.
+	// It fails with ErrPaymentStatusChanged when the payment isn't in the from status anymore.
+	SetPaymentStatus(ctx context.Context, tripID, userID, from, to string) (*PaymentModel, error)
+	ListTripPayments(ctx context.Context, tripID string) ([]*PaymentModel, error)
+	ListPaymentsByStatus(ctx context.Context, status string) ([]*PaymentModel, error)
+	// SetCaptureAmount records the final fare to capture on the payment and returns it updated
+	SetCaptureAmount(ctx context.Context, tripID, userID string, amountInCents int64) (*PaymentModel, error)
+	// CapturePayment moves the authorized payment to succeeded with the captured amount and returns it updated.
+	// It fails with ErrPaymentStatusChanged when the payment isn't authorized anymore.
+	CapturePayment(ctx context.Context, tripID, userID string, amountInCents int64) (*PaymentModel, error)
+	// ReserveRefund adds the pending refund to the payment, unless the payment already has a refund with
+	// the same reference, which is returned instead. It fails with ErrRefundExceedsPayment when the refund
+	// is more than what's left to refund.
+	ReserveRefund(ctx context.Context, tripID, userID string, refund *RefundModel) (*PaymentModel, *RefundModel, error)
+	// CompleteRefund marks the refund as succeeded, the payment is refunded once nothing's left
+	CompleteRefund(ctx context.Context, tripID, userID, refundID, providerRefundID string) (*PaymentModel, *RefundModel, error)
+	// ReleaseRefund removes the pending refund the provider didn't make
+	ReleaseRefund(ctx context.Context, tripID, userID, refundID string) error
+	// ReserveBalanceRefund, CompleteBalanceRefund and ReleaseBalanceRefund are the same for the balances
+	ReserveBalanceRefund(ctx context.Context, tripID, userID string, refund *RefundModel) (*PaymentModel, *RefundModel, error)
+	CompleteBalanceRefund(ctx context.Context, tripID, userID, refundID, providerRefundID string) (*PaymentModel, *RefundModel, error)
+	ReleaseBalanceRefund(ctx context.Context, tripID, userID, refundID string) error
+	// CreateTip fails with ErrPaymentExists when the rider already tipped on the trip. The tips are kept
+	// apart from the fares, the other methods only see the fares.
+	CreateTip(ctx context.Context, tip *PaymentModel) error
+	GetTip(ctx context.Context, tripID, userID string) (*PaymentModel, error)
+	// SetTipStatus moves the tip from one status to another and returns it updated.
+	// It fails with ErrPaymentStatusChanged when the tip isn't in the from status anymore.
+	SetTipStatus(ctx context.Context, tripID, userID, from, to string) (*PaymentModel, error)
+	// ReplaceTip replaces the tip of the rider by a new one. It fails with ErrPaymentStatusChanged when the
+	// tip isn't in the from status anymore.
+	ReplaceTip(ctx context.Context, from string, tip *PaymentModel) error
+	ListTipsByStatus(ctx context.Context, status string) ([]*PaymentModel, error)
+	// CreateBalance fails with ErrPaymentExists when the rider already has a balance for the trip. The balances
+	// are kept apart like the tips.
+	CreateBalance(ctx context.Context, balance *PaymentModel) error
+	GetBalance(ctx context.Context, tripID, userID string) (*PaymentModel, error)
+	// SetBalanceStatus moves the balance from one status to another and returns it updated.
+	// It fails with ErrPaymentStatusChanged when the balance isn't in the from status anymore.
+	SetBalanceStatus(ctx context.Context, tripID, userID, from, to string) (*PaymentModel, error)
+	ListBalancesByStatus(ctx context.Context, status string) ([]*PaymentModel, error)
+	// ReassignPayment charges the pending payment to another payer through a new session and returns it
+	// updated. It fails with ErrPaymentStatusChanged when the payment isn't pending on the session anymore.
+	ReassignPayment(
+		ctx context.Context,
+		tripID, userID, sessionID string,
+		payerID, newSessionID, checkoutURL string,
+	) (*PaymentModel, error)
+}
+
+type PaymentService interface {
+	// CreateSessions opens a checkout session for every rider of the trip without one yet,
+	// and returns the new payments
+	CreateSessions(ctx context.Context, trip *tripPb.Trip) ([]*PaymentModel, error)
+	// CancelTripPayments expires the pending sessions and releases the holds of the cancelled trip,
+	// and returns the cancelled payments
+	CancelTripPayments(ctx context.Context, tripID string) ([]*PaymentModel, error)
+	// CaptureTripPayments captures the final fares of the completed trip held by the payments, the holds not
+	// placed yet are captured once they are. The final fares over the holds get a balance session for the rest.
+	// It returns the captured payments and the new balances.
+	CaptureTripPayments(ctx context.Context, trip *tripPb.Trip) ([]*PaymentModel, error)
+	// ChargeTip opens a checkout session for the tip of the rider, and returns no payment when the rider
+	// already has one. A tip which failed or was cancelled is replaced by the one tipped after it.
+	ChargeTip(
+		ctx context.Context,
+		tripID, userID, driverID string,
+		amountInCents int64,
+		tippedAt time.Time,
+	) (*PaymentModel, error)
+	// SyncPendingPayments checks the pending sessions, tips and balances included, with the provider, retries
+	// the captures which failed, and returns the payments that changed
+	SyncPendingPayments(ctx context.Context) ([]*PaymentModel, error)
+	// SettlePayment records the outcome of the pending payment of the kind reported by the provider webhooks,
+	// holds of completed trips are captured right away and the unpaid shares of a split fare are charged to
+	// the owner. It returns the payment, followed by its balance when the capture opened one, and no payments
+	// when the payment was already settled, or when the session isn't the one of the payment anymore.
+	SettlePayment(ctx context.Context, tripID, userID, kind, sessionID, status string) ([]*PaymentModel, error)
+	// RefundPayment refunds the payment of the rider, everything left when the amount is 0. The rider can be
+	// omitted when a single rider paid for the trip. The fare is refunded first, then its balance if any, the
+	// refund being capped at what both captured. It returns the fare followed by its balance, and the refund
+	// they add up to. Retries with the same reference get the first refund back.
+	RefundPayment(
+		ctx context.Context,
+		tripID, userID string,
+		amountInCents int64,
+		reason, referenceID string,
+	) ([]*PaymentModel, *RefundModel, error)
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// PaymentConsumer settles the payments with the outcomes the gateway got from the provider webhooks.
+// It also gets the events published by this service, they find the payments already settled.
+type PaymentConsumer struct {
+	rabbitMQ  *messaging.RabbitMQ
+	service   domain.PaymentService
+	publisher Publisher
+}
+
+func NewPaymentConsumer(rabbitMQ *messaging.RabbitMQ, service domain.PaymentService, publisher Publisher) *PaymentConsumer {
+	return &PaymentConsumer{
+		rabbitMQ:  rabbitMQ,
+		service:   service,
+		publisher: publisher,
+	}
+}
+
+func (c *PaymentConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.PaymentStatusUpdateQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.PaymentEventData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the payment event: %v", err)
+			}
+
+			var status string
+			switch msg.RoutingKey {
+			case contracts.PaymentEventAuthorized:
+				status = domain.PaymentStatusAuthorized
+			case contracts.PaymentEventSuccess:
+				status = domain.PaymentStatusSucceeded
+			case contracts.PaymentEventFailed:
+				status = domain.PaymentStatusFailed
+			case contracts.PaymentEventCancelled:
+				status = domain.PaymentStatusCancelled
+			default:
+				log.Printf("Unknown payment event: %s", msg.RoutingKey)
+				return nil
+			}
+
+			payments, err := c.service.SettlePayment(
+				ctx,
+				payload.TripID,
+				payload.UserID,
+				payload.Kind,
+				payload.SessionID,
+				status,
+			)
+			if errors.Is(err, domain.ErrPaymentNotFound) {
+				// Sessions created outside of the service, ex. from the provider dashboard
+				log.Printf("No payment of trip %s for %s to settle", payload.TripID, payload.UserID)
+				return nil
+			}
+
+			for _, payment := range payments {
+				switch {
+				case payment.Status != status:
+					// Holds of completed trips are captured as soon as they're placed, the capture and the balance
+					// of the fare over the hold weren't announced yet
+					PublishStatusEvents(ctx, c.publisher, []*domain.PaymentModel{payment})
+				case payment.IsTip() && payment.Status == domain.PaymentStatusSucceeded:
+					// The webhook event went to the rider, the driver hears of the tip from this service
+					if err := c.publisher.PublishTipReceived(ctx, payment); err != nil {
+						log.Printf("Failed to publish the tip of trip %s for %s: %v", payment.TripID, payment.DriverID, err)
+					}
+				}
+			}
+			return err
+		},
+	)
+}
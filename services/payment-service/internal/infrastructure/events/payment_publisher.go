@@ -0,0 +1,188 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+)
+
+type PaymentEventsPublisher struct {
+	rabbitMQ *messaging.RabbitMQ
+}
+
+func NewPublisher(rabbitMQ *messaging.RabbitMQ) Publisher {
+	return &PaymentEventsPublisher{
+		rabbitMQ: rabbitMQ,
+	}
+}
+
+// StatusRoutingKey returns the event published when a payment reaches the status
+func StatusRoutingKey(status string) (string, bool) {
+	switch status {
+	case domain.PaymentStatusAuthorized:
+		return contracts.PaymentEventAuthorized, true
+	case domain.PaymentStatusSucceeded:
+		return contracts.PaymentEventSuccess, true
+	case domain.PaymentStatusFailed:
+		return contracts.PaymentEventFailed, true
+	case domain.PaymentStatusCancelled:
+		return contracts.PaymentEventCancelled, true
+	}
+
+	return "", false
+}
+
+func (p *PaymentEventsPublisher) PublishPaymentEvent(
+	ctx context.Context,
+	routingKey string,
+	payment *domain.PaymentModel,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var payload any = messaging.PaymentEventData{
+		TripID:        payment.TripID,
+		UserID:        payment.UserID,
+		DriverID:      payment.DriverID,
+		PayerID:       payerID(payment),
+		SessionID:     payment.SessionID,
+		Kind:          payment.Kind,
+		AmountInCents: payment.AmountInCents,
+		TipInCents:    tipInCents(payment),
+		HoldInCents:   payment.HoldInCents,
+		Currency:      payment.Currency,
+	}
+	if routingKey == contracts.PaymentEventSessionCreated {
+		created := messaging.PaymentSessionCreatedData{
+			TripID:      payment.TripID,
+			Kind:        payment.Kind,
+			SessionID:   payment.SessionID,
+			Amount:      float64(payment.AmountInCents) / 100,
+			Currency:    payment.Currency,
+			CheckoutURL: payment.CheckoutURL,
+		}
+		if payerID(payment) != "" {
+			created.UserID = payment.UserID
+		}
+		payload = created
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the payment event: %w", err)
+	}
+
+	// The payer goes through the checkout and hears of its outcome
+	return p.rabbitMQ.Publish(ctx, routingKey, contracts.AmqpMessage{
+		OwnerID: payment.Payer(),
+		Data:    data,
+	})
+}
+
+func (p *PaymentEventsPublisher) PublishTipReceived(ctx context.Context, tip *domain.PaymentModel) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(messaging.PaymentEventData{
+		TripID:        tip.TripID,
+		UserID:        tip.UserID,
+		DriverID:      tip.DriverID,
+		Kind:          tip.Kind,
+		AmountInCents: tip.AmountInCents,
+		TipInCents:    tip.AmountInCents,
+		Currency:      tip.Currency,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the tip event: %w", err)
+	}
+
+	return p.rabbitMQ.Publish(ctx, contracts.PaymentEventTipReceived, contracts.AmqpMessage{
+		OwnerID: tip.DriverID,
+		Data:    data,
+	})
+}
+
+// payerID returns who's charged for the payment when it's not the rider
+func payerID(payment *domain.PaymentModel) string {
+	if payment.Payer() == payment.UserID {
+		return ""
+	}
+
+	return payment.Payer()
+}
+
+// tipInCents is the part of the payment going to the driver in full
+func tipInCents(payment *domain.PaymentModel) int64 {
+	if !payment.IsTip() {
+		return 0
+	}
+
+	return payment.AmountInCents
+}
+
+func (p *PaymentEventsPublisher) PublishRefundEvent(
+	ctx context.Context,
+	payments []*domain.PaymentModel,
+	refund *domain.RefundModel,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	payment := payments[0]
+	payload := messaging.PaymentRefundedData{
+		TripID:          payment.TripID,
+		UserID:          payment.UserID,
+		DriverID:        payment.DriverID,
+		RefundID:        refund.ID,
+		Reason:          refund.Reason,
+		AmountInCents:   refund.AmountInCents,
+		PaidInCents:     payment.AmountInCents,
+		RefundedInCents: payment.RefundedInCents,
+		Currency:        payment.Currency,
+	}
+	for _, balance := range payments[1:] {
+		payload.BalanceInCents += balance.AmountInCents
+		payload.RefundedInCents += balance.RefundedInCents
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the refund event: %w", err)
+	}
+
+	return p.rabbitMQ.Publish(ctx, contracts.PaymentEventRefunded, contracts.AmqpMessage{
+		OwnerID: payment.Payer(),
+		Data:    data,
+	})
+}
+
+// PublishStatusEvents publishes the event of the status every payment reached
+func PublishStatusEvents(ctx context.Context, publisher Publisher, payments []*domain.PaymentModel) {
+	for _, payment := range payments {
+		routingKey, ok := StatusRoutingKey(payment.Status)
+		if payment.Status == domain.PaymentStatusPending && (payerID(payment) != "" || payment.IsBalance()) {
+			// The share of a split participant was charged to the owner, who gets the new checkout, or the
+			// final fare was over the hold and the payer gets the checkout of the rest
+			routingKey, ok = contracts.PaymentEventSessionCreated, true
+		}
+		if !ok {
+			continue
+		}
+
+		if err := publisher.PublishPaymentEvent(ctx, routingKey, payment); err != nil {
+			log.Printf("Failed to publish %s of trip %s for %s: %v", routingKey, payment.TripID, payment.UserID, err)
+		}
+
+		if payment.IsTip() && payment.Status == domain.PaymentStatusSucceeded {
+			if err := publisher.PublishTipReceived(ctx, payment); err != nil {
+				log.Printf("Failed to publish the tip of trip %s for %s: %v", payment.TripID, payment.DriverID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,18 @@
+// Package events provides event publishing and consuming functionalities
+package events
+
+import (
+	"context"
+
+	"ride-sharing/services/payment-service/internal/domain"
+)
+
+type Publisher interface {
+	// PublishPaymentEvent publishes the payment under the given payment.event.* routing key, to its rider
+	PublishPaymentEvent(ctx context.Context, routingKey string, payment *domain.PaymentModel) error
+	// PublishTipReceived publishes payment.event.tip_received for the charged tip, to its driver
+	PublishTipReceived(ctx context.Context, tip *domain.PaymentModel) error
+	// PublishRefundEvent publishes payment.event.refunded for the refund of the payment, followed by its
+	// balance if any, to its rider
+	PublishRefundEvent(ctx context.Context, payments []*domain.PaymentModel, refund *domain.RefundModel) error
+}
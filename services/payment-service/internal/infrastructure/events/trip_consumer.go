@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// TripConsumer opens the checkout sessions of the trips once a driver is assigned, and of the tips the riders
+// add, captures the holds of the completed trips, and cancels the pending payments of the cancelled trips
+type TripConsumer struct {
+	rabbitMQ  *messaging.RabbitMQ
+	service   domain.PaymentService
+	publisher Publisher
+}
+
+func NewTripConsumer(rabbitMQ *messaging.RabbitMQ, service domain.PaymentService, publisher Publisher) *TripConsumer {
+	return &TripConsumer{
+		rabbitMQ:  rabbitMQ,
+		service:   service,
+		publisher: publisher,
+	}
+}
+
+func (c *TripConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.CreatePaymentSessionQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			if msg.RoutingKey == contracts.TripEventTipAdded {
+				return c.chargeTip(ctx, message.Data)
+			}
+
+			var payload messaging.TripEventData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the trip event: %v", err)
+			}
+
+			switch msg.RoutingKey {
+			case contracts.TripEventDriverAssigned, contracts.TripEventPoolRiderAdded:
+				// Pool riders joining after the assignment get their own session
+				if payload.Trip.GetDriver() == nil {
+					return nil
+				}
+
+				payments, err := c.service.CreateSessions(ctx, payload.Trip)
+				for _, payment := range payments {
+					if err := c.publisher.PublishPaymentEvent(ctx, contracts.PaymentEventSessionCreated, payment); err != nil {
+						log.Printf("Failed to publish the session of trip %s for %s: %v", payment.TripID, payment.UserID, err)
+					}
+				}
+				return err
+			case contracts.TripEventCompleted:
+				payments, err := c.service.CaptureTripPayments(ctx, payload.Trip)
+				PublishStatusEvents(ctx, c.publisher, payments)
+				return err
+			case contracts.TripEventCancelled:
+				payments, err := c.service.CancelTripPayments(ctx, payload.Trip.GetId())
+				PublishStatusEvents(ctx, c.publisher, payments)
+				return err
+			}
+
+			log.Printf("Unknown trip event: %s", msg.RoutingKey)
+			return nil
+		},
+	)
+}
+
+func (c *TripConsumer) chargeTip(ctx context.Context, data []byte) error {
+	var payload messaging.TripTipData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal the tip event: %v", err)
+	}
+
+	tip, err := c.service.ChargeTip(
+		ctx,
+		payload.TripID,
+		payload.Tip.GetUserID(),
+		payload.DriverID,
+		payload.Tip.GetAmountInCents(),
+		payload.Tip.GetCreatedAt().AsTime(),
+	)
+	if errors.Is(err, domain.ErrInvalidAmount) {
+		log.Printf("Dropping the tip of trip %s for %s: %v", payload.TripID, payload.Tip.GetUserID(), err)
+		return nil
+	}
+	if err != nil || tip == nil {
+		return err
+	}
+
+	if err := c.publisher.PublishPaymentEvent(ctx, contracts.PaymentEventSessionCreated, tip); err != nil {
+		log.Printf("Failed to publish the tip session of trip %s for %s: %v", tip.TripID, tip.UserID, err)
+	}
+	return nil
+}
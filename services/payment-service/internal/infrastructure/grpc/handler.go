@@ -0,0 +1,82 @@
+// Package grpc implements the gRPC handler for Payment operations
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/internal/infrastructure/events"
+	pb "ride-sharing/shared/proto/payment"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type handler struct {
+	pb.UnimplementedPaymentServiceServer
+
+	service   domain.PaymentService
+	publisher events.Publisher
+}
+
+func NewHandler(
+	server *grpc.Server,
+	service domain.PaymentService,
+	publisher events.Publisher,
+) *handler {
+	handler := &handler{
+		service:   service,
+		publisher: publisher,
+	}
+
+	pb.RegisterPaymentServiceServer(server, handler)
+
+	return handler
+}
+
+func (h *handler) RefundTrip(
+	ctx context.Context,
+	req *pb.RefundTripRequest,
+) (*pb.RefundTripResponse, error) {
+	if req.GetTripID() == "" {
+		return nil, status.Error(codes.InvalidArgument, "trip ID is required")
+	}
+
+	payments, refund, err := h.service.RefundPayment(
+		ctx,
+		req.GetTripID(),
+		req.GetUserID(),
+		req.GetAmountInCents(),
+		req.GetReason(),
+		req.GetReferenceID(),
+	)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentNotFound) {
+			return nil, status.Errorf(codes.NotFound, "refundTripErr: %v", err)
+		}
+		if errors.Is(err, domain.ErrInvalidRefund) {
+			return nil, status.Errorf(codes.InvalidArgument, "refundTripErr: %v", err)
+		}
+		if errors.Is(err, domain.ErrPaymentNotRefundable) || errors.Is(err, domain.ErrRefundExceedsPayment) {
+			return nil, status.Errorf(codes.FailedPrecondition, "refundTripErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "refundTripErr: %v", err)
+	}
+
+	// Retries publish the refund again, the consumers only apply it once
+	if err := h.publisher.PublishRefundEvent(ctx, payments, refund); err != nil {
+		return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
+	}
+
+	res := &pb.RefundTripResponse{
+		Refund:  refund.ToProto(),
+		Payment: payments[0].ToProto(),
+	}
+	if len(payments) > 1 {
+		res.Balance = payments[1].ToProto()
+	}
+
+	return res, nil
+}
@@ -0,0 +1,53 @@
+// Package poller follows the pending payments until the riders paid, or didn't
+package poller
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/internal/infrastructure/events"
+)
+
+type Poller struct {
+	service   domain.PaymentService
+	publisher events.Publisher
+	interval  time.Duration
+}
+
+func NewPoller(
+	service domain.PaymentService,
+	publisher events.Publisher,
+	interval time.Duration,
+) *Poller {
+	return &Poller{
+		service:   service,
+		publisher: publisher,
+		interval:  interval,
+	}
+}
+
+// Run checks the pending payments every interval until the context is done
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.tick(ctx)
+		}
+	}
+}
+
+func (p *Poller) tick(ctx context.Context) {
+	payments, err := p.service.SyncPendingPayments(ctx)
+	if err != nil {
+		log.Printf("Failed to sync some pending payments: %v", err)
+	}
+
+	events.PublishStatusEvents(ctx, p.publisher, payments)
+}
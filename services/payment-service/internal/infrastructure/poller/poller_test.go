@@ -0,0 +1,78 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/internal/infrastructure/events"
+	"ride-sharing/shared/contracts"
+)
+
+// stubService returns the payments changed by the sync, with its error
+type stubService struct {
+	domain.PaymentService
+	changed []*domain.PaymentModel
+	err     error
+}
+
+func (s *stubService) SyncPendingPayments(context.Context) ([]*domain.PaymentModel, error) {
+	return s.changed, s.err
+}
+
+// recordingPublisher records the routing keys of the published payment events
+type recordingPublisher struct {
+	events.Publisher
+	published []string
+	err       error
+}
+
+func (p *recordingPublisher) PublishPaymentEvent(
+	_ context.Context,
+	routingKey string,
+	payment *domain.PaymentModel,
+) error {
+	p.published = append(p.published, routingKey+" "+payment.UserID)
+	return p.err
+}
+
+func TestPollerTick(t *testing.T) {
+	changed := []*domain.PaymentModel{
+		{TripID: "trip", UserID: "paid", Status: domain.PaymentStatusSucceeded},
+		{TripID: "trip", UserID: "declined", Status: domain.PaymentStatusFailed},
+		{TripID: "trip", UserID: "expired", Status: domain.PaymentStatusCancelled},
+		{TripID: "trip", UserID: "open", Status: domain.PaymentStatusPending},
+	}
+	want := []string{
+		contracts.PaymentEventSuccess + " paid",
+		contracts.PaymentEventFailed + " declined",
+		contracts.PaymentEventCancelled + " expired",
+	}
+
+	tests := []struct {
+		name       string
+		syncErr    error
+		publishErr error
+	}{
+		{name: "sync succeeded"},
+		// Some sessions couldn't be checked, the others are still reported
+		{name: "sync failed partly", syncErr: errors.New("stripe unreachable")},
+		// A failed publish doesn't hold back the other events
+		{name: "publish failed", publishErr: errors.New("channel closed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			publisher := &recordingPublisher{err: tt.publishErr}
+			p := NewPoller(&stubService{changed: changed, err: tt.syncErr}, publisher, 0)
+
+			p.tick(context.Background())
+
+			if !slices.Equal(publisher.published, want) {
+				t.Errorf("published %v, want %v", publisher.published, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+)
+
+// FakeProvider keeps the checkout sessions in memory, they end up in the configured outcome after the delay.
+// It's meant for tests and local runs without a Stripe account.
+type FakeProvider struct {
+	outcome string
+	delay   time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*fakeSession
+	// byKey are the sessions by idempotency key
+	byKey map[string]string
+	seq   int
+}
+
+type fakeSession struct {
+	session   domain.Session
+	createdAt time.Time
+	// amount is what the session holds until it's captured, then what was captured
+	amount       int64
+	refunded     int64
+	captureLater bool
+}
+
+var fakeOutcomes = []string{
+	domain.SessionStatusPaid,
+	domain.SessionStatusFailed,
+	domain.SessionStatusExpired,
+	domain.SessionStatusOpen,
+}
+
+func NewFakeProvider(outcome string, delay time.Duration) (*FakeProvider, error) {
+	if !slices.Contains(fakeOutcomes, outcome) {
+		return nil, fmt.Errorf("invalid fake payment outcome %q, expected one of %v", outcome, fakeOutcomes)
+	}
+
+	return &FakeProvider{
+		outcome:  outcome,
+		delay:    delay,
+		sessions: make(map[string]*fakeSession),
+		byKey:    make(map[string]string),
+	}, nil
+}
+
+func (p *FakeProvider) CreateSession(ctx context.Context, req *domain.SessionRequest) (*domain.Session, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.byKey[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		session := p.sessions[id].session
+		return &session, nil
+	}
+
+	p.seq++
+	id := fmt.Sprintf("cs_fake_%d", p.seq)
+	p.sessions[id] = &fakeSession{
+		session:      domain.Session{ID: id, Status: domain.SessionStatusOpen},
+		createdAt:    time.Now(),
+		amount:       req.AmountInCents,
+		captureLater: req.CaptureLater,
+	}
+	if req.IdempotencyKey != "" {
+		p.byKey[req.IdempotencyKey] = id
+	}
+
+	session := p.sessions[id].session
+	return &session, nil
+}
+
+func (p *FakeProvider) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.sessions[sessionID]
+	if !ok {
+		return nil, fmt.Errorf("unknown session %s", sessionID)
+	}
+
+	s.settle(p.outcome, p.delay)
+
+	session := s.session
+	return &session, nil
+}
+
+func (p *FakeProvider) ExpireSession(ctx context.Context, sessionID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("unknown session %s", sessionID)
+	}
+
+	s.settle(p.outcome, p.delay)
+	if s.session.Status != domain.SessionStatusOpen {
+		return fmt.Errorf("session %s is %s, only open sessions can be expired", sessionID, s.session.Status)
+	}
+
+	s.session.Status = domain.SessionStatusExpired
+	return nil
+}
+
+func (p *FakeProvider) Refund(ctx context.Context, req *domain.RefundRequest) (*domain.ProviderRefund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if id, ok := p.byKey[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		return &domain.ProviderRefund{ID: id, Status: domain.RefundStatusSucceeded}, nil
+	}
+
+	s, ok := p.sessions[req.SessionID]
+	if !ok {
+		return nil, fmt.Errorf("unknown session %s", req.SessionID)
+	}
+
+	s.settle(p.outcome, p.delay)
+	if s.session.Status != domain.SessionStatusPaid {
+		return nil, fmt.Errorf("session %s is %s, only paid sessions can be refunded", req.SessionID, s.session.Status)
+	}
+	if s.refunded+req.AmountInCents > s.amount {
+		return nil, fmt.Errorf("refund of %d exceeds the %d left", req.AmountInCents, s.amount-s.refunded)
+	}
+
+	s.refunded += req.AmountInCents
+	p.seq++
+	id := fmt.Sprintf("re_fake_%d", p.seq)
+	if req.IdempotencyKey != "" {
+		p.byKey[req.IdempotencyKey] = id
+	}
+
+	return &domain.ProviderRefund{ID: id, Status: domain.RefundStatusSucceeded}, nil
+}
+
+func (p *FakeProvider) Capture(ctx context.Context, req *domain.CaptureRequest) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.byKey[req.IdempotencyKey]; ok && req.IdempotencyKey != "" {
+		return nil
+	}
+
+	s, ok := p.sessions[req.SessionID]
+	if !ok {
+		return fmt.Errorf("unknown session %s", req.SessionID)
+	}
+
+	s.settle(p.outcome, p.delay)
+	if s.session.Status != domain.SessionStatusAuthorized {
+		return fmt.Errorf("session %s is %s, only authorized sessions can be captured", req.SessionID, s.session.Status)
+	}
+	if req.AmountInCents > s.amount {
+		return fmt.Errorf("capture of %d exceeds the %d held", req.AmountInCents, s.amount)
+	}
+
+	s.session.Status = domain.SessionStatusPaid
+	s.amount = req.AmountInCents
+	if req.IdempotencyKey != "" {
+		p.byKey[req.IdempotencyKey] = req.SessionID
+	}
+
+	return nil
+}
+
+func (p *FakeProvider) ReleaseHold(ctx context.Context, sessionID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.sessions[sessionID]
+	if !ok {
+		return fmt.Errorf("unknown session %s", sessionID)
+	}
+
+	s.settle(p.outcome, p.delay)
+	if s.session.Status != domain.SessionStatusAuthorized {
+		return fmt.Errorf("session %s is %s, only authorized sessions can be released", sessionID, s.session.Status)
+	}
+
+	s.session.Status = domain.SessionStatusExpired
+	return nil
+}
+
+// settle moves the session to the outcome once the delay passed, paid sessions capturing later are authorized
+func (s *fakeSession) settle(outcome string, delay time.Duration) {
+	if s.session.Status != domain.SessionStatusOpen || time.Since(s.createdAt) < delay {
+		return
+	}
+
+	s.session.Status = outcome
+	if outcome == domain.SessionStatusPaid && s.captureLater {
+		s.session.Status = domain.SessionStatusAuthorized
+	}
+}
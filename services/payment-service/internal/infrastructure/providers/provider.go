@@ -0,0 +1,61 @@
+// Package providers implements the payment providers the checkout sessions are created with
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/shared/env"
+)
+
+const (
+	ProviderStripe = "stripe"
+	ProviderFake   = "fake"
+)
+
+type Config struct {
+	// Provider is stripe or fake
+	Provider string
+
+	StripeAPIURL    string
+	StripeSecretKey string
+	StripeTimeout   time.Duration
+	// SuccessURL and CancelURL are where the riders are sent back to after the checkout
+	SuccessURL string
+	CancelURL  string
+
+	// FakeOutcome is the session status the fake sessions end up in: paid, failed, expired or open
+	FakeOutcome string
+	// FakeDelay is how long the fake sessions stay open
+	FakeDelay time.Duration
+}
+
+// ConfigFromEnv reads the payment provider configuration from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		Provider:        env.GetString("PAYMENT_PROVIDER", ProviderFake),
+		StripeAPIURL:    env.GetString("STRIPE_API_URL", "https://api.stripe.com"),
+		StripeSecretKey: env.GetString("STRIPE_SECRET_KEY", ""),
+		StripeTimeout:   env.GetDuration("STRIPE_TIMEOUT", 10*time.Second),
+		SuccessURL:      env.GetString("PAYMENT_SUCCESS_URL", "http://localhost:3000?payment=success"),
+		CancelURL:       env.GetString("PAYMENT_CANCEL_URL", "http://localhost:3000?payment=cancel"),
+		FakeOutcome:     env.GetString("PAYMENT_FAKE_OUTCOME", domain.SessionStatusPaid),
+		FakeDelay:       env.GetDuration("PAYMENT_FAKE_DELAY", 10*time.Second),
+	}
+}
+
+// NewProvider builds the payment provider selected by the configuration
+func NewProvider(cfg Config) (domain.PaymentProvider, error) {
+	switch cfg.Provider {
+	case ProviderStripe:
+		if cfg.StripeSecretKey == "" {
+			return nil, fmt.Errorf("STRIPE_SECRET_KEY is required by the stripe provider")
+		}
+		return NewStripeProvider(cfg.StripeAPIURL, cfg.StripeSecretKey, cfg.SuccessURL, cfg.CancelURL, cfg.StripeTimeout), nil
+	case ProviderFake:
+		return NewFakeProvider(cfg.FakeOutcome, cfg.FakeDelay)
+	}
+
+	return nil, fmt.Errorf("unknown payment provider: %q", cfg.Provider)
+}
@@ -0,0 +1,295 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+)
+
+// Checkout session responses are small, anything bigger isn't a session
+const maxStripeResponseBytes = 1 << 20
+
+// stripeProvider talks to the Stripe API, or to anything implementing its checkout sessions (ex. stripe-mock)
+type stripeProvider struct {
+	baseURL    string
+	secretKey  string
+	successURL string
+	cancelURL  string
+	client     *http.Client
+}
+
+func NewStripeProvider(baseURL, secretKey, successURL, cancelURL string, timeout time.Duration) *stripeProvider {
+	return &stripeProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		secretKey:  secretKey,
+		successURL: successURL,
+		cancelURL:  cancelURL,
+		client:     &http.Client{Timeout: timeout},
+	}
+}
+
+// stripeSession is the part of the checkout session object we use
+type stripeSession struct {
+	ID            string            `json:"id"`
+	URL           string            `json:"url"`
+	Status        string            `json:"status"`         // open, complete or expired
+	PaymentStatus string            `json:"payment_status"` // paid, unpaid or no_payment_required
+	PaymentIntent string            `json:"payment_intent"`
+	Metadata      map[string]string `json:"metadata"`
+}
+
+// stripePaymentIntent is the part of the payment intent object we use
+type stripePaymentIntent struct {
+	ID string `json:"id"`
+	// requires_capture once a hold is placed, then succeeded or canceled. Earlier statuses mean the rider
+	// is still going through the checkout.
+	Status string `json:"status"`
+}
+
+type stripeRefund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"` // pending, requires_action, succeeded, failed or canceled
+}
+
+// stripeRefundReasons are the refund reasons Stripe knows of, the others are only kept in the metadata
+var stripeRefundReasons = []string{
+	domain.RefundReasonDuplicate,
+	domain.RefundReasonFraudulent,
+	domain.RefundReasonRequestedByCustomer,
+}
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (p *stripeProvider) CreateSession(ctx context.Context, req *domain.SessionRequest) (*domain.Session, error) {
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", p.successURL)
+	form.Set("cancel_url", p.cancelURL)
+	form.Set("client_reference_id", req.TripID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", req.Currency)
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(req.AmountInCents, 10))
+	form.Set("line_items[0][price_data][product_data][name]", req.Description)
+	form.Set("metadata[trip_id]", req.TripID)
+	form.Set("metadata[user_id]", req.UserID)
+	form.Set("metadata[kind]", req.Kind)
+	if req.PayerID != "" {
+		form.Set("metadata[payer_id]", req.PayerID)
+	}
+	if req.DriverID != "" {
+		form.Set("metadata[driver_id]", req.DriverID)
+	}
+	if req.CaptureLater {
+		form.Set("payment_intent_data[capture_method]", "manual")
+		// Tells the webhooks the session only places a hold
+		form.Set("metadata[capture]", "manual")
+	}
+
+	session := new(stripeSession)
+	if err := p.do(ctx, http.MethodPost, "/v1/checkout/sessions", form, req.IdempotencyKey, session); err != nil {
+		return nil, fmt.Errorf("failed to create the checkout session: %w", err)
+	}
+
+	return session.toDomain(), nil
+}
+
+func (p *stripeProvider) GetSession(ctx context.Context, sessionID string) (*domain.Session, error) {
+	session := new(stripeSession)
+	if err := p.do(ctx, http.MethodGet, "/v1/checkout/sessions/"+url.PathEscape(sessionID), nil, "", session); err != nil {
+		return nil, fmt.Errorf("failed to get the checkout session: %w", err)
+	}
+
+	if session.Status != "complete" || session.Metadata["capture"] != "manual" || session.PaymentIntent == "" {
+		return session.toDomain(), nil
+	}
+
+	// Sessions capturing later are followed through their payment intent
+	intent := new(stripePaymentIntent)
+	if err := p.do(ctx, http.MethodGet, "/v1/payment_intents/"+url.PathEscape(session.PaymentIntent), nil, "", intent); err != nil {
+		return nil, fmt.Errorf("failed to get the payment intent: %w", err)
+	}
+
+	result := &domain.Session{ID: session.ID, URL: session.URL, Status: domain.SessionStatusOpen}
+	switch intent.Status {
+	case "requires_capture":
+		result.Status = domain.SessionStatusAuthorized
+	case "succeeded":
+		result.Status = domain.SessionStatusPaid
+	case "canceled":
+		result.Status = domain.SessionStatusExpired
+	}
+
+	return result, nil
+}
+
+func (p *stripeProvider) ExpireSession(ctx context.Context, sessionID string) error {
+	session := new(stripeSession)
+	path := "/v1/checkout/sessions/" + url.PathEscape(sessionID) + "/expire"
+	if err := p.do(ctx, http.MethodPost, path, url.Values{}, "", session); err != nil {
+		// Sessions which aren't open anymore can't be expired, the sync will tell what happened to them
+		return fmt.Errorf("failed to expire the checkout session: %w", err)
+	}
+
+	return nil
+}
+
+func (p *stripeProvider) Refund(ctx context.Context, req *domain.RefundRequest) (*domain.ProviderRefund, error) {
+	// Refunds are made on the payment intent of the session
+	paymentIntent, err := p.paymentIntentID(ctx, req.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("payment_intent", paymentIntent)
+	form.Set("amount", strconv.FormatInt(req.AmountInCents, 10))
+	form.Set("metadata[reason]", req.Reason)
+	if slices.Contains(stripeRefundReasons, req.Reason) {
+		form.Set("reason", req.Reason)
+	}
+
+	refund := new(stripeRefund)
+	if err := p.do(ctx, http.MethodPost, "/v1/refunds", form, req.IdempotencyKey, refund); err != nil {
+		return nil, fmt.Errorf("failed to create the refund: %w", err)
+	}
+
+	if refund.Status == "failed" || refund.Status == "canceled" {
+		return nil, fmt.Errorf("refund %s %s", refund.ID, refund.Status)
+	}
+
+	return &domain.ProviderRefund{ID: refund.ID, Status: refund.Status}, nil
+}
+
+func (p *stripeProvider) Capture(ctx context.Context, req *domain.CaptureRequest) error {
+	paymentIntent, err := p.paymentIntentID(ctx, req.SessionID)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Set("amount_to_capture", strconv.FormatInt(req.AmountInCents, 10))
+
+	intent := new(stripePaymentIntent)
+	path := "/v1/payment_intents/" + url.PathEscape(paymentIntent) + "/capture"
+	if err := p.do(ctx, http.MethodPost, path, form, req.IdempotencyKey, intent); err != nil {
+		return fmt.Errorf("failed to capture the payment: %w", err)
+	}
+
+	if intent.Status != "succeeded" {
+		return fmt.Errorf("payment intent %s is %s after the capture", intent.ID, intent.Status)
+	}
+
+	return nil
+}
+
+func (p *stripeProvider) ReleaseHold(ctx context.Context, sessionID string) error {
+	paymentIntent, err := p.paymentIntentID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	form.Set("cancellation_reason", "requested_by_customer")
+
+	intent := new(stripePaymentIntent)
+	path := "/v1/payment_intents/" + url.PathEscape(paymentIntent) + "/cancel"
+	if err := p.do(ctx, http.MethodPost, path, form, "", intent); err != nil {
+		return fmt.Errorf("failed to release the hold: %w", err)
+	}
+
+	return nil
+}
+
+// paymentIntentID returns the payment intent of the session, which only has one once the rider went through
+// the checkout
+func (p *stripeProvider) paymentIntentID(ctx context.Context, sessionID string) (string, error) {
+	session := new(stripeSession)
+	if err := p.do(ctx, http.MethodGet, "/v1/checkout/sessions/"+url.PathEscape(sessionID), nil, "", session); err != nil {
+		return "", fmt.Errorf("failed to get the checkout session: %w", err)
+	}
+	if session.PaymentIntent == "" {
+		return "", fmt.Errorf("checkout session %s has no payment", sessionID)
+	}
+
+	return session.PaymentIntent, nil
+}
+
+func (p *stripeProvider) do(
+	ctx context.Context,
+	method, path string,
+	form url.Values,
+	idempotencyKey string,
+	out any,
+) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to build the request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxStripeResponseBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read the response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		stripeErr := new(stripeError)
+		if err := json.Unmarshal(data, stripeErr); err != nil || stripeErr.Error.Message == "" {
+			return fmt.Errorf("stripe responded with status %d", res.StatusCode)
+		}
+		return fmt.Errorf("stripe responded with status %d (%s): %s", res.StatusCode, stripeErr.Error.Type, stripeErr.Error.Message)
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse the response: %w", err)
+	}
+
+	return nil
+}
+
+func (s *stripeSession) toDomain() *domain.Session {
+	session := &domain.Session{ID: s.ID, URL: s.URL, Status: domain.SessionStatusOpen}
+
+	switch {
+	case s.Status == "expired":
+		session.Status = domain.SessionStatusExpired
+	case s.Status == "complete" && s.PaymentStatus != "unpaid":
+		session.Status = domain.SessionStatusPaid
+	}
+	// Completed but unpaid sessions are delayed payment methods still processing, they're reported by the webhooks
+
+	return session
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+)
+
+func TestStripeSessionStatus(t *testing.T) {
+	tests := []struct {
+		status        string
+		paymentStatus string
+		want          string
+	}{
+		{status: "open", paymentStatus: "unpaid", want: domain.SessionStatusOpen},
+		{status: "complete", paymentStatus: "paid", want: domain.SessionStatusPaid},
+		{status: "complete", paymentStatus: "no_payment_required", want: domain.SessionStatusPaid},
+		// Delayed payment methods still processing
+		{status: "complete", paymentStatus: "unpaid", want: domain.SessionStatusOpen},
+		{status: "expired", paymentStatus: "unpaid", want: domain.SessionStatusExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status+" "+tt.paymentStatus, func(t *testing.T) {
+			session := &stripeSession{ID: "cs_test", Status: tt.status, PaymentStatus: tt.paymentStatus}
+			if got := session.toDomain().Status; got != tt.want {
+				t.Errorf("status = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripeProviderCreateSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Idempotency-Key") != "session:trip:rider" {
+			t.Errorf("Idempotency-Key = %q", r.Header.Get("Idempotency-Key"))
+		}
+		if r.Header.Get("Authorization") != "Bearer sk_test" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("line_items[0][price_data][unit_amount]") != "1250" {
+			t.Errorf("unit amount = %q, want 1250", r.PostForm.Get("line_items[0][price_data][unit_amount]"))
+		}
+
+		_, _ = w.Write([]byte(`{"id":"cs_test","url":"https://checkout.test/cs_test","status":"open","payment_status":"unpaid"}`))
+	}))
+	defer server.Close()
+
+	provider := NewStripeProvider(server.URL, "sk_test", "http://success", "http://cancel", time.Second)
+	session, err := provider.CreateSession(context.Background(), &domain.SessionRequest{
+		TripID:         "trip",
+		UserID:         "rider",
+		AmountInCents:  1_250,
+		Currency:       "usd",
+		IdempotencyKey: "session:trip:rider",
+	})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	if session.ID != "cs_test" || session.Status != domain.SessionStatusOpen {
+		t.Errorf("session = %+v", session)
+	}
+}
+
+func TestStripeProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"type":"invalid_request_error","message":"No such checkout.session"}}`))
+	}))
+	defer server.Close()
+
+	provider := NewStripeProvider(server.URL, "sk_test", "http://success", "http://cancel", time.Second)
+	_, err := provider.GetSession(context.Background(), "cs_missing")
+	if err == nil || !strings.Contains(err.Error(), "No such checkout.session") {
+		t.Errorf("GetSession() error = %v, want the Stripe message", err)
+	}
+}
@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+)
+
+type inMemRepository struct {
+	mu sync.RWMutex
+	// payments by trip, then by rider
+	payments map[string]map[string]*domain.PaymentModel
+	// tips by trip, then by rider
+	tips map[string]map[string]*domain.PaymentModel
+	// balances by trip, then by rider
+	balances map[string]map[string]*domain.PaymentModel
+}
+
+func NewInMemRepository() *inMemRepository {
+	return &inMemRepository{
+		payments: make(map[string]map[string]*domain.PaymentModel),
+		tips:     make(map[string]map[string]*domain.PaymentModel),
+		balances: make(map[string]map[string]*domain.PaymentModel),
+	}
+}
+
+func (r *inMemRepository) CreatePayment(ctx context.Context, payment *domain.PaymentModel) error {
+	return r.create(r.payments, payment)
+}
+
+func (r *inMemRepository) GetPayment(ctx context.Context, tripID, userID string) (*domain.PaymentModel, error) {
+	return r.get(r.payments, tripID, userID)
+}
+
+func (r *inMemRepository) SetPaymentStatus(
+	ctx context.Context,
+	tripID, userID, from, to string,
+) (*domain.PaymentModel, error) {
+	return r.setStatus(r.payments, tripID, userID, from, to)
+}
+
+func (r *inMemRepository) ListTripPayments(ctx context.Context, tripID string) ([]*domain.PaymentModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payments := make([]*domain.PaymentModel, 0, len(r.payments[tripID]))
+	for _, payment := range r.payments[tripID] {
+		payments = append(payments, clonePayment(payment))
+	}
+
+	return payments, nil
+}
+
+func (r *inMemRepository) ListPaymentsByStatus(ctx context.Context, status string) ([]*domain.PaymentModel, error) {
+	return r.listByStatus(r.payments, status)
+}
+
+func (r *inMemRepository) ReassignPayment(
+	ctx context.Context,
+	tripID, userID, sessionID string,
+	payerID, newSessionID, checkoutURL string,
+) (*domain.PaymentModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, ok := r.payments[tripID][userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tripID, userID)
+	}
+
+	if payment.Status != domain.PaymentStatusPending || payment.SessionID != sessionID {
+		return nil, fmt.Errorf("%w: %s on session %s", domain.ErrPaymentStatusChanged, payment.Status, payment.SessionID)
+	}
+
+	payment.PayerID = payerID
+	payment.SessionID = newSessionID
+	payment.CheckoutURL = checkoutURL
+	payment.UpdatedAt = time.Now()
+
+	return clonePayment(payment), nil
+}
+
+func (r *inMemRepository) CreateTip(ctx context.Context, tip *domain.PaymentModel) error {
+	return r.create(r.tips, tip)
+}
+
+func (r *inMemRepository) GetTip(ctx context.Context, tripID, userID string) (*domain.PaymentModel, error) {
+	return r.get(r.tips, tripID, userID)
+}
+
+func (r *inMemRepository) SetTipStatus(
+	ctx context.Context,
+	tripID, userID, from, to string,
+) (*domain.PaymentModel, error) {
+	return r.setStatus(r.tips, tripID, userID, from, to)
+}
+
+func (r *inMemRepository) ReplaceTip(ctx context.Context, from string, tip *domain.PaymentModel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.tips[tip.TripID][tip.UserID]
+	if !ok {
+		return fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tip.TripID, tip.UserID)
+	}
+
+	if existing.Status != from {
+		return fmt.Errorf("%w: %s, not %s", domain.ErrPaymentStatusChanged, existing.Status, from)
+	}
+
+	r.tips[tip.TripID][tip.UserID] = clonePayment(tip)
+	return nil
+}
+
+func (r *inMemRepository) ListTipsByStatus(ctx context.Context, status string) ([]*domain.PaymentModel, error) {
+	return r.listByStatus(r.tips, status)
+}
+
+func (r *inMemRepository) CreateBalance(ctx context.Context, balance *domain.PaymentModel) error {
+	return r.create(r.balances, balance)
+}
+
+func (r *inMemRepository) GetBalance(ctx context.Context, tripID, userID string) (*domain.PaymentModel, error) {
+	return r.get(r.balances, tripID, userID)
+}
+
+func (r *inMemRepository) SetBalanceStatus(
+	ctx context.Context,
+	tripID, userID, from, to string,
+) (*domain.PaymentModel, error) {
+	return r.setStatus(r.balances, tripID, userID, from, to)
+}
+
+func (r *inMemRepository) ListBalancesByStatus(ctx context.Context, status string) ([]*domain.PaymentModel, error) {
+	return r.listByStatus(r.balances, status)
+}
+
+func (r *inMemRepository) create(store map[string]map[string]*domain.PaymentModel, payment *domain.PaymentModel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	riders, ok := store[payment.TripID]
+	if !ok {
+		riders = make(map[string]*domain.PaymentModel)
+		store[payment.TripID] = riders
+	}
+
+	if _, ok := riders[payment.UserID]; ok {
+		return fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentExists, payment.TripID, payment.UserID)
+	}
+
+	riders[payment.UserID] = clonePayment(payment)
+	return nil
+}
+
+func (r *inMemRepository) get(
+	store map[string]map[string]*domain.PaymentModel,
+	tripID, userID string,
+) (*domain.PaymentModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	payment, ok := store[tripID][userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tripID, userID)
+	}
+
+	return clonePayment(payment), nil
+}
+
+func (r *inMemRepository) setStatus(
+	store map[string]map[string]*domain.PaymentModel,
+	tripID, userID, from, to string,
+) (*domain.PaymentModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, ok := store[tripID][userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tripID, userID)
+	}
+
+	if payment.Status != from {
+		return nil, fmt.Errorf("%w: %s, not %s", domain.ErrPaymentStatusChanged, payment.Status, from)
+	}
+
+	payment.Status = to
+	payment.UpdatedAt = time.Now()
+
+	return clonePayment(payment), nil
+}
+
+func (r *inMemRepository) listByStatus(
+	store map[string]map[string]*domain.PaymentModel,
+	status string,
+) ([]*domain.PaymentModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var payments []*domain.PaymentModel
+	for _, riders := range store {
+		for _, payment := range riders {
+			if payment.Status == status {
+				payments = append(payments, clonePayment(payment))
+			}
+		}
+	}
+
+	return payments, nil
+}
+
+func (r *inMemRepository) SetCaptureAmount(
+	ctx context.Context,
+	tripID, userID string,
+	amountInCents int64,
+) (*domain.PaymentModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, ok := r.payments[tripID][userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tripID, userID)
+	}
+
+	payment.CaptureInCents = amountInCents
+	payment.UpdatedAt = time.Now()
+
+	return clonePayment(payment), nil
+}
+
+func (r *inMemRepository) CapturePayment(
+	ctx context.Context,
+	tripID, userID string,
+	amountInCents int64,
+) (*domain.PaymentModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, ok := r.payments[tripID][userID]
+	if !ok {
+		return nil, fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tripID, userID)
+	}
+
+	if payment.Status != domain.PaymentStatusAuthorized {
+		return nil, fmt.Errorf("%w: %s, not %s", domain.ErrPaymentStatusChanged, payment.Status, domain.PaymentStatusAuthorized)
+	}
+
+	payment.Status = domain.PaymentStatusSucceeded
+	payment.AmountInCents = amountInCents
+	payment.UpdatedAt = time.Now()
+
+	return clonePayment(payment), nil
+}
+
+func (r *inMemRepository) ReserveRefund(
+	ctx context.Context,
+	tripID, userID string,
+	refund *domain.RefundModel,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	return r.reserveRefund(r.payments, tripID, userID, refund)
+}
+
+func (r *inMemRepository) CompleteRefund(
+	ctx context.Context,
+	tripID, userID, refundID, providerRefundID string,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	return r.completeRefund(r.payments, tripID, userID, refundID, providerRefundID)
+}
+
+func (r *inMemRepository) ReleaseRefund(ctx context.Context, tripID, userID, refundID string) error {
+	return r.releaseRefund(r.payments, tripID, userID, refundID)
+}
+
+func (r *inMemRepository) ReserveBalanceRefund(
+	ctx context.Context,
+	tripID, userID string,
+	refund *domain.RefundModel,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	return r.reserveRefund(r.balances, tripID, userID, refund)
+}
+
+func (r *inMemRepository) CompleteBalanceRefund(
+	ctx context.Context,
+	tripID, userID, refundID, providerRefundID string,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	return r.completeRefund(r.balances, tripID, userID, refundID, providerRefundID)
+}
+
+func (r *inMemRepository) ReleaseBalanceRefund(ctx context.Context, tripID, userID, refundID string) error {
+	return r.releaseRefund(r.balances, tripID, userID, refundID)
+}
+
+func (r *inMemRepository) reserveRefund(
+	store map[string]map[string]*domain.PaymentModel,
+	tripID, userID string,
+	refund *domain.RefundModel,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, ok := store[tripID][userID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tripID, userID)
+	}
+
+	if existing := payment.FindRefund(refund.ReferenceID); existing != nil {
+		found := *existing
+		return clonePayment(payment), &found, nil
+	}
+
+	if payment.Status != domain.PaymentStatusSucceeded {
+		return nil, nil, fmt.Errorf("%w: payment is %s", domain.ErrPaymentNotRefundable, payment.Status)
+	}
+
+	if refund.AmountInCents > payment.RefundableInCents() {
+		return nil, nil, fmt.Errorf(
+			"%w: %d asked, %d left to refund",
+			domain.ErrRefundExceedsPayment, refund.AmountInCents, payment.RefundableInCents(),
+		)
+	}
+
+	stored := *refund
+	payment.Refunds = append(payment.Refunds, &stored)
+	payment.RefundedInCents += refund.AmountInCents
+	payment.UpdatedAt = time.Now()
+
+	return clonePayment(payment), refund, nil
+}
+
+func (r *inMemRepository) completeRefund(
+	store map[string]map[string]*domain.PaymentModel,
+	tripID, userID, refundID, providerRefundID string,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, refund, err := r.findRefund(store, tripID, userID, refundID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refund.Status = domain.RefundStatusSucceeded
+	refund.ProviderRefundID = providerRefundID
+	if payment.RefundedInCents >= payment.AmountInCents {
+		payment.Status = domain.PaymentStatusRefunded
+	}
+	payment.UpdatedAt = time.Now()
+
+	found := *refund
+	return clonePayment(payment), &found, nil
+}
+
+func (r *inMemRepository) releaseRefund(
+	store map[string]map[string]*domain.PaymentModel,
+	tripID, userID, refundID string,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, refund, err := r.findRefund(store, tripID, userID, refundID)
+	if err != nil {
+		return err
+	}
+
+	payment.Refunds = slices.DeleteFunc(payment.Refunds, func(other *domain.RefundModel) bool {
+		return other == refund
+	})
+	payment.RefundedInCents -= refund.AmountInCents
+	payment.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// findRefund must be called with the mutex held
+func (r *inMemRepository) findRefund(
+	store map[string]map[string]*domain.PaymentModel,
+	tripID, userID, refundID string,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	payment, ok := store[tripID][userID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: trip %s, rider %s", domain.ErrPaymentNotFound, tripID, userID)
+	}
+
+	for _, refund := range payment.Refunds {
+		if refund.ID == refundID {
+			return payment, refund, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("refund %s not found on the payment of trip %s for %s", refundID, tripID, userID)
+}
+
+// clonePayment copies the payment and its refunds, the stored payments are only changed with the mutex held
+func clonePayment(payment *domain.PaymentModel) *domain.PaymentModel {
+	clone := *payment
+	clone.Refunds = make([]*domain.RefundModel, len(payment.Refunds))
+	for i, refund := range payment.Refunds {
+		r := *refund
+		clone.Refunds[i] = &r
+	}
+
+	return &clone
+}
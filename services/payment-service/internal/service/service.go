@@ -0,0 +1,867 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/shared/env"
+	pb "ride-sharing/shared/proto/trip"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Config struct {
+	Currency string
+	// Mode is charge or hold
+	Mode string
+	// HoldBuffer is the fraction added to the quoted fare in the hold mode, for routes longer than quoted
+	HoldBuffer float64
+}
+
+// ConfigFromEnv reads the payment configuration from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		Currency:   env.GetString("PAYMENT_CURRENCY", "usd"),
+		Mode:       env.GetString("PAYMENT_MODE", domain.PaymentModeCharge),
+		HoldBuffer: env.GetFloat("PAYMENT_HOLD_BUFFER", 0.2),
+	}
+}
+
+type service struct {
+	repo     domain.PaymentRepository
+	provider domain.PaymentProvider
+	cfg      Config
+}
+
+func NewService(repo domain.PaymentRepository, provider domain.PaymentProvider, cfg Config) (*service, error) {
+	if cfg.Mode != domain.PaymentModeCharge && cfg.Mode != domain.PaymentModeHold {
+		return nil, fmt.Errorf("unknown payment mode %q, expected %s or %s", cfg.Mode, domain.PaymentModeCharge, domain.PaymentModeHold)
+	}
+	if cfg.HoldBuffer < 0 {
+		return nil, fmt.Errorf("the hold buffer can't be negative: %v", cfg.HoldBuffer)
+	}
+
+	return &service{
+		repo:     repo,
+		provider: provider,
+		cfg:      cfg,
+	}, nil
+}
+
+// splitStatusAccepted is the status of the participants who agreed to split the fare with the owner
+const splitStatusAccepted = "accepted"
+
+// riderCharge is what a rider of the trip owes
+type riderCharge struct {
+	userID string
+	fare   float64 // cents
+	// fallbackPayerID pays when the rider doesn't, the owner for the participants of a split fare
+	fallbackPayerID string
+}
+
+// tripCharges lists what every rider of the trip owes, out of the final fare once the trip is completed.
+// Pool riders pay their own quoted fare, the fare of the other trips is split between the owner and the
+// participants who accepted to share it.
+func tripCharges(trip *pb.Trip) []riderCharge {
+	if len(trip.GetRiders()) > 1 {
+		charges := make([]riderCharge, len(trip.GetRiders()))
+		for i, rider := range trip.GetRiders() {
+			charges[i] = riderCharge{userID: rider.GetUserID(), fare: rider.GetFare().GetTotalPriceInCents()}
+		}
+
+		return charges
+	}
+
+	fare := trip.GetSelectedFare().GetTotalPriceInCents()
+	if final := trip.GetFinalFare(); final != nil {
+		fare = final.GetTotalPriceInCents()
+	}
+
+	var participants []string
+	for _, participant := range trip.GetSplitParticipants() {
+		if participant.GetStatus() == splitStatusAccepted {
+			participants = append(participants, participant.GetUserID())
+		}
+	}
+
+	if len(participants) == 0 {
+		return []riderCharge{{userID: trip.GetUserID(), fare: fare}}
+	}
+
+	// Equal shares in whole cents, the owner pays the cents left over
+	total := math.Round(fare)
+	share := math.Floor(total / float64(len(participants)+1))
+
+	charges := []riderCharge{{userID: trip.GetUserID(), fare: total - share*float64(len(participants))}}
+	for _, userID := range participants {
+		charges = append(charges, riderCharge{userID: userID, fare: share, fallbackPayerID: trip.GetUserID()})
+	}
+
+	return charges
+}
+
+func (s *service) CreateSessions(ctx context.Context, trip *pb.Trip) ([]*domain.PaymentModel, error) {
+	var (
+		created []*domain.PaymentModel
+		errs    []error
+	)
+
+	for _, charge := range tripCharges(trip) {
+		// Fares discounted in full, ex. by a promo code, have nothing to charge
+		if math.Round(charge.fare) == 0 {
+			continue
+		}
+
+		_, err := s.repo.GetPayment(ctx, trip.GetId(), charge.userID)
+		if err == nil {
+			// Redelivered event, or a pool rider who already has a session
+			continue
+		}
+		if !errors.Is(err, domain.ErrPaymentNotFound) {
+			errs = append(errs, err)
+			continue
+		}
+
+		payment, err := s.createSession(ctx, trip, charge)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create the session of %s: %w", charge.userID, err))
+			continue
+		}
+		if payment != nil {
+			created = append(created, payment)
+		}
+	}
+
+	return created, errors.Join(errs...)
+}
+
+// createSession returns no payment when a concurrent delivery created it first
+func (s *service) createSession(ctx context.Context, trip *pb.Trip, charge riderCharge) (*domain.PaymentModel, error) {
+	amount := int64(math.Round(charge.fare))
+	if amount <= 0 {
+		return nil, fmt.Errorf("%w: %d", domain.ErrInvalidAmount, amount)
+	}
+
+	req := &domain.SessionRequest{
+		TripID:         trip.GetId(),
+		UserID:         charge.userID,
+		Kind:           domain.PaymentKindFare,
+		DriverID:       trip.GetDriver().GetId(),
+		AmountInCents:  amount,
+		Currency:       s.cfg.Currency,
+		Description:    fmt.Sprintf("%s ride", trip.GetSelectedFare().GetPackageSlug()),
+		IdempotencyKey: "session:" + trip.GetId() + ":" + charge.userID,
+	}
+
+	var hold int64
+	if s.cfg.Mode == domain.PaymentModeHold {
+		hold = int64(math.Round(charge.fare * (1 + s.cfg.HoldBuffer)))
+		req.AmountInCents = hold
+		req.CaptureLater = true
+	}
+
+	session, err := s.provider.CreateSession(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payment := &domain.PaymentModel{
+		TripID:          trip.GetId(),
+		UserID:          charge.userID,
+		DriverID:        trip.GetDriver().GetId(),
+		FallbackPayerID: charge.fallbackPayerID,
+		Kind:            domain.PaymentKindFare,
+		AmountInCents:   amount,
+		HoldInCents:     hold,
+		Currency:        s.cfg.Currency,
+		Status:          domain.PaymentStatusPending,
+		SessionID:       session.ID,
+		CheckoutURL:     session.URL,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := s.repo.CreatePayment(ctx, payment); err != nil {
+		if errors.Is(err, domain.ErrPaymentExists) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("Created the checkout session %s of trip %s for %s", session.ID, payment.TripID, payment.UserID)
+
+	return payment, nil
+}
+
+func (s *service) ChargeTip(
+	ctx context.Context,
+	tripID, userID, driverID string,
+	amountInCents int64,
+	tippedAt time.Time,
+) (*domain.PaymentModel, error) {
+	if amountInCents <= 0 {
+		return nil, fmt.Errorf("%w: %d", domain.ErrInvalidAmount, amountInCents)
+	}
+
+	existing, err := s.repo.GetTip(ctx, tripID, userID)
+	switch {
+	case err == nil:
+		// Redelivered event, unless the rider tipped again after the tip failed
+		retried := existing.Status == domain.PaymentStatusFailed || existing.Status == domain.PaymentStatusCancelled
+		if !retried || !existing.CreatedAt.Before(tippedAt) {
+			return nil, nil
+		}
+	case !errors.Is(err, domain.ErrPaymentNotFound):
+		return nil, err
+	}
+
+	// Tips are charged right away, whatever the payment mode, the trip is already completed
+	session, err := s.provider.CreateSession(ctx, &domain.SessionRequest{
+		TripID:         tripID,
+		UserID:         userID,
+		Kind:           domain.PaymentKindTip,
+		DriverID:       driverID,
+		AmountInCents:  amountInCents,
+		Currency:       s.cfg.Currency,
+		Description:    "Tip for your driver",
+		IdempotencyKey: fmt.Sprintf("tip:%s:%s:%d", tripID, userID, tippedAt.UnixMilli()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the tip session of %s: %w", userID, err)
+	}
+
+	now := time.Now()
+	tip := &domain.PaymentModel{
+		TripID:        tripID,
+		UserID:        userID,
+		DriverID:      driverID,
+		Kind:          domain.PaymentKindTip,
+		AmountInCents: amountInCents,
+		Currency:      s.cfg.Currency,
+		Status:        domain.PaymentStatusPending,
+		SessionID:     session.ID,
+		CheckoutURL:   session.URL,
+		// The retries are told apart from the redelivered events by when the rider tipped
+		CreatedAt: tippedAt,
+		UpdatedAt: now,
+	}
+
+	if existing != nil {
+		err = s.repo.ReplaceTip(ctx, existing.Status, tip)
+	} else {
+		err = s.repo.CreateTip(ctx, tip)
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentExists) || errors.Is(err, domain.ErrPaymentStatusChanged) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("Created the tip session %s of trip %s for %s", session.ID, tripID, userID)
+
+	return tip, nil
+}
+
+func (s *service) CancelTripPayments(ctx context.Context, tripID string) ([]*domain.PaymentModel, error) {
+	payments, err := s.repo.ListTripPayments(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cancelled []*domain.PaymentModel
+		errs      []error
+	)
+
+	for _, payment := range payments {
+		switch payment.Status {
+		case domain.PaymentStatusPending:
+			if err := s.provider.ExpireSession(ctx, payment.SessionID); err != nil {
+				errs = append(errs, fmt.Errorf("failed to expire the session %s: %w", payment.SessionID, err))
+				continue
+			}
+		case domain.PaymentStatusAuthorized:
+			if err := s.provider.ReleaseHold(ctx, payment.SessionID); err != nil {
+				errs = append(errs, fmt.Errorf("failed to release the hold of the session %s: %w", payment.SessionID, err))
+				continue
+			}
+		default:
+			continue
+		}
+
+		updated, err := s.setStatus(ctx, payment, domain.PaymentStatusCancelled)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if updated != nil {
+			cancelled = append(cancelled, updated)
+		}
+	}
+
+	return cancelled, errors.Join(errs...)
+}
+
+func (s *service) SyncPendingPayments(ctx context.Context) ([]*domain.PaymentModel, error) {
+	payments, err := s.repo.ListPaymentsByStatus(ctx, domain.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	tips, err := s.repo.ListTipsByStatus(ctx, domain.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	payments = append(payments, tips...)
+
+	balances, err := s.repo.ListBalancesByStatus(ctx, domain.PaymentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+	payments = append(payments, balances...)
+
+	var (
+		changed []*domain.PaymentModel
+		errs    []error
+	)
+
+	// Captures which failed on the trip completion
+	authorized, err := s.repo.ListPaymentsByStatus(ctx, domain.PaymentStatusAuthorized)
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range authorized {
+		if payment.CaptureInCents == 0 {
+			continue
+		}
+
+		captured, balance, err := s.capture(ctx, payment)
+		changed = append(changed, changedPayments(captured, balance)...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, payment := range payments {
+		session, err := s.provider.GetSession(ctx, payment.SessionID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get the session %s: %w", payment.SessionID, err))
+			continue
+		}
+
+		var status string
+		switch session.Status {
+		case domain.SessionStatusPaid:
+			status = domain.PaymentStatusSucceeded
+		case domain.SessionStatusAuthorized:
+			status = domain.PaymentStatusAuthorized
+		case domain.SessionStatusFailed:
+			status = domain.PaymentStatusFailed
+		case domain.SessionStatusExpired:
+			status = domain.PaymentStatusCancelled
+		default:
+			continue
+		}
+
+		updated, err := s.settle(ctx, payment, status)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed = append(changed, updated...)
+	}
+
+	return changed, errors.Join(errs...)
+}
+
+func (s *service) SettlePayment(
+	ctx context.Context,
+	tripID, userID, kind, sessionID, status string,
+) ([]*domain.PaymentModel, error) {
+	getPayment := s.repo.GetPayment
+	switch kind {
+	case domain.PaymentKindTip:
+		getPayment = s.repo.GetTip
+	case domain.PaymentKindBalance:
+		getPayment = s.repo.GetBalance
+	}
+
+	payment, err := getPayment(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.Status != domain.PaymentStatusPending {
+		return nil, nil
+	}
+
+	// The session of a rider whose payment was charged to someone else since
+	if sessionID != "" && sessionID != payment.SessionID {
+		return nil, nil
+	}
+
+	return s.settle(ctx, payment, status)
+}
+
+// settle moves the pending payment to the status, the holds of the trips completed in the meantime are
+// captured right away. The payments which failed are charged to their fallback payer instead, they stay
+// pending. It returns the payment, followed by its balance when the capture opened one.
+func (s *service) settle(
+	ctx context.Context,
+	payment *domain.PaymentModel,
+	status string,
+) ([]*domain.PaymentModel, error) {
+	if payment.NeedsFallback(status) {
+		reassigned, err := s.chargeFallbackPayer(ctx, payment)
+		return changedPayments(reassigned), err
+	}
+
+	updated, err := s.setStatus(ctx, payment, status)
+	if err != nil || updated == nil {
+		return nil, err
+	}
+
+	if updated.Status != domain.PaymentStatusAuthorized || updated.CaptureInCents == 0 {
+		return changedPayments(updated), nil
+	}
+
+	captured, balance, err := s.capture(ctx, updated)
+	if err != nil {
+		// The hold is placed, the capture is retried by the next sync
+		log.Printf("Failed to capture the payment of trip %s for %s: %v", updated.TripID, updated.UserID, err)
+	}
+	if captured == nil {
+		captured = updated
+	}
+
+	return changedPayments(captured, balance), nil
+}
+
+// chargeFallbackPayer opens a session for the fallback payer of the pending payment. It returns no payment
+// when the payment moved on concurrently.
+func (s *service) chargeFallbackPayer(ctx context.Context, payment *domain.PaymentModel) (*domain.PaymentModel, error) {
+	req := &domain.SessionRequest{
+		TripID:         payment.TripID,
+		UserID:         payment.UserID,
+		PayerID:        payment.FallbackPayerID,
+		Kind:           payment.Kind,
+		DriverID:       payment.DriverID,
+		AmountInCents:  payment.AmountInCents,
+		Currency:       payment.Currency,
+		Description:    fmt.Sprintf("Share of %s in the ride", payment.UserID),
+		IdempotencyKey: "fallback:" + payment.TripID + ":" + payment.UserID,
+	}
+	if payment.IsHold() {
+		req.AmountInCents = payment.HoldInCents
+		req.CaptureLater = true
+	}
+
+	session, err := s.provider.CreateSession(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the session of %s for the share of %s: %w", payment.FallbackPayerID, payment.UserID, err)
+	}
+
+	reassigned, err := s.repo.ReassignPayment(
+		ctx,
+		payment.TripID,
+		payment.UserID,
+		payment.SessionID,
+		payment.FallbackPayerID,
+		session.ID,
+		session.URL,
+	)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentStatusChanged) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update the payment of trip %s for %s: %w", payment.TripID, payment.UserID, err)
+	}
+
+	log.Printf(
+		"Share of %s in trip %s wasn't paid, charging %s through the session %s",
+		payment.UserID, payment.TripID, payment.FallbackPayerID, session.ID,
+	)
+
+	return reassigned, nil
+}
+
+// setStatus moves the payment from its current status to the status. It returns no payment when the payment
+// was moved on concurrently, ex. cancelled while its session was being checked.
+func (s *service) setStatus(
+	ctx context.Context,
+	payment *domain.PaymentModel,
+	status string,
+) (*domain.PaymentModel, error) {
+	setStatus := s.repo.SetPaymentStatus
+	switch {
+	case payment.IsTip():
+		setStatus = s.repo.SetTipStatus
+	case payment.IsBalance():
+		setStatus = s.repo.SetBalanceStatus
+	}
+
+	updated, err := setStatus(ctx, payment.TripID, payment.UserID, payment.Status, status)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentStatusChanged) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update the payment of trip %s for %s: %w", payment.TripID, payment.UserID, err)
+	}
+
+	log.Printf("Payment of trip %s for %s (%s) is %s", payment.TripID, payment.UserID, payment.Kind, status)
+
+	return updated, nil
+}
+
+func (s *service) RefundPayment(
+	ctx context.Context,
+	tripID, userID string,
+	amountInCents int64,
+	reason, referenceID string,
+) ([]*domain.PaymentModel, *domain.RefundModel, error) {
+	if referenceID == "" {
+		return nil, nil, fmt.Errorf("%w: a reference is required", domain.ErrInvalidRefund)
+	}
+	if !slices.Contains(domain.RefundReasons, reason) {
+		return nil, nil, fmt.Errorf("%w: unknown reason %q", domain.ErrInvalidRefund, reason)
+	}
+	if amountInCents < 0 {
+		return nil, nil, fmt.Errorf("%w: negative amount %d", domain.ErrInvalidRefund, amountInCents)
+	}
+
+	payment, err := s.riderPayment(ctx, tripID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The final fare over the hold was paid on a balance session, the refund spans both
+	payments := []*domain.PaymentModel{payment}
+	balance, err := s.repo.GetBalance(ctx, payment.TripID, payment.UserID)
+	switch {
+	case err == nil:
+		payments = append(payments, balance)
+	case !errors.Is(err, domain.ErrPaymentNotFound):
+		return nil, nil, err
+	}
+
+	amounts, err := splitRefund(payments, amountInCents, referenceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var refund *domain.RefundModel
+	for i, payment := range payments {
+		if amounts[i] == 0 {
+			continue
+		}
+
+		refunded, part, err := s.refundSession(ctx, payment, amounts[i], reason, referenceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		payments[i] = refunded
+
+		if refund == nil {
+			refund = part
+			continue
+		}
+		refund.AmountInCents += part.AmountInCents
+	}
+
+	log.Printf(
+		"Refunded %d of the payment of trip %s for %s (%s)",
+		refund.AmountInCents, payment.TripID, payment.UserID, reason,
+	)
+
+	return payments, refund, nil
+}
+
+// splitRefund tells how much of the refund each payment gives back, the fare first. The payments which
+// already have a refund with the reference keep its amount, a retry going on with the rest.
+func splitRefund(payments []*domain.PaymentModel, amountInCents int64, referenceID string) ([]int64, error) {
+	amounts := make([]int64, len(payments))
+
+	var retriedInCents, refundableInCents int64
+	for i, payment := range payments {
+		if existing := payment.FindRefund(referenceID); existing != nil {
+			amounts[i] = existing.AmountInCents
+			retriedInCents += existing.AmountInCents
+			continue
+		}
+		refundableInCents += payment.RefundableInCents()
+	}
+
+	if retriedInCents == 0 && refundableInCents == 0 {
+		return nil, fmt.Errorf("%w: nothing left to refund", domain.ErrPaymentNotRefundable)
+	}
+	if amountInCents == 0 {
+		// Everything left, the first call with the reference included
+		amountInCents = retriedInCents + refundableInCents
+	}
+
+	leftInCents := amountInCents - retriedInCents
+	if leftInCents < 0 {
+		return nil, fmt.Errorf("%w: reference %s was used for another refund", domain.ErrInvalidRefund, referenceID)
+	}
+
+	for i, payment := range payments {
+		if payment.FindRefund(referenceID) != nil {
+			continue
+		}
+		amounts[i] = min(leftInCents, payment.RefundableInCents())
+		leftInCents -= amounts[i]
+	}
+
+	if leftInCents > 0 {
+		return nil, fmt.Errorf(
+			"%w: %d asked, %d left to refund",
+			domain.ErrRefundExceedsPayment, amountInCents, retriedInCents+refundableInCents,
+		)
+	}
+
+	return amounts, nil
+}
+
+// refundSession refunds the amount of the session of the payment, a fare or its balance, unless the
+// payment already has a succeeded refund with the reference
+func (s *service) refundSession(
+	ctx context.Context,
+	payment *domain.PaymentModel,
+	amountInCents int64,
+	reason, referenceID string,
+) (*domain.PaymentModel, *domain.RefundModel, error) {
+	reserve, complete, release := s.repo.ReserveRefund, s.repo.CompleteRefund, s.repo.ReleaseRefund
+	if payment.IsBalance() {
+		reserve, complete, release = s.repo.ReserveBalanceRefund, s.repo.CompleteBalanceRefund, s.repo.ReleaseBalanceRefund
+	}
+
+	payment, refund, err := reserve(ctx, payment.TripID, payment.UserID, &domain.RefundModel{
+		ID:            primitive.NewObjectID().Hex(),
+		ReferenceID:   referenceID,
+		AmountInCents: amountInCents,
+		Reason:        reason,
+		Status:        domain.RefundStatusPending,
+		CreatedAt:     time.Now(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case refund.AmountInCents != amountInCents || refund.Reason != reason:
+		return nil, nil, fmt.Errorf("%w: reference %s was used for another refund", domain.ErrInvalidRefund, referenceID)
+	case refund.Status == domain.RefundStatusSucceeded:
+		return payment, refund, nil
+	}
+
+	// A concurrent call with the same reference gets the same pending refund, so the provider refunds once
+	providerRefund, err := s.provider.Refund(ctx, &domain.RefundRequest{
+		SessionID:      payment.SessionID,
+		AmountInCents:  refund.AmountInCents,
+		Reason:         reason,
+		IdempotencyKey: "refund:" + refund.ID,
+	})
+	if err != nil {
+		if releaseErr := release(ctx, payment.TripID, payment.UserID, refund.ID); releaseErr != nil {
+			log.Printf("Failed to release the refund %s: %v", refund.ID, releaseErr)
+		}
+		return nil, nil, fmt.Errorf(
+			"failed to refund the %s payment of trip %s for %s: %w",
+			payment.Kind, payment.TripID, payment.UserID, err,
+		)
+	}
+
+	return complete(ctx, payment.TripID, payment.UserID, refund.ID, providerRefund.ID)
+}
+
+// riderPayment returns the payment of the rider, or the only payment of the trip when no rider is given
+func (s *service) riderPayment(ctx context.Context, tripID, userID string) (*domain.PaymentModel, error) {
+	if userID != "" {
+		return s.repo.GetPayment(ctx, tripID, userID)
+	}
+
+	payments, err := s.repo.ListTripPayments(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(payments) {
+	case 0:
+		return nil, fmt.Errorf("%w: trip %s", domain.ErrPaymentNotFound, tripID)
+	case 1:
+		return payments[0], nil
+	}
+
+	return nil, fmt.Errorf("%w: trip %s has %d riders, the rider is required", domain.ErrInvalidRefund, tripID, len(payments))
+}
+
+func (s *service) CaptureTripPayments(ctx context.Context, trip *pb.Trip) ([]*domain.PaymentModel, error) {
+	charges := tripCharges(trip)
+
+	var (
+		captured []*domain.PaymentModel
+		errs     []error
+	)
+
+	for _, charge := range charges {
+		payment, err := s.repo.GetPayment(ctx, trip.GetId(), charge.userID)
+		if err != nil {
+			if !errors.Is(err, domain.ErrPaymentNotFound) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		// Charged up front
+		if !payment.IsHold() {
+			continue
+		}
+
+		amount := int64(math.Round(charge.fare))
+		if amount <= 0 {
+			errs = append(errs, fmt.Errorf("%w: final fare of %d for %s", domain.ErrInvalidAmount, amount, charge.userID))
+			continue
+		}
+
+		updated, err := s.repo.SetCaptureAmount(ctx, payment.TripID, payment.UserID, amount)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// Pending holds are captured once they're placed
+		if updated.Status != domain.PaymentStatusAuthorized {
+			continue
+		}
+
+		payment, balance, err := s.capture(ctx, updated)
+		captured = append(captured, changedPayments(payment, balance)...)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return captured, errors.Join(errs...)
+}
+
+// capture charges the final fare of the authorized payment, up to what's held. The part of the final fare over
+// the hold is charged on a balance session, opened before the capture so that a failed capture retried by the
+// sync doesn't lose it. It returns the new balance, if any, and no captured payment when the payment was
+// captured or released concurrently.
+func (s *service) capture(
+	ctx context.Context,
+	payment *domain.PaymentModel,
+) (captured, balance *domain.PaymentModel, err error) {
+	amount := payment.CaptureInCents
+	if amount > payment.HoldInCents {
+		log.Printf(
+			"Final fare of %d for trip %s of %s is over the %d held, charging the rest on its own session",
+			amount, payment.TripID, payment.UserID, payment.HoldInCents,
+		)
+
+		balance, err = s.chargeBalance(ctx, payment, amount-payment.HoldInCents)
+		if err != nil {
+			return nil, nil, err
+		}
+		amount = payment.HoldInCents
+	}
+
+	err = s.provider.Capture(ctx, &domain.CaptureRequest{
+		SessionID:      payment.SessionID,
+		AmountInCents:  amount,
+		IdempotencyKey: "capture:" + payment.TripID + ":" + payment.UserID,
+	})
+	if err != nil {
+		return nil, balance, fmt.Errorf("failed to capture the payment of trip %s for %s: %w", payment.TripID, payment.UserID, err)
+	}
+
+	captured, err = s.repo.CapturePayment(ctx, payment.TripID, payment.UserID, amount)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentStatusChanged) {
+			return nil, balance, nil
+		}
+		return nil, balance, fmt.Errorf("failed to update the payment of trip %s for %s: %w", payment.TripID, payment.UserID, err)
+	}
+
+	log.Printf("Captured %d of the %d held for trip %s of %s", amount, payment.HoldInCents, payment.TripID, payment.UserID)
+
+	return captured, balance, nil
+}
+
+// chargeBalance opens the session of the rest of the final fare the hold doesn't cover, for the payer of the
+// hold. It returns no payment when the balance already exists.
+func (s *service) chargeBalance(
+	ctx context.Context,
+	payment *domain.PaymentModel,
+	amountInCents int64,
+) (*domain.PaymentModel, error) {
+	_, err := s.repo.GetBalance(ctx, payment.TripID, payment.UserID)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, domain.ErrPaymentNotFound) {
+		return nil, err
+	}
+
+	session, err := s.provider.CreateSession(ctx, &domain.SessionRequest{
+		TripID:         payment.TripID,
+		UserID:         payment.UserID,
+		PayerID:        payment.PayerID,
+		Kind:           domain.PaymentKindBalance,
+		DriverID:       payment.DriverID,
+		AmountInCents:  amountInCents,
+		Currency:       payment.Currency,
+		Description:    "Rest of the final fare",
+		IdempotencyKey: "balance:" + payment.TripID + ":" + payment.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the balance session of trip %s for %s: %w", payment.TripID, payment.UserID, err)
+	}
+
+	now := time.Now()
+	balance := &domain.PaymentModel{
+		TripID:        payment.TripID,
+		UserID:        payment.UserID,
+		DriverID:      payment.DriverID,
+		Kind:          domain.PaymentKindBalance,
+		PayerID:       payment.PayerID,
+		AmountInCents: amountInCents,
+		Currency:      payment.Currency,
+		Status:        domain.PaymentStatusPending,
+		SessionID:     session.ID,
+		CheckoutURL:   session.URL,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.repo.CreateBalance(ctx, balance); err != nil {
+		if errors.Is(err, domain.ErrPaymentExists) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	log.Printf("Created the balance session %s of trip %s for %s", session.ID, payment.TripID, payment.UserID)
+
+	return balance, nil
+}
+
+// changedPayments drops the payments which didn't change
+func changedPayments(payments ...*domain.PaymentModel) []*domain.PaymentModel {
+	changed := make([]*domain.PaymentModel, 0, len(payments))
+	for _, payment := range payments {
+		if payment != nil {
+			changed = append(changed, payment)
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ride-sharing/services/payment-service/internal/domain"
+	"ride-sharing/services/payment-service/internal/infrastructure/providers"
+	"ride-sharing/services/payment-service/internal/infrastructure/repository"
+	pb "ride-sharing/shared/proto/trip"
+)
+
+// newTestService settles the sessions in the outcome as soon as they're checked
+func newTestService(t *testing.T, outcome string) *service {
+	t.Helper()
+
+	provider, err := providers.NewFakeProvider(outcome, 0)
+	if err != nil {
+		t.Fatalf("NewFakeProvider() error = %v", err)
+	}
+
+	s, err := NewService(repository.NewInMemRepository(), provider, Config{Currency: "usd", Mode: domain.PaymentModeCharge})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	return s
+}
+
+func testTrip(fares map[string]float64) *pb.Trip {
+	trip := &pb.Trip{
+		Id:           "trip",
+		UserID:       "owner",
+		SelectedFare: &pb.RideFare{PackageSlug: "pool", TotalPriceInCents: fares["owner"]},
+		Driver:       &pb.TripDriver{Id: "driver"},
+	}
+	for _, userID := range []string{"owner", "rider"} {
+		if fare, ok := fares[userID]; ok {
+			trip.Riders = append(trip.Riders, &pb.TripRider{UserID: userID, Fare: &pb.RideFare{TotalPriceInCents: fare}})
+		}
+	}
+
+	return trip
+}
+
+func TestCreateSessionsIdempotent(t *testing.T) {
+	s := newTestService(t, domain.SessionStatusOpen)
+	ctx := context.Background()
+	trip := testTrip(map[string]float64{"owner": 1_250.4, "rider": 800})
+
+	created, err := s.CreateSessions(ctx, trip)
+	if err != nil {
+		t.Fatalf("CreateSessions() error = %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("%d payments created, want one per rider", len(created))
+	}
+	if created[0].AmountInCents != 1_250 || created[0].Status != domain.PaymentStatusPending {
+		t.Errorf("payment of the owner = %d cents %s, want 1250 cents pending", created[0].AmountInCents, created[0].Status)
+	}
+
+	// The trip event was delivered again
+	again, err := s.CreateSessions(ctx, trip)
+	if err != nil {
+		t.Fatalf("CreateSessions() again error = %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("%d payments created again, want none", len(again))
+	}
+
+	payments, err := s.repo.ListTripPayments(ctx, "trip")
+	if err != nil {
+		t.Fatalf("ListTripPayments() error = %v", err)
+	}
+	if len(payments) != 2 {
+		t.Errorf("%d payments stored, want 2", len(payments))
+	}
+}
+
+func TestCreateSessionsInvalidAmount(t *testing.T) {
+	s := newTestService(t, domain.SessionStatusOpen)
+
+	created, err := s.CreateSessions(context.Background(), testTrip(map[string]float64{"owner": -100, "rider": 800}))
+	if !errors.Is(err, domain.ErrInvalidAmount) {
+		t.Errorf("CreateSessions() error = %v, want %v", err, domain.ErrInvalidAmount)
+	}
+	// The other riders are charged anyway
+	if len(created) != 1 || created[0].UserID != "rider" {
+		t.Errorf("%d payments created, want the one of rider", len(created))
+	}
+}
+
+func TestCreateSessionsFreeFare(t *testing.T) {
+	s := newTestService(t, domain.SessionStatusOpen)
+
+	// The promo code of the owner covered the whole fare
+	created, err := s.CreateSessions(context.Background(), testTrip(map[string]float64{"owner": 0.4, "rider": 800}))
+	if err != nil {
+		t.Fatalf("CreateSessions() error = %v", err)
+	}
+	if len(created) != 1 || created[0].UserID != "rider" {
+		t.Errorf("%d payments created, want the one of rider", len(created))
+	}
+}
+
+func TestSyncPendingPayments(t *testing.T) {
+	tests := []struct {
+		outcome    string
+		wantStatus string
+	}{
+		{outcome: domain.SessionStatusPaid, wantStatus: domain.PaymentStatusSucceeded},
+		{outcome: domain.SessionStatusFailed, wantStatus: domain.PaymentStatusFailed},
+		{outcome: domain.SessionStatusExpired, wantStatus: domain.PaymentStatusCancelled},
+		{outcome: domain.SessionStatusOpen, wantStatus: domain.PaymentStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outcome, func(t *testing.T) {
+			s := newTestService(t, tt.outcome)
+			ctx := context.Background()
+
+			if _, err := s.CreateSessions(ctx, testTrip(map[string]float64{"owner": 1_000})); err != nil {
+				t.Fatalf("CreateSessions() error = %v", err)
+			}
+
+			changed, err := s.SyncPendingPayments(ctx)
+			if err != nil {
+				t.Fatalf("SyncPendingPayments() error = %v", err)
+			}
+			if wantChanged := tt.wantStatus != domain.PaymentStatusPending; (len(changed) == 1) != wantChanged {
+				t.Errorf("%d payments changed, want changed: %v", len(changed), wantChanged)
+			}
+
+			payment, err := s.repo.GetPayment(ctx, "trip", "owner")
+			if err != nil {
+				t.Fatalf("GetPayment() error = %v", err)
+			}
+			if payment.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", payment.Status, tt.wantStatus)
+			}
+
+			// The final payments aren't checked again
+			if changed, _ := s.SyncPendingPayments(ctx); len(changed) != 0 {
+				t.Errorf("%d payments changed on the second sync, want none", len(changed))
+			}
+		})
+	}
+}
+
+func TestCancelTripPayments(t *testing.T) {
+	s := newTestService(t, domain.SessionStatusOpen)
+	ctx := context.Background()
+
+	if _, err := s.CreateSessions(ctx, testTrip(map[string]float64{"owner": 1_000, "rider": 500})); err != nil {
+		t.Fatalf("CreateSessions() error = %v", err)
+	}
+	// The rider paid before the trip was cancelled
+	_, err := s.repo.SetPaymentStatus(ctx, "trip", "rider", domain.PaymentStatusPending, domain.PaymentStatusSucceeded)
+	if err != nil {
+		t.Fatalf("SetPaymentStatus() error = %v", err)
+	}
+
+	cancelled, err := s.CancelTripPayments(ctx, "trip")
+	if err != nil {
+		t.Fatalf("CancelTripPayments() error = %v", err)
+	}
+	if len(cancelled) != 1 || cancelled[0].UserID != "owner" || cancelled[0].Status != domain.PaymentStatusCancelled {
+		t.Fatalf("cancelled %d payments, want the pending one of owner", len(cancelled))
+	}
+
+	// The session was expired, it can't be paid anymore
+	payment, _ := s.repo.GetPayment(ctx, "trip", "owner")
+	session, err := s.provider.GetSession(ctx, payment.SessionID)
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	if session.Status != domain.SessionStatusExpired {
+		t.Errorf("session status = %s, want %s", session.Status, domain.SessionStatusExpired)
+	}
+
+	if again, err := s.CancelTripPayments(ctx, "trip"); err != nil || len(again) != 0 {
+		t.Errorf("CancelTripPayments() again = %d payments, %v, want none", len(again), err)
+	}
+}
+
+// createCapturedPayment stores a payment of the rider captured on a paid session of the fake provider
+func createCapturedPayment(t *testing.T, s *service, kind string, amountInCents int64) {
+	t.Helper()
+	ctx := context.Background()
+
+	session, err := s.provider.CreateSession(ctx, &domain.SessionRequest{TripID: "trip", UserID: "rider", AmountInCents: amountInCents})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	payment := &domain.PaymentModel{
+		TripID:        "trip",
+		UserID:        "rider",
+		Kind:          kind,
+		AmountInCents: amountInCents,
+		Status:        domain.PaymentStatusSucceeded,
+		SessionID:     session.ID,
+	}
+
+	create := s.repo.CreatePayment
+	if payment.IsBalance() {
+		create = s.repo.CreateBalance
+	}
+	if err := create(ctx, payment); err != nil {
+		t.Fatalf("creating the %s payment error = %v", kind, err)
+	}
+}
+
+func TestRefundPaymentWithBalance(t *testing.T) {
+	s := newTestService(t, domain.SessionStatusPaid)
+	ctx := context.Background()
+
+	// The final fare was 1500, 1000 were held and the balance paid the rest
+	createCapturedPayment(t, s, domain.PaymentKindFare, 1_000)
+	createCapturedPayment(t, s, domain.PaymentKindBalance, 500)
+
+	payments, refund, err := s.RefundPayment(ctx, "trip", "rider", 1_200, domain.RefundReasonServiceIssue, "ref-1")
+	if err != nil {
+		t.Fatalf("RefundPayment() error = %v", err)
+	}
+	if refund.AmountInCents != 1_200 || len(payments) != 2 {
+		t.Fatalf("refunded %d over %d payments, want 1200 over the fare and its balance", refund.AmountInCents, len(payments))
+	}
+	if payments[0].RefundedInCents != 1_000 || payments[0].Status != domain.PaymentStatusRefunded {
+		t.Errorf("fare refunded %d and %s, want 1000 and refunded", payments[0].RefundedInCents, payments[0].Status)
+	}
+	if payments[1].RefundedInCents != 200 || payments[1].Status != domain.PaymentStatusSucceeded {
+		t.Errorf("balance refunded %d and %s, want 200 and succeeded", payments[1].RefundedInCents, payments[1].Status)
+	}
+
+	// The retry gets the same refund back
+	_, again, err := s.RefundPayment(ctx, "trip", "rider", 1_200, domain.RefundReasonServiceIssue, "ref-1")
+	if err != nil || again.AmountInCents != 1_200 || again.ID != refund.ID {
+		t.Errorf("RefundPayment() retry = %v, %v, want the first refund", again, err)
+	}
+
+	// Only 300 are left of the balance
+	_, _, err = s.RefundPayment(ctx, "trip", "rider", 400, domain.RefundReasonServiceIssue, "ref-2")
+	if !errors.Is(err, domain.ErrRefundExceedsPayment) {
+		t.Errorf("RefundPayment() over the total error = %v, want %v", err, domain.ErrRefundExceedsPayment)
+	}
+
+	payments, refund, err = s.RefundPayment(ctx, "trip", "rider", 0, domain.RefundReasonServiceIssue, "ref-3")
+	if err != nil {
+		t.Fatalf("RefundPayment() of the rest error = %v", err)
+	}
+	if refund.AmountInCents != 300 || payments[1].Status != domain.PaymentStatusRefunded {
+		t.Errorf("refunded %d, balance %s, want the 300 left and refunded", refund.AmountInCents, payments[1].Status)
+	}
+
+	_, _, err = s.RefundPayment(ctx, "trip", "rider", 0, domain.RefundReasonServiceIssue, "ref-4")
+	if !errors.Is(err, domain.ErrPaymentNotRefundable) {
+		t.Errorf("RefundPayment() of a refunded payment error = %v, want %v", err, domain.ErrPaymentNotRefundable)
+	}
+}
+
+func TestSettlePaymentFallback(t *testing.T) {
+	for _, tt := range []struct {
+		status    string
+		wantPayer string
+	}{
+		{domain.PaymentStatusFailed, "owner"},
+		{domain.PaymentStatusCancelled, ""},
+	} {
+		t.Run(tt.status, func(t *testing.T) {
+			s := newTestService(t, domain.SessionStatusOpen)
+			ctx := context.Background()
+
+			// The share of a participant of a split fare, covered by the owner
+			err := s.repo.CreatePayment(ctx, &domain.PaymentModel{
+				TripID:          "trip",
+				UserID:          "rider",
+				Kind:            domain.PaymentKindFare,
+				FallbackPayerID: "owner",
+				AmountInCents:   500,
+				Status:          domain.PaymentStatusPending,
+				SessionID:       "cs_rider",
+			})
+			if err != nil {
+				t.Fatalf("CreatePayment() error = %v", err)
+			}
+
+			if _, err := s.SettlePayment(ctx, "trip", "rider", domain.PaymentKindFare, "cs_rider", tt.status); err != nil {
+				t.Fatalf("SettlePayment() error = %v", err)
+			}
+
+			payment, _ := s.repo.GetPayment(ctx, "trip", "rider")
+			if payment.PayerID != tt.wantPayer {
+				t.Errorf("payer = %q, want %q", payment.PayerID, tt.wantPayer)
+			}
+			if tt.wantPayer == "" && payment.Status != tt.status {
+				t.Errorf("status = %s, want %s", payment.Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestChargeTipAfterFailure(t *testing.T) {
+	s := newTestService(t, domain.SessionStatusOpen)
+	ctx := context.Background()
+	tippedAt := time.Now()
+
+	first, err := s.ChargeTip(ctx, "trip", "rider", "driver", 300, tippedAt)
+	if err != nil || first == nil {
+		t.Fatalf("ChargeTip() = %v, %v, want the tip", first, err)
+	}
+	if _, err := s.SettlePayment(ctx, "trip", "rider", domain.PaymentKindTip, first.SessionID, domain.PaymentStatusFailed); err != nil {
+		t.Fatalf("SettlePayment() error = %v", err)
+	}
+
+	// The event of the failed tip was redelivered
+	if again, err := s.ChargeTip(ctx, "trip", "rider", "driver", 300, tippedAt); err != nil || again != nil {
+		t.Errorf("ChargeTip() redelivered = %v, %v, want no tip", again, err)
+	}
+
+	retried, err := s.ChargeTip(ctx, "trip", "rider", "driver", 500, tippedAt.Add(time.Minute))
+	if err != nil || retried == nil {
+		t.Fatalf("ChargeTip() after the failure = %v, %v, want a new tip", retried, err)
+	}
+	if retried.SessionID == first.SessionID || retried.Status != domain.PaymentStatusPending || retried.AmountInCents != 500 {
+		t.Errorf("tip = %d cents %s on %s, want 500 pending on a new session",
+			retried.AmountInCents, retried.Status, retried.SessionID)
+	}
+}
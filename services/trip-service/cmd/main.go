@@ -12,8 +12,11 @@ import (
 	"ride-sharing/services/trip-service/internal/infrastructure/events"
 	infraGRPC "ride-sharing/services/trip-service/internal/infrastructure/grpc"
 	"ride-sharing/services/trip-service/internal/infrastructure/repository"
+	"ride-sharing/services/trip-service/internal/infrastructure/routing"
+	"ride-sharing/services/trip-service/internal/infrastructure/scheduler"
 	"ride-sharing/services/trip-service/internal/service"
 	"ride-sharing/shared/env"
+	"ride-sharing/shared/geojson"
 	"ride-sharing/shared/messaging"
 
 	"google.golang.org/grpc"
@@ -22,8 +25,36 @@ import (
 const GRPCAddr = ":9083"
 
 func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	inMemRepo := repository.NewInMemRepository()
-	svc := service.NewService(inMemRepo)
+
+	routeProvider, err := routing.NewProvider(ctx, routing.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to create the route provider: %v", err)
+	}
+
+	// Trips can go anywhere unless service areas are configured
+	var serviceAreas geojson.ServiceAreas
+	if areasFile := env.GetString("SERVICE_AREAS_FILE", ""); areasFile != "" {
+		serviceAreas, err = geojson.LoadServiceAreas(areasFile)
+		if err != nil {
+			log.Fatalf("Failed to load the service areas: %v", err)
+		}
+		log.Printf("Loaded %d service areas", len(serviceAreas))
+	}
+
+	// Promo codes are only accepted for the configured campaigns
+	if campaignsFile := env.GetString("PROMO_CAMPAIGNS_FILE", ""); campaignsFile != "" {
+		count, err := repository.LoadPromoCampaigns(ctx, inMemRepo, campaignsFile)
+		if err != nil {
+			log.Fatalf("Failed to load the promo campaigns: %v", err)
+		}
+		log.Printf("Loaded %d promo campaigns", count)
+	}
+
+	svc := service.NewService(inMemRepo, routeProvider, serviceAreas)
 
 	listener, err := net.Listen("tcp", GRPCAddr)
 	if err != nil {
@@ -41,6 +72,28 @@ func main() {
 
 	publisher := events.NewPublisher(rabbitMQ)
 
+	tripScheduler := scheduler.NewScheduler(
+		svc,
+		publisher,
+		env.GetDuration("SCHEDULER_INTERVAL", 30*time.Second),
+	)
+	go tripScheduler.Run(ctx)
+
+	driverConsumer := events.NewDriverConsumer(rabbitMQ, svc, publisher)
+	if err := driverConsumer.Listen(); err != nil {
+		log.Fatalf("Failed to listen to the driver events: %v", err)
+	}
+
+	paymentConsumer := events.NewPaymentConsumer(rabbitMQ, svc)
+	if err := paymentConsumer.Listen(); err != nil {
+		log.Fatalf("Failed to listen to the payment events: %v", err)
+	}
+
+	splitConsumer := events.NewSplitConsumer(rabbitMQ, svc, publisher)
+	if err := splitConsumer.Listen(); err != nil {
+		log.Fatalf("Failed to listen to the split responses: %v", err)
+	}
+
 	grpcServer := grpc.NewServer()
 	_ = infraGRPC.NewHandler(grpcServer, svc, publisher)
 
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Promo discount types
+const (
+	// PromoTypePercent takes a percentage off the fare
+	PromoTypePercent = "percent"
+	// PromoTypeFixed takes a fixed amount off the fare
+	PromoTypeFixed = "fixed"
+)
+
+var (
+	// ErrInvalidPromo is returned when a campaign is misconfigured
+	ErrInvalidPromo = errors.New("invalid promo campaign")
+	// ErrPromoNotFound is returned when no campaign has the promo code
+	ErrPromoNotFound = errors.New("promo code not found")
+	// ErrPromoExpired is returned outside of the validity period of the campaign
+	ErrPromoExpired = errors.New("promo code expired")
+	// ErrPromoNotEligible is returned when the rider or none of the fares can use the promo code
+	ErrPromoNotEligible = errors.New("promo code not eligible")
+	// ErrPromoExhausted is returned when the campaign, or the rider, reached the redemption limit
+	ErrPromoExhausted = errors.New("promo code fully redeemed")
+)
+
+// PromoCampaignModel is a discount the riders get with its promo code
+type PromoCampaignModel struct {
+	Code string `json:"code"`
+	// Type is percent or fixed
+	Type             string  `json:"type"`
+	PercentOff       float64 `json:"percentOff,omitempty"`
+	AmountOffInCents float64 `json:"amountOffInCents,omitempty"`
+	// MaxDiscountInCents caps the discount, 0 for no cap
+	MaxDiscountInCents float64 `json:"maxDiscountInCents,omitempty"`
+	// StartsAt and ExpiresAt bound the validity period, zero for no bound
+	StartsAt  time.Time `json:"startsAt,omitzero"`
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+	// MaxRedemptions is the limit for all the riders, MaxRedemptionsPerUser for each of them, 0 for no limit
+	MaxRedemptions        int `json:"maxRedemptions,omitempty"`
+	MaxRedemptionsPerUser int `json:"maxRedemptionsPerUser,omitempty"`
+	// PackageSlugs restricts the discount to these packages, all of them when empty
+	PackageSlugs []string `json:"packageSlugs,omitempty"`
+	// FirstRideOnly restricts the promo code to the riders who never booked a trip
+	FirstRideOnly bool `json:"firstRideOnly,omitempty"`
+}
+
+// PromoRedemptionModel is a promo code used to book a trip, one per fare
+type PromoRedemptionModel struct {
+	Code            string
+	UserID          string
+	FareID          string
+	DiscountInCents float64
+	RedeemedAt      time.Time
+}
+
+// NormalizePromoCode makes the promo codes case insensitive
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks the campaign configuration
+func (c *PromoCampaignModel) Validate() error {
+	if c.Code == "" {
+		return fmt.Errorf("%w: the code is required", ErrInvalidPromo)
+	}
+
+	switch c.Type {
+	case PromoTypePercent:
+		if c.PercentOff <= 0 || c.PercentOff > 100 {
+			return fmt.Errorf("%w: %s has a percentage of %v", ErrInvalidPromo, c.Code, c.PercentOff)
+		}
+	case PromoTypeFixed:
+		if c.AmountOffInCents <= 0 {
+			return fmt.Errorf("%w: %s has an amount of %v", ErrInvalidPromo, c.Code, c.AmountOffInCents)
+		}
+	default:
+		return fmt.Errorf("%w: %s has an unknown type %q", ErrInvalidPromo, c.Code, c.Type)
+	}
+
+	switch {
+	case c.MaxDiscountInCents < 0:
+		return fmt.Errorf("%w: %s has a negative cap", ErrInvalidPromo, c.Code)
+	case c.MaxRedemptions < 0 || c.MaxRedemptionsPerUser < 0:
+		return fmt.Errorf("%w: %s has a negative redemption limit", ErrInvalidPromo, c.Code)
+	case !c.StartsAt.IsZero() && !c.ExpiresAt.IsZero() && !c.StartsAt.Before(c.ExpiresAt):
+		return fmt.Errorf("%w: %s expires before it starts", ErrInvalidPromo, c.Code)
+	}
+
+	return nil
+}
+
+// CheckActive returns ErrPromoExpired outside of the validity period
+func (c *PromoCampaignModel) CheckActive(now time.Time) error {
+	if !c.StartsAt.IsZero() && now.Before(c.StartsAt) {
+		return fmt.Errorf("%w: %s starts at %s", ErrPromoExpired, c.Code, c.StartsAt.Format(time.RFC3339))
+	}
+	if !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt) {
+		return fmt.Errorf("%w: %s expired at %s", ErrPromoExpired, c.Code, c.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// AppliesTo tells whether the campaign discounts the package
+func (c *PromoCampaignModel) AppliesTo(packageSlug string) bool {
+	return len(c.PackageSlugs) == 0 || slices.Contains(c.PackageSlugs, packageSlug)
+}
+
+// Discount is what the campaign takes off the price, in whole cents and never more than the price
+func (c *PromoCampaignModel) Discount(priceInCents float64) float64 {
+	discount := c.AmountOffInCents
+	if c.Type == PromoTypePercent {
+		discount = priceInCents * c.PercentOff / 100
+	}
+
+	if c.MaxDiscountInCents > 0 {
+		discount = min(discount, c.MaxDiscountInCents)
+	}
+
+	return math.Round(min(discount, priceInCents))
+}
+
+type PromoRepository interface {
+	SavePromoCampaign(ctx context.Context, campaign *PromoCampaignModel) error
+	// GetPromoCampaign returns ErrPromoNotFound when no campaign has the code
+	GetPromoCampaign(ctx context.Context, code string) (*PromoCampaignModel, error)
+	// CountPromoRedemptions returns how many times the code was redeemed, in total and by the rider
+	CountPromoRedemptions(ctx context.Context, code, userID string) (total int, byUser int, err error)
+	// RedeemPromo checks the redemption limits of the campaign and records the redemption at once.
+	// It fails with ErrPromoExhausted when a limit is reached or the fare was already redeemed.
+	RedeemPromo(ctx context.Context, redemption *PromoRedemptionModel) error
+	// ReleasePromo removes the redemption of the fare, if any
+	ReleasePromo(ctx context.Context, fareID string) error
+}
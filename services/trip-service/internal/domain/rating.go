@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pb "ride-sharing/shared/proto/trip"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Who a rating is about
+const (
+	RateeRoleDriver = "driver"
+	RateeRoleRider  = "rider"
+)
+
+var (
+	// ErrInvalidRating is returned when the score, tags or comment of a rating are invalid
+	ErrInvalidRating = errors.New("invalid rating")
+	// ErrTripNotRateable is returned when the trip isn't completed or the user wasn't part of it
+	ErrTripNotRateable = errors.New("trip can't be rated")
+	// ErrAlreadyRated is returned when the user already rated the other party of the trip
+	ErrAlreadyRated = errors.New("trip already rated")
+)
+
+// RatingModel is the feedback a rider gives the driver of a completed trip, or the other way around
+type RatingModel struct {
+	TripID    string
+	RaterID   string
+	RateeID   string
+	RateeRole string
+	Score     int
+	Tags      []string
+	Comment   string
+	CreatedAt time.Time
+}
+
+func (r *RatingModel) ToProto() *pb.Rating {
+	return &pb.Rating{
+		TripID:    r.TripID,
+		RaterID:   r.RaterID,
+		RateeID:   r.RateeID,
+		RateeRole: r.RateeRole,
+		Score:     int32(r.Score),
+		Tags:      r.Tags,
+		Comment:   r.Comment,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+	}
+}
+
+// RatingSummary is the rolling average of the latest ratings of a user
+type RatingSummary struct {
+	UserID  string
+	Average float64
+	Count   int
+}
+
+func (s *RatingSummary) ToProto() *pb.RatingSummary {
+	return &pb.RatingSummary{
+		UserID:  s.UserID,
+		Average: s.Average,
+		Count:   int32(s.Count),
+	}
+}
+
+type RatingRepository interface {
+	// SaveRating returns ErrAlreadyRated when the rater already rated the ratee on the trip
+	SaveRating(ctx context.Context, rating *RatingModel) error
+	// ListLatestRatings returns up to limit ratings of the ratee, the latest first
+	ListLatestRatings(ctx context.Context, rateeID string, limit int) ([]*RatingModel, error)
+}
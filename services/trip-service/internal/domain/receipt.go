@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	pb "ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrReceiptNotAvailable is returned for the trips which aren't completed
+var ErrReceiptNotAvailable = errors.New("receipt not available")
+
+// ReceiptModel is the receipt of a completed trip for one of its riders, the amounts are in cents
+type ReceiptModel struct {
+	TripID      string
+	UserID      string
+	PackageSlug string
+	Currency    string
+	IssuedAt    time.Time
+	Fare        *FareBreakdown
+	// TipInCents is the tip the rider paid the driver
+	TipInCents float64
+	// TaxesInCents is the part of the fare going to taxes, already included in it
+	TaxesInCents float64
+	// TotalInCents is the fare, or the share of the rider when the fare was split, plus the tip
+	TotalInCents float64
+	Route        *ReceiptRoute
+	Driver       *pb.TripDriver
+	// Payment is nil until the payment service reported the payment of the rider
+	Payment *TripPayment
+}
+
+// FareBreakdown itemizes the fare the way it was priced
+type FareBreakdown struct {
+	BaseInCents     float64
+	DistanceInCents float64
+	TimeInCents     float64
+	StopsInCents    float64
+	WaitingInCents  float64
+	TollsInCents    float64
+	// SurgeInCents stays at 0, the fares aren't surge priced
+	SurgeInCents float64
+	// The discounts are positive amounts taken off the fare
+	PoolDiscountInCents  float64
+	PromoCode            string
+	PromoDiscountInCents float64
+	TotalPriceInCents    float64
+	// ShareInCents is what the rider owes of the total when the fare was split
+	ShareInCents float64
+}
+
+// PriceInCents is the fare the items add up to, before the promo discount
+func (b *FareBreakdown) PriceInCents() float64 {
+	return b.BaseInCents +
+		b.DistanceInCents +
+		b.TimeInCents +
+		b.StopsInCents +
+		b.WaitingInCents +
+		b.TollsInCents +
+		b.SurgeInCents -
+		b.PoolDiscountInCents
+}
+
+// ChargedInCents is what the rider owes for the fare, their share when it was split
+func (b *FareBreakdown) ChargedInCents() float64 {
+	if b.ShareInCents > 0 {
+		return b.ShareInCents
+	}
+
+	return b.TotalPriceInCents
+}
+
+func (b *FareBreakdown) ToProto() *pb.FareBreakdown {
+	return &pb.FareBreakdown{
+		BaseInCents:          b.BaseInCents,
+		DistanceInCents:      b.DistanceInCents,
+		TimeInCents:          b.TimeInCents,
+		StopsInCents:         b.StopsInCents,
+		WaitingInCents:       b.WaitingInCents,
+		TollsInCents:         b.TollsInCents,
+		SurgeInCents:         b.SurgeInCents,
+		PoolDiscountInCents:  b.PoolDiscountInCents,
+		PromoCode:            b.PromoCode,
+		PromoDiscountInCents: b.PromoDiscountInCents,
+		TotalPriceInCents:    b.TotalPriceInCents,
+		ShareInCents:         b.ShareInCents,
+	}
+}
+
+// ReceiptRoute sums up the route driven
+type ReceiptRoute struct {
+	Pickup      *types.Coordinate
+	Destination *types.Coordinate
+	Waypoints   []*types.Coordinate
+	// Distance and Duration are the ones the fare was priced with
+	Distance    float64
+	Duration    float64
+	CompletedAt time.Time
+}
+
+func (r *ReceiptRoute) ToProto() *pb.ReceiptRoute {
+	route := &pb.ReceiptRoute{
+		Waypoints:   CoordinatesToProtos(r.Waypoints),
+		Distance:    r.Distance,
+		Duration:    r.Duration,
+		CompletedAt: timestamppb.New(r.CompletedAt),
+	}
+	if r.Pickup != nil && r.Destination != nil {
+		route.Pickup = CoordinatesToProtos([]*types.Coordinate{r.Pickup})[0]
+		route.Destination = CoordinatesToProtos([]*types.Coordinate{r.Destination})[0]
+	}
+
+	return route
+}
+
+func (r *ReceiptModel) ToProto() *pb.Receipt {
+	receipt := &pb.Receipt{
+		TripID:       r.TripID,
+		UserID:       r.UserID,
+		PackageSlug:  r.PackageSlug,
+		Currency:     r.Currency,
+		IssuedAt:     timestamppb.New(r.IssuedAt),
+		Fare:         r.Fare.ToProto(),
+		TipInCents:   r.TipInCents,
+		TaxesInCents: r.TaxesInCents,
+		TotalInCents: r.TotalInCents,
+		Route:        r.Route.ToProto(),
+		Driver:       r.Driver,
+	}
+	if r.Payment != nil {
+		receipt.Payment = r.Payment.ToProto()
+	}
+
+	return receipt
+}
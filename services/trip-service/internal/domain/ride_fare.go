@@ -5,24 +5,47 @@ import (
 
 	tripTypes "ride-sharing/services/trip-service/pkg/types"
 	pb "ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// PoolPackageSlug is the shared ride package, where riders on overlapping routes share a vehicle
+const PoolPackageSlug = "pool"
+
 type RideFareModel struct {
 	ID                primitive.ObjectID
 	UserID            string
-	PackageSlug       string // ex. van, luxury, sedan
+	PackageSlug       string // ex. van, luxury, sedan, pool
 	TotalPriceInCents float64
 	Route             *tripTypes.OsrmAPIResponse
+	Pickup            *types.Coordinate
+	Destination       *types.Coordinate
+	// Intermediate stops the route goes through, in order
+	Waypoints []*types.Coordinate
+	// PromoCode discounted the fare, TotalPriceInCents being the discounted price
+	PromoCode            string
+	OriginalPriceInCents float64
+	DiscountInCents      float64
+}
+
+// ApplyPromo takes the discount of the campaign off the fare
+func (r *RideFareModel) ApplyPromo(campaign *PromoCampaignModel) {
+	r.PromoCode = campaign.Code
+	r.OriginalPriceInCents = r.TotalPriceInCents
+	r.DiscountInCents = campaign.Discount(r.TotalPriceInCents)
+	r.TotalPriceInCents -= r.DiscountInCents
 }
 
 func (r *RideFareModel) ToProto() *pb.RideFare {
 	return &pb.RideFare{
-		Id:                r.ID.Hex(),
-		UserID:            r.UserID,
-		PackageSlug:       r.PackageSlug,
-		TotalPriceInCents: r.TotalPriceInCents,
+		Id:                   r.ID.Hex(),
+		UserID:               r.UserID,
+		PackageSlug:          r.PackageSlug,
+		TotalPriceInCents:    r.TotalPriceInCents,
+		PromoCode:            r.PromoCode,
+		OriginalPriceInCents: r.OriginalPriceInCents,
+		DiscountInCents:      r.DiscountInCents,
 	}
 }
 
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+// ErrNoRoute is returned by a RouteProvider when the points cannot be connected
+var ErrNoRoute = errors.New("no route found between the given points")
+
+// ErrTooManyStops is returned when a trip has more intermediate stops than allowed
+var ErrTooManyStops = errors.New("too many stops")
+
+// ErrOutsideServiceArea is returned when a stop of the trip is where the service doesn't operate
+var ErrOutsideServiceArea = errors.New("outside of the service areas")
+
+// RouteProvider calculates a driving route through the given stops.
+// The first stop is the pickup and the last one is the destination, so at least two are required.
+type RouteProvider interface {
+	GetRoute(ctx context.Context, stops []*types.Coordinate) (*tripTypes.OsrmAPIResponse, error)
+}
+
+// ValidateStops checks that a route can be calculated through the stops
+func ValidateStops(stops []*types.Coordinate) error {
+	if len(stops) < 2 {
+		return fmt.Errorf("a route needs at least a pickup and a destination")
+	}
+
+	for _, stop := range stops {
+		if stop == nil {
+			return fmt.Errorf("route stops can't be empty")
+		}
+	}
+
+	return nil
+}
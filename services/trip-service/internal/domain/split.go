@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"math"
+
+	pb "ride-sharing/shared/proto/trip"
+)
+
+// Split participant statuses
+const (
+	SplitStatusInvited  = "invited"
+	SplitStatusAccepted = "accepted"
+	SplitStatusDeclined = "declined"
+	// SplitStatusExpired is an invitation still unanswered when the driver was assigned
+	SplitStatusExpired = "expired"
+)
+
+// Split participant payment statuses
+const (
+	// SplitPaymentPending is waiting for the participant to pay their share
+	SplitPaymentPending = "pending"
+	SplitPaymentPaid    = "paid"
+	// SplitPaymentFailed is a share the participant didn't pay, the owner is being charged for it
+	SplitPaymentFailed = "failed"
+	// SplitPaymentCoveredByOwner is a share the owner paid for the participant
+	SplitPaymentCoveredByOwner = "covered_by_owner"
+)
+
+var (
+	// ErrInvalidSplit is returned when the invitees are missing or invalid
+	ErrInvalidSplit = errors.New("invalid fare split")
+	// ErrTripNotSplittable is returned once a driver is assigned, and for the pool trips
+	ErrTripNotSplittable = errors.New("trip fare can't be split")
+	// ErrSplitInviteNotFound is returned when the user has no pending invitation to split the trip
+	ErrSplitInviteNotFound = errors.New("split invitation not found")
+)
+
+// SplitParticipant shares the fare of the trip with its owner
+type SplitParticipant struct {
+	UserID        string
+	Status        string
+	PaymentStatus string
+}
+
+func (p *SplitParticipant) ToProto() *pb.SplitParticipant {
+	return &pb.SplitParticipant{
+		UserID:        p.UserID,
+		Status:        p.Status,
+		PaymentStatus: p.PaymentStatus,
+	}
+}
+
+// FindSplitParticipant returns the participant invited to split the trip, if any
+func (t *TripModel) FindSplitParticipant(userID string) *SplitParticipant {
+	for _, participant := range t.SplitParticipants {
+		if participant.UserID == userID {
+			return participant
+		}
+	}
+
+	return nil
+}
+
+// IsSplitParticipant tells whether the user accepted to split the fare of the trip
+func (t *TripModel) IsSplitParticipant(userID string) bool {
+	participant := t.FindSplitParticipant(userID)
+	return participant != nil && participant.Status == SplitStatusAccepted
+}
+
+// FareShare is what the user owes of the fare: an equal share in whole cents when the owner split it with
+// the participants who accepted, the owner paying the cents left over, the whole fare otherwise
+func (t *TripModel) FareShare(userID string, fare float64) float64 {
+	var accepted int
+	for _, participant := range t.SplitParticipants {
+		if participant.Status == SplitStatusAccepted {
+			accepted++
+		}
+	}
+	if accepted == 0 {
+		return fare
+	}
+
+	total := math.Round(fare)
+	share := math.Floor(total / float64(accepted+1))
+	if userID == t.UserID {
+		return total - share*float64(accepted)
+	}
+
+	return share
+}
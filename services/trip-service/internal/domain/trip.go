@@ -2,12 +2,74 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	tripTypes "ride-sharing/services/trip-service/pkg/types"
 	pb "ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/types"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// TripStatusScheduled is a booked-ahead ride that isn't looking for a driver yet
+	TripStatusScheduled  = "scheduled"
+	TripStatusPending    = "pending"
+	TripStatusAssigned   = "assigned"
+	TripStatusInProgress = "in_progress"
+	TripStatusCancelled  = "cancelled"
+	TripStatusCompleted  = "completed"
+)
+
+const (
+	TripStopPickup  = "pickup"
+	TripStopDropoff = "dropoff"
+)
+
+// Trip tip statuses
+const (
+	// TripTipStatusPending is waiting for the payment service to charge the tip
+	TripTipStatusPending = "pending"
+	TripTipStatusPaid    = "paid"
+	TripTipStatusFailed  = "failed"
+)
+
+// Trip payment statuses
+const (
+	TripPaymentStatusPaid              = "paid"
+	TripPaymentStatusPartiallyRefunded = "partially_refunded"
+	TripPaymentStatusRefunded          = "refunded"
+)
+
+var (
+	// ErrTripNotFound is returned when there's no trip with the given ID
+	ErrTripNotFound = errors.New("trip not found")
+	// ErrInvalidTripStop is returned when a stop can't be marked as reached
+	ErrInvalidTripStop = errors.New("invalid trip stop")
+	// ErrInvalidSchedule is returned when a ride can't be scheduled for the requested time
+	ErrInvalidSchedule = errors.New("invalid schedule")
+	// ErrTripNotCancellable is returned when the trip is past the point it can be cancelled
+	ErrTripNotCancellable = errors.New("trip can't be cancelled")
+	// ErrTripNotOwned is returned when a user acts on someone else's trip
+	ErrTripNotOwned = errors.New("trip does not belong to the user")
+	// ErrNoPoolMatch is returned when no pool trip can take another rider
+	ErrNoPoolMatch = errors.New("no pool trip to join")
+	// ErrTripNotAssignable is returned when a driver is assigned to a trip that isn't waiting for one
+	ErrTripNotAssignable = errors.New("trip can't be assigned")
+	// ErrTripAlreadyAssigned is returned when the trip is already assigned to the same driver
+	ErrTripAlreadyAssigned = errors.New("trip already assigned")
+	// ErrTripNotCompletable is returned when the trip isn't driven or some of its stops weren't reached
+	ErrTripNotCompletable = errors.New("trip can't be completed")
+	// ErrTripAlreadyCompleted is returned when the driver completes the same trip again
+	ErrTripAlreadyCompleted = errors.New("trip already completed")
+	// ErrInvalidTip is returned when the tip amount or percentage is invalid
+	ErrInvalidTip = errors.New("invalid tip")
+	// ErrTripNotTippable is returned when the trip isn't completed, or was completed too long ago
+	ErrTripNotTippable = errors.New("trip can't be tipped")
+	// ErrAlreadyTipped is returned when the rider already tipped the driver of the trip
+	ErrAlreadyTipped = errors.New("trip already tipped")
 )
 
 type TripModel struct {
@@ -16,20 +78,307 @@ type TripModel struct {
 	Status   string
 	RideFare *RideFareModel
 	Driver   *pb.TripDriver
+	// DriverAssignedPublished is set once the assignment of the driver was announced, the redelivered
+	// assignments aren't announced again
+	DriverAssignedPublished bool
+	// How many of the ride fare waypoints, or of the stop sequence of pool trips, the driver has already reached
+	StopsReached int
+	// ScheduledAt is the pickup time of a booked-ahead ride, zero for immediate rides
+	ScheduledAt  time.Time
+	ReminderSent bool
+	// Riders of the trip, the owner first. Only pool trips have more than one
+	Riders []*TripRider
+	// StopSequence is the pickup/drop-off order of pool trips
+	StopSequence []*TripStop
+	// Payments are what the riders paid, reported by the payment service
+	Payments []*TripPayment
+	// FinalFare is the price of the route driven, set when a trip other than a pool trip is completed
+	FinalFare *FinalFareModel
+	// CompletedAt is when the driver completed the trip, zero until then
+	CompletedAt time.Time
+	// Tips the riders added once the trip completed, one per rider
+	Tips []*TripTip
+	// SplitParticipants are the users the owner invited to split the fare
+	SplitParticipants []*SplitParticipant
+}
+
+// TripTip is what a rider adds for the driver once the trip is completed
+type TripTip struct {
+	UserID        string
+	AmountInCents int64
+	Status        string
+	CreatedAt     time.Time
+}
+
+func (t *TripTip) ToProto() *pb.TripTip {
+	return &pb.TripTip{
+		UserID:        t.UserID,
+		AmountInCents: t.AmountInCents,
+		Status:        t.Status,
+		CreatedAt:     timestamppb.New(t.CreatedAt),
+	}
+}
+
+// CompletedRoute is what the driver reports of the route once the trip is completed.
+// The values left to 0 are taken from the quoted route.
+type CompletedRoute struct {
+	Distance     float64
+	Duration     float64
+	WaitMinutes  float64
+	TollsInCents float64
+}
+
+// FinalFareModel is the price of the route driven
+type FinalFareModel struct {
+	TotalPriceInCents float64
+	Distance          float64
+	Duration          float64
+	WaitMinutes       float64
+	TollsInCents      float64
+	// DiscountInCents is the promo discount granted at the booking, already taken off the total
+	DiscountInCents float64
+}
+
+func (f *FinalFareModel) ToProto() *pb.FinalFare {
+	return &pb.FinalFare{
+		TotalPriceInCents: f.TotalPriceInCents,
+		Distance:          f.Distance,
+		Duration:          f.Duration,
+		WaitMinutes:       f.WaitMinutes,
+		TollsInCents:      f.TollsInCents,
+		DiscountInCents:   f.DiscountInCents,
+	}
+}
+
+type TripRider struct {
+	UserID   string
+	RideFare *RideFareModel
+}
+
+// TripPayment is what a rider paid for the trip
+type TripPayment struct {
+	UserID string
+	// PayerID is who was charged, the owner when they covered the share of a split participant
+	PayerID string
+	// Method is how the payer paid, ex. card, when the provider reported it
+	Method          string
+	AmountInCents   int64
+	RefundedInCents int64
+	Currency        string
+	// BalanceInCents is the part of the final fare over the hold, paid on its own session
+	BalanceInCents int64
+}
+
+// PaidInCents is what was paid for the fare, the balance included
+func (p *TripPayment) PaidInCents() int64 {
+	return p.AmountInCents + p.BalanceInCents
+}
+
+func (p *TripPayment) NetInCents() int64 {
+	return p.PaidInCents() - p.RefundedInCents
+}
+
+func (p *TripPayment) Status() string {
+	switch {
+	case p.RefundedInCents <= 0:
+		return TripPaymentStatusPaid
+	case p.RefundedInCents < p.PaidInCents():
+		return TripPaymentStatusPartiallyRefunded
+	}
+
+	return TripPaymentStatusRefunded
+}
+
+func (p *TripPayment) ToProto() *pb.TripPayment {
+	return &pb.TripPayment{
+		UserID:          p.UserID,
+		AmountInCents:   p.PaidInCents(),
+		BalanceInCents:  p.BalanceInCents,
+		RefundedInCents: p.RefundedInCents,
+		NetInCents:      p.NetInCents(),
+		Currency:        p.Currency,
+		Status:          p.Status(),
+		PayerID:         p.PayerID,
+		Method:          p.Method,
+	}
+}
+
+type TripStop struct {
+	UserID    string
+	Type      string // pickup or dropoff
+	Location  *types.Coordinate
+	Completed bool
 }
 
+func (s *TripStop) ToProto() *pb.TripStop {
+	return &pb.TripStop{
+		UserID: s.UserID,
+		Type:   s.Type,
+		Location: &pb.Coordinate{
+			Latitude:  s.Location.Latitude,
+			Longitude: s.Location.Longitude,
+		},
+		Completed: s.Completed,
+	}
+}
+
+func (t *TripModel) IsPool() bool {
+	return t.RideFare != nil && t.RideFare.PackageSlug == PoolPackageSlug
+}
+
+func (t *TripModel) IsScheduled() bool {
+	return !t.ScheduledAt.IsZero()
+}
+
+func (t *TripModel) ToProto() *pb.Trip {
+	trip := &pb.Trip{
+		Id:           t.ID.Hex(),
+		UserID:       t.UserID,
+		Status:       t.Status,
+		Driver:       t.Driver,
+		StopsReached: int32(t.StopsReached),
+	}
+
+	if t.IsScheduled() {
+		trip.ScheduledAt = timestamppb.New(t.ScheduledAt)
+	}
+
+	for _, rider := range t.Riders {
+		trip.Riders = append(trip.Riders, &pb.TripRider{
+			UserID: rider.UserID,
+			Fare:   rider.RideFare.ToProto(),
+		})
+	}
+
+	for _, stop := range t.StopSequence {
+		trip.StopSequence = append(trip.StopSequence, stop.ToProto())
+	}
+
+	for _, payment := range t.Payments {
+		trip.Payments = append(trip.Payments, payment.ToProto())
+	}
+
+	if t.FinalFare != nil {
+		trip.FinalFare = t.FinalFare.ToProto()
+	}
+
+	for _, tip := range t.Tips {
+		trip.Tips = append(trip.Tips, tip.ToProto())
+	}
+
+	for _, participant := range t.SplitParticipants {
+		trip.SplitParticipants = append(trip.SplitParticipants, participant.ToProto())
+	}
+
+	if t.RideFare != nil {
+		trip.SelectedFare = t.RideFare.ToProto()
+		trip.Waypoints = CoordinatesToProtos(t.RideFare.Waypoints)
+		if t.RideFare.Pickup != nil && t.RideFare.Destination != nil {
+			trip.Pickup = CoordinatesToProtos([]*types.Coordinate{t.RideFare.Pickup})[0]
+			trip.Destination = CoordinatesToProtos([]*types.Coordinate{t.RideFare.Destination})[0]
+		}
+		if t.RideFare.Route != nil {
+			trip.Route = t.RideFare.Route.ToProto()
+		}
+	}
+
+	return trip
+}
+
+func CoordinatesToProtos(coordinates []*types.Coordinate) []*pb.Coordinate {
+	protos := make([]*pb.Coordinate, len(coordinates))
+	for idx, c := range coordinates {
+		protos[idx] = &pb.Coordinate{Latitude: c.Latitude, Longitude: c.Longitude}
+	}
+
+	return protos
+}
+
+func CoordinatesFromProtos(protos []*pb.Coordinate) []*types.Coordinate {
+	coordinates := make([]*types.Coordinate, len(protos))
+	for idx, c := range protos {
+		coordinates[idx] = &types.Coordinate{Latitude: c.GetLatitude(), Longitude: c.GetLongitude()}
+	}
+
+	return coordinates
+}
+
+// RiderFare is what the rider was charged for the trip, in cents: the final fare when there's one,
+// else the fare of the rider
+func (t *TripModel) RiderFare(userID string) float64 {
+	if t.FinalFare != nil {
+		return t.FinalFare.TotalPriceInCents
+	}
+
+	for _, rider := range t.Riders {
+		if rider.UserID == userID && rider.RideFare != nil {
+			return rider.RideFare.TotalPriceInCents
+		}
+	}
+
+	if t.RideFare != nil {
+		return t.RideFare.TotalPriceInCents
+	}
+
+	return 0
+}
+
+// FindTip returns the tip of the rider, if any
+func (t *TripModel) FindTip(userID string) *TripTip {
+	for _, tip := range t.Tips {
+		if tip.UserID == userID {
+			return tip
+		}
+	}
+
+	return nil
+}
+
+// HasRider tells whether the user rides the trip
+func (t *TripModel) HasRider(userID string) bool {
+	if t.UserID == userID {
+		return true
+	}
+
+	for _, rider := range t.Riders {
+		if rider.UserID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TripRepository stores the trips and the fares, the ones it returns are copies whose changes are only kept once saved
 type TripRepository interface {
 	CreateTrip(ctx context.Context, trip *TripModel) (*TripModel, error)
+	GetTripByID(ctx context.Context, id string) (*TripModel, error)
+	UpdateTrip(ctx context.Context, trip *TripModel) error
+	ListTripsByStatus(ctx context.Context, status string) ([]*TripModel, error)
 	SaveRideFare(ctx context.Context, fare *RideFareModel) error
 	GetRideFareByID(ctx context.Context, id string) (*RideFareModel, error)
+	// CountRiderTrips counts the trips the user booked or joined, the cancelled ones aside
+	CountRiderTrips(ctx context.Context, userID string) (int, error)
+	RatingRepository
+	PromoRepository
 }
 
 type TripService interface {
-	CreateTrip(ctx context.Context, fare *RideFareModel) (*TripModel, error)
+	// CreateTrip books the ride right away, or for scheduledAt when it's not zero
+	CreateTrip(ctx context.Context, fare *RideFareModel, scheduledAt time.Time) (*TripModel, error)
+	// CancelTrip cancels the trip while it's waiting for a driver
+	CancelTrip(ctx context.Context, tripID, userID string) (*TripModel, error)
+	// ActivateDueScheduledTrips moves the scheduled trips that entered the lead window to pending
+	ActivateDueScheduledTrips(ctx context.Context, now time.Time) ([]*TripModel, error)
+	// DeactivateScheduledTrip moves the activated trip back to scheduled, to activate it again on the next check
+	DeactivateScheduledTrip(ctx context.Context, tripID string) error
+	// RemindScheduledTrips returns the scheduled trips whose riders should be reminded now
+	RemindScheduledTrips(ctx context.Context, now time.Time) ([]*TripModel, error)
 	GetRoute(
 		ctx context.Context,
 		pickup *types.Coordinate,
 		destination *types.Coordinate,
+		waypoints ...*types.Coordinate,
 	) (*tripTypes.OsrmAPIResponse, error)
 	EstimaPkgsPriceWithRoute(route *tripTypes.OsrmAPIResponse) []*RideFareModel
 	GenerateTripFares(
@@ -37,7 +386,55 @@ type TripService interface {
 		fares []*RideFareModel,
 		userID string,
 		route *tripTypes.OsrmAPIResponse,
+		stops []*types.Coordinate,
 	) ([]*RideFareModel, error)
+	// ApplyPromoCode discounts the estimated fares of the packages the promo code applies to, it checks
+	// the campaign without redeeming it
+	ApplyPromoCode(ctx context.Context, code, userID string, fares []*RideFareModel) error
 	GetFare(ctx context.Context, fareID string) (*RideFareModel, error)
 	ValidateFare(fare *RideFareModel, userID string) (*RideFareModel, error)
+	// RedeemPromo redeems the promo code of the fare being booked, if any. It fails with ErrPromoExpired,
+	// ErrPromoNotEligible or ErrPromoExhausted when the promo code can't be used anymore.
+	RedeemPromo(ctx context.Context, fare *RideFareModel) error
+	// ReleasePromo gives the promo code of the fare back to the rider, when the booking failed
+	ReleasePromo(ctx context.Context, fare *RideFareModel) error
+	// AssignDriver records the driver who accepted the pending trip
+	AssignDriver(ctx context.Context, tripID string, driver *pb.TripDriver) (*TripModel, error)
+	// MarkDriverAssignedPublished records that the assignment of the driver was announced
+	MarkDriverAssignedPublished(ctx context.Context, tripID string) error
+	// JoinPoolTrip adds the rider of a pool fare to a matching pool trip, or returns ErrNoPoolMatch
+	JoinPoolTrip(ctx context.Context, fare *RideFareModel) (*TripModel, error)
+	// ReachTripStop records that the driver has reached the waypoint with the given index
+	ReachTripStop(ctx context.Context, tripID, driverID string, stopIndex int) (*TripModel, error)
+	// CompleteTrip ends the trip once the driver dropped the riders off, and prices the route driven
+	CompleteTrip(ctx context.Context, tripID, driverID string, route *CompletedRoute) (*TripModel, error)
+	// RateTrip records the rating of the other party of a completed trip.
+	// Riders rate the driver, drivers rate the rider given by rateeID (the trip owner when empty).
+	RateTrip(
+		ctx context.Context,
+		tripID, userID, rateeID string,
+		score int,
+		tags []string,
+		comment string,
+	) (*RatingModel, *RatingSummary, error)
+	GetRatingSummary(ctx context.Context, userID string) (*RatingSummary, error)
+	// GetTrip returns the trip to one of its riders
+	GetTrip(ctx context.Context, tripID, userID string) (*TripModel, error)
+	// GetTripReceipt returns the receipt of the completed trip to one of its riders, or split participants
+	GetTripReceipt(ctx context.Context, tripID, userID string) (*ReceiptModel, error)
+	// RecordPayment merges what the rider paid for the trip, the payment events may come more than once
+	// and in any order
+	RecordPayment(ctx context.Context, tripID string, payment *TripPayment) (*TripModel, error)
+	// TipTrip adds the tip of the rider to the completed trip, either a fixed amount or a percentage
+	// of the fare of the rider
+	TipTrip(ctx context.Context, tripID, userID string, amountInCents int64, percent float64) (*TripModel, *TripTip, error)
+	// SettleTip records whether the tip of the rider was charged
+	SettleTip(ctx context.Context, tripID, userID string, paid bool) (*TripModel, error)
+	// InviteToSplit invites the users to split the fare of the trip with its owner, until a driver is
+	// assigned. It returns the users to send the invitation to, again for those who didn't answer yet.
+	InviteToSplit(ctx context.Context, tripID, ownerID string, inviteeIDs []string) (*TripModel, []string, error)
+	// RespondToSplit records whether the invited user accepted to split the fare
+	RespondToSplit(ctx context.Context, tripID, userID string, accept bool) (*TripModel, error)
+	// RecordSplitPaymentFailure records that the participant didn't pay their share, the owner covers it
+	RecordSplitPaymentFailure(ctx context.Context, tripID, userID string) (*TripModel, error)
 }
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DriverConsumer assigns the trips to the drivers who accepted them in the driver service, and tells
+// whether the assignment was recorded
+type DriverConsumer struct {
+	rabbitMQ  *messaging.RabbitMQ
+	service   domain.TripService
+	publisher Publisher
+}
+
+func NewDriverConsumer(rabbitMQ *messaging.RabbitMQ, service domain.TripService, publisher Publisher) *DriverConsumer {
+	return &DriverConsumer{
+		rabbitMQ:  rabbitMQ,
+		service:   service,
+		publisher: publisher,
+	}
+}
+
+func (c *DriverConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.AssignTripDriverQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.TripEventData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the trip event: %v", err)
+			}
+
+			if msg.RoutingKey != contracts.DriverCmdTripAssign {
+				log.Printf("Unknown driver command: %s", msg.RoutingKey)
+				return nil
+			}
+
+			trip, err := c.service.AssignDriver(ctx, payload.Trip.GetId(), payload.Trip.GetDriver())
+			if errors.Is(err, domain.ErrTripAlreadyAssigned) {
+				// Redelivered command, the assignment is only published if the previous publish failed
+				trip, err = c.service.GetTrip(ctx, payload.Trip.GetId(), payload.Trip.GetUserID())
+				if err == nil && trip.DriverAssignedPublished {
+					return nil
+				}
+			}
+
+			switch {
+			case errors.Is(err, domain.ErrTripNotFound) || errors.Is(err, domain.ErrTripNotAssignable):
+				// The driver service makes the driver available again
+				log.Printf("Rejecting driver %s for trip %s: %v", payload.Trip.GetDriver().GetId(), payload.Trip.GetId(), err)
+				return c.publisher.PublishDriverRejected(ctx, payload.Trip)
+			case err != nil:
+				return err
+			}
+
+			if err := c.publisher.PublishTripEvent(ctx, contracts.TripEventDriverAssigned, trip); err != nil {
+				return err
+			}
+
+			return c.service.MarkDriverAssignedPublished(ctx, trip.ID.Hex())
+		},
+	)
+}
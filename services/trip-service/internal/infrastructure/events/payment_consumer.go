@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// PaymentConsumer records on the trips what their riders paid, got refunded, and whether their tips were charged
+type PaymentConsumer struct {
+	rabbitMQ *messaging.RabbitMQ
+	service  domain.TripService
+}
+
+func NewPaymentConsumer(rabbitMQ *messaging.RabbitMQ, service domain.TripService) *PaymentConsumer {
+	return &PaymentConsumer{
+		rabbitMQ: rabbitMQ,
+		service:  service,
+	}
+}
+
+func (c *PaymentConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.PaymentTripUpdateQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payment *domain.TripPayment
+			var tripID string
+			switch msg.RoutingKey {
+			case contracts.PaymentEventSuccess, contracts.PaymentEventFailed, contracts.PaymentEventCancelled:
+				var payload messaging.PaymentEventData
+				if err := json.Unmarshal(message.Data, &payload); err != nil {
+					return fmt.Errorf("failed to unmarshal the payment event: %v", err)
+				}
+				if payload.Kind == messaging.PaymentKindTip {
+					return c.settleTip(ctx, &payload, msg.RoutingKey == contracts.PaymentEventSuccess)
+				}
+				if payload.Kind == messaging.PaymentKindBalance && msg.RoutingKey != contracts.PaymentEventSuccess {
+					// The rest of the final fare stays due, the hold was captured and recorded
+					log.Printf("Balance of %s on trip %s wasn't paid", payload.UserID, payload.TripID)
+					return nil
+				}
+				// Only the captured fares are recorded, the failed shares of a split are covered by the owner
+				if msg.RoutingKey != contracts.PaymentEventSuccess {
+					return c.recordSplitFailure(ctx, &payload)
+				}
+				tripID = payload.TripID
+				payment = &domain.TripPayment{
+					UserID:        payload.UserID,
+					PayerID:       payload.PayerID,
+					Method:        payload.Method,
+					AmountInCents: payload.AmountInCents,
+					Currency:      payload.Currency,
+				}
+				if payload.Kind == messaging.PaymentKindBalance {
+					payment.AmountInCents, payment.BalanceInCents = 0, payload.AmountInCents
+				}
+			case contracts.PaymentEventRefunded:
+				var payload messaging.PaymentRefundedData
+				if err := json.Unmarshal(message.Data, &payload); err != nil {
+					return fmt.Errorf("failed to unmarshal the refund event: %v", err)
+				}
+				tripID = payload.TripID
+				payment = &domain.TripPayment{
+					UserID:          payload.UserID,
+					AmountInCents:   payload.PaidInCents,
+					BalanceInCents:  payload.BalanceInCents,
+					RefundedInCents: payload.RefundedInCents,
+					Currency:        payload.Currency,
+				}
+			default:
+				log.Printf("Unknown payment event: %s", msg.RoutingKey)
+				return nil
+			}
+
+			_, err := c.service.RecordPayment(ctx, tripID, payment)
+			if errors.Is(err, domain.ErrTripNotFound) {
+				// Checkout sessions created outside of the trips, ex. from the provider dashboard
+				log.Printf("No trip %s to record the payment of %s on", tripID, payment.UserID)
+				return nil
+			}
+			return err
+		},
+	)
+}
+
+func (c *PaymentConsumer) settleTip(ctx context.Context, payload *messaging.PaymentEventData, paid bool) error {
+	_, err := c.service.SettleTip(ctx, payload.TripID, payload.UserID, paid)
+	if errors.Is(err, domain.ErrTripNotFound) || errors.Is(err, domain.ErrTripNotTippable) {
+		log.Printf("No tip of %s on trip %s to settle: %v", payload.UserID, payload.TripID, err)
+		return nil
+	}
+	return err
+}
+
+func (c *PaymentConsumer) recordSplitFailure(ctx context.Context, payload *messaging.PaymentEventData) error {
+	_, err := c.service.RecordSplitPaymentFailure(ctx, payload.TripID, payload.UserID)
+	if errors.Is(err, domain.ErrTripNotFound) {
+		log.Printf("No trip %s to record the failed payment of %s on", payload.TripID, payload.UserID)
+		return nil
+	}
+	return err
+}
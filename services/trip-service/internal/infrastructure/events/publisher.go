@@ -1,7 +1,21 @@
 package events
 
-import "context"
+import (
+	"context"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	pb "ride-sharing/shared/proto/trip"
+)
 
 type Publisher interface {
-	Publish(ctx context.Context, event string) error
+	// PublishTripEvent publishes the trip under the given trip.event.* routing key
+	PublishTripEvent(ctx context.Context, routingKey string, trip *domain.TripModel) error
+	// PublishRatingEvent publishes the new rating along with the updated summary of the ratee
+	PublishRatingEvent(ctx context.Context, rating *domain.RatingModel, summary *domain.RatingSummary) error
+	// PublishSplitInvite publishes trip.event.split_invited with the trip to the invitee
+	PublishSplitInvite(ctx context.Context, trip *domain.TripModel, inviteeID string) error
+	// PublishTipEvent publishes the tip the rider added to the trip
+	PublishTipEvent(ctx context.Context, trip *domain.TripModel, tip *domain.TripTip) error
+	// PublishDriverRejected publishes trip.event.driver_rejected with the trip as the driver accepted it
+	PublishDriverRejected(ctx context.Context, trip *pb.Trip) error
 }
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/contracts"
+	"ride-sharing/shared/messaging"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// SplitConsumer records the answers of the users invited to split a fare, and tells the owner
+type SplitConsumer struct {
+	rabbitMQ  *messaging.RabbitMQ
+	service   domain.TripService
+	publisher Publisher
+}
+
+func NewSplitConsumer(rabbitMQ *messaging.RabbitMQ, service domain.TripService, publisher Publisher) *SplitConsumer {
+	return &SplitConsumer{
+		rabbitMQ:  rabbitMQ,
+		service:   service,
+		publisher: publisher,
+	}
+}
+
+func (c *SplitConsumer) Listen() error {
+	return c.rabbitMQ.ConsumeMessages(
+		messaging.SplitResponseQueue,
+		func(ctx context.Context, msg amqp.Delivery) error {
+			var message contracts.AmqpMessage
+			if err := json.Unmarshal(msg.Body, &message); err != nil {
+				return fmt.Errorf("failed to unmarshal the message: %v", err)
+			}
+
+			var payload messaging.RiderSplitResponseData
+			if err := json.Unmarshal(message.Data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal the split response: %v", err)
+			}
+
+			var accept bool
+			switch msg.RoutingKey {
+			case contracts.RiderCmdSplitAccept:
+				accept = true
+			case contracts.RiderCmdSplitDecline:
+			default:
+				log.Printf("Unknown split response: %s", msg.RoutingKey)
+				return nil
+			}
+
+			trip, err := c.service.RespondToSplit(ctx, payload.TripID, message.OwnerID, accept)
+			if errors.Is(err, domain.ErrTripNotFound) ||
+				errors.Is(err, domain.ErrSplitInviteNotFound) ||
+				errors.Is(err, domain.ErrTripNotSplittable) {
+				// Answered too late, or without an invitation, nothing changes
+				log.Printf("Ignoring the split response of %s on trip %s: %v", message.OwnerID, payload.TripID, err)
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			return c.publisher.PublishTripEvent(ctx, contracts.TripEventSplitUpdated, trip)
+		},
+	)
+}
@@ -3,9 +3,14 @@ package events
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/messaging"
+	pb "ride-sharing/shared/proto/trip"
 )
 
 type TripEventsPublisher struct {
@@ -18,9 +23,101 @@ func NewPublisher(rabbitMQ *messaging.RabbitMQ) Publisher {
 	}
 }
 
-func (t *TripEventsPublisher) Publish(ctx context.Context, event string) error {
+func (t *TripEventsPublisher) PublishTripEvent(
+	ctx context.Context,
+	routingKey string,
+	trip *domain.TripModel,
+) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	return t.rabbitMQ.Publish(ctx, "hello", event)
+	data, err := json.Marshal(messaging.TripEventData{Trip: trip.ToProto()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the trip event: %w", err)
+	}
+
+	return t.rabbitMQ.Publish(ctx, routingKey, contracts.AmqpMessage{
+		OwnerID: trip.UserID,
+		Data:    data,
+	})
+}
+
+func (t *TripEventsPublisher) PublishSplitInvite(
+	ctx context.Context,
+	trip *domain.TripModel,
+	inviteeID string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(messaging.TripEventData{Trip: trip.ToProto()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the split invitation: %w", err)
+	}
+
+	return t.rabbitMQ.Publish(ctx, contracts.TripEventSplitInvited, contracts.AmqpMessage{
+		OwnerID: inviteeID,
+		Data:    data,
+	})
+}
+
+func (t *TripEventsPublisher) PublishRatingEvent(
+	ctx context.Context,
+	rating *domain.RatingModel,
+	summary *domain.RatingSummary,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(messaging.TripRatedData{
+		Rating:  rating.ToProto(),
+		Summary: summary.ToProto(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the rating event: %w", err)
+	}
+
+	return t.rabbitMQ.Publish(ctx, contracts.TripEventRated, contracts.AmqpMessage{
+		OwnerID: rating.RateeID,
+		Data:    data,
+	})
+}
+
+func (t *TripEventsPublisher) PublishTipEvent(
+	ctx context.Context,
+	trip *domain.TripModel,
+	tip *domain.TripTip,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(messaging.TripTipData{
+		TripID:      trip.ID.Hex(),
+		DriverID:    trip.Driver.GetId(),
+		PackageSlug: trip.RideFare.PackageSlug,
+		Tip:         tip.ToProto(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the tip event: %w", err)
+	}
+
+	return t.rabbitMQ.Publish(ctx, contracts.TripEventTipAdded, contracts.AmqpMessage{
+		OwnerID: tip.UserID,
+		Data:    data,
+	})
+}
+
+func (t *TripEventsPublisher) PublishDriverRejected(ctx context.Context, trip *pb.Trip) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := json.Marshal(messaging.TripEventData{Trip: trip})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the rejected driver event: %w", err)
+	}
+
+	return t.rabbitMQ.Publish(ctx, contracts.TripEventDriverRejected, contracts.AmqpMessage{
+		OwnerID: trip.GetDriver().GetId(),
+		Data:    data,
+	})
 }
@@ -3,6 +3,9 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"log"
+	"time"
 
 	"ride-sharing/services/trip-service/internal/domain"
 	"ride-sharing/services/trip-service/internal/infrastructure/events"
@@ -44,6 +47,10 @@ func (h *handler) PreviewTrip(
 	ctx context.Context,
 	req *pb.PreviewTripReq,
 ) (*pb.PreviewTripRes, error) {
+	if req.GetStartLocation() == nil || req.GetEndLocation() == nil {
+		return nil, status.Error(codes.InvalidArgument, "start and end locations are required")
+	}
+
 	pickup := &types.Coordinate{
 		Latitude:  req.StartLocation.Latitude,
 		Longitude: req.StartLocation.Longitude,
@@ -54,14 +61,34 @@ func (h *handler) PreviewTrip(
 		Longitude: req.EndLocation.Longitude,
 	}
 
-	route, err := h.service.GetRoute(ctx, pickup, destination)
+	for _, waypoint := range req.GetWaypoints() {
+		if waypoint == nil {
+			return nil, status.Error(codes.InvalidArgument, "waypoints can't be empty")
+		}
+	}
+	waypoints := domain.CoordinatesFromProtos(req.GetWaypoints())
+
+	route, err := h.service.GetRoute(ctx, pickup, destination, waypoints...)
 	if err != nil {
+		if errors.Is(err, domain.ErrNoRoute) {
+			return nil, status.Errorf(codes.NotFound, "Failed to get route: %v", err)
+		}
+		if errors.Is(err, domain.ErrTooManyStops) {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to get route: %v", err)
+		}
+		if errors.Is(err, domain.ErrOutsideServiceArea) {
+			return nil, status.Errorf(codes.FailedPrecondition, "Failed to get route: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "Failed to get route: %v", err)
 	}
 
-	// Estimate the ride fares prices based on the route (ex. distance)
+	// Estimate the ride fares prices based on the route (ex. distance, stops)
 	estimatedFares := h.service.EstimaPkgsPriceWithRoute(route)
-	fares, err := h.service.GenerateTripFares(ctx, estimatedFares, req.UserID, route)
+	if err := h.service.ApplyPromoCode(ctx, req.GetPromoCode(), req.GetUserID(), estimatedFares); err != nil {
+		return nil, promoStatus("applyPromoErr", err)
+	}
+	stops := append(append([]*types.Coordinate{pickup}, waypoints...), destination)
+	fares, err := h.service.GenerateTripFares(ctx, estimatedFares, req.UserID, route, stops)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to generate the ride fares: %v", err)
 	}
@@ -89,14 +116,329 @@ func (h *handler) CreateTrip(
 		return nil, status.Errorf(codes.Internal, "validateFareErr: %v", err.Error())
 	}
 
-	trip, err := h.service.CreateTrip(ctx, rightFare)
+	// The promo code is redeemed first, so that concurrent bookings can't go over its limits
+	if err := h.service.RedeemPromo(ctx, rightFare); err != nil {
+		return nil, promoStatus("redeemPromoErr", err)
+	}
+
+	var scheduledAt time.Time
+	if req.GetScheduledAt() != nil {
+		scheduledAt = req.GetScheduledAt().AsTime()
+	}
+
+	// Pool riders share a trip that is already on its way when one is close enough
+	if rightFare.PackageSlug == domain.PoolPackageSlug && scheduledAt.IsZero() {
+		pooled, err := h.service.JoinPoolTrip(ctx, rightFare)
+		switch {
+		case err == nil:
+			if err := h.publisher.PublishTripEvent(ctx, contracts.TripEventPoolRiderAdded, pooled); err != nil {
+				h.releasePromo(ctx, rightFare)
+				return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
+			}
+
+			return &pb.CreateTripRes{TripID: pooled.ID.Hex(), Trip: pooled.ToProto()}, nil
+		case !errors.Is(err, domain.ErrNoPoolMatch):
+			log.Printf("Failed to match pool fare %s, creating a new trip: %v", fareID, err)
+		}
+	}
+
+	trip, err := h.service.CreateTrip(ctx, rightFare, scheduledAt)
 	if err != nil {
+		h.releasePromo(ctx, rightFare)
+		if errors.Is(err, domain.ErrInvalidSchedule) {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to create trip: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create trip: %v", err)
 	}
 
-	if err := h.publisher.Publish(ctx, contracts.TripEventCreated); err != nil {
+	// Scheduled trips look for a driver once the scheduler moves them into the lead window
+	event := contracts.TripEventCreated
+	if trip.IsScheduled() {
+		event = contracts.TripEventScheduled
+	}
+
+	if err := h.publisher.PublishTripEvent(ctx, event, trip); err != nil {
+		// Nobody heard of the trip, cancelling it gives the promo code back for the rider to book again
+		if _, err := h.service.CancelTrip(ctx, trip.ID.Hex(), userID); err != nil {
+			log.Printf("Failed to cancel the unpublished trip %s: %v", trip.ID.Hex(), err)
+			h.releasePromo(ctx, rightFare)
+		}
+		return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
+	}
+
+	return &pb.CreateTripRes{TripID: trip.ID.Hex(), Trip: trip.ToProto()}, nil
+}
+
+// releasePromo gives the promo code of the fare back when the booking failed
+func (h *handler) releasePromo(ctx context.Context, fare *domain.RideFareModel) {
+	if err := h.service.ReleasePromo(ctx, fare); err != nil {
+		log.Printf("Failed to release the promo code of fare %s: %v", fare.ID.Hex(), err)
+	}
+}
+
+// promoStatus maps the promo code errors to the gRPC codes
+func promoStatus(prefix string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrPromoNotFound):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", prefix, err)
+	case errors.Is(err, domain.ErrPromoExpired),
+		errors.Is(err, domain.ErrPromoNotEligible),
+		errors.Is(err, domain.ErrPromoExhausted):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", prefix, err)
+	}
+
+	return status.Errorf(codes.Internal, "%s: %v", prefix, err)
+}
+
+func (h *handler) CancelTrip(
+	ctx context.Context,
+	req *pb.CancelTripReq,
+) (*pb.CancelTripRes, error) {
+	trip, err := h.service.CancelTrip(ctx, req.GetTripID(), req.GetUserID())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "cancelTripErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotOwned):
+			return nil, status.Errorf(codes.PermissionDenied, "cancelTripErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotCancellable):
+			return nil, status.Errorf(codes.FailedPrecondition, "cancelTripErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "cancelTripErr: %v", err)
+	}
+
+	if err := h.publisher.PublishTripEvent(ctx, contracts.TripEventCancelled, trip); err != nil {
+		return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
+	}
+
+	return &pb.CancelTripRes{
+		Trip: trip.ToProto(),
+	}, nil
+}
+
+func (h *handler) ReachTripStop(
+	ctx context.Context,
+	req *pb.ReachTripStopReq,
+) (*pb.Trip, error) {
+	trip, err := h.service.ReachTripStop(ctx, req.GetTripID(), req.GetDriverID(), int(req.GetStopIndex()))
+	if err != nil {
+		if errors.Is(err, domain.ErrTripNotFound) {
+			return nil, status.Errorf(codes.NotFound, "reachTripStopErr: %v", err)
+		}
+		if errors.Is(err, domain.ErrInvalidTripStop) {
+			return nil, status.Errorf(codes.FailedPrecondition, "reachTripStopErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "reachTripStopErr: %v", err)
+	}
+
+	return trip.ToProto(), nil
+}
+
+func (h *handler) CompleteTrip(
+	ctx context.Context,
+	req *pb.CompleteTripReq,
+) (*pb.Trip, error) {
+	trip, err := h.service.CompleteTrip(ctx, req.GetTripID(), req.GetDriverID(), &domain.CompletedRoute{
+		Distance:     req.GetDistance(),
+		Duration:     req.GetDuration(),
+		WaitMinutes:  req.GetWaitMinutes(),
+		TollsInCents: req.GetTollsInCents(),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrTripNotFound) {
+			return nil, status.Errorf(codes.NotFound, "completeTripErr: %v", err)
+		}
+		if errors.Is(err, domain.ErrTripNotCompletable) {
+			return nil, status.Errorf(codes.FailedPrecondition, "completeTripErr: %v", err)
+		}
+		// The completion was already published
+		if errors.Is(err, domain.ErrTripAlreadyCompleted) {
+			return nil, status.Errorf(codes.AlreadyExists, "completeTripErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "completeTripErr: %v", err)
+	}
+
+	if err := h.publisher.PublishTripEvent(ctx, contracts.TripEventCompleted, trip); err != nil {
+		return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
+	}
+
+	return trip.ToProto(), nil
+}
+
+func (h *handler) RateTrip(
+	ctx context.Context,
+	req *pb.RateTripReq,
+) (*pb.RateTripRes, error) {
+	rating, summary, err := h.service.RateTrip(
+		ctx,
+		req.GetTripID(),
+		req.GetUserID(),
+		req.GetRateeID(),
+		int(req.GetScore()),
+		req.GetTags(),
+		req.GetComment(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "rateTripErr: %v", err)
+		case errors.Is(err, domain.ErrInvalidRating):
+			return nil, status.Errorf(codes.InvalidArgument, "rateTripErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotRateable):
+			return nil, status.Errorf(codes.FailedPrecondition, "rateTripErr: %v", err)
+		case errors.Is(err, domain.ErrAlreadyRated):
+			return nil, status.Errorf(codes.AlreadyExists, "rateTripErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "rateTripErr: %v", err)
+	}
+
+	if err := h.publisher.PublishRatingEvent(ctx, rating, summary); err != nil {
+		return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
+	}
+
+	return &pb.RateTripRes{
+		Rating:  rating.ToProto(),
+		Summary: summary.ToProto(),
+	}, nil
+}
+
+func (h *handler) TipTrip(
+	ctx context.Context,
+	req *pb.TipTripReq,
+) (*pb.TripTip, error) {
+	trip, tip, err := h.service.TipTrip(
+		ctx,
+		req.GetTripID(),
+		req.GetUserID(),
+		req.GetAmountInCents(),
+		req.GetPercent(),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "tipTripErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotOwned):
+			return nil, status.Errorf(codes.PermissionDenied, "tipTripErr: %v", err)
+		case errors.Is(err, domain.ErrInvalidTip):
+			return nil, status.Errorf(codes.InvalidArgument, "tipTripErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotTippable):
+			return nil, status.Errorf(codes.FailedPrecondition, "tipTripErr: %v", err)
+		case errors.Is(err, domain.ErrAlreadyTipped):
+			return nil, status.Errorf(codes.AlreadyExists, "tipTripErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "tipTripErr: %v", err)
+	}
+
+	if err := h.publisher.PublishTipEvent(ctx, trip, tip); err != nil {
 		return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
 	}
 
-	return &pb.CreateTripRes{TripID: trip.ID.Hex()}, nil
+	return tip.ToProto(), nil
+}
+
+func (h *handler) InviteToSplit(
+	ctx context.Context,
+	req *pb.InviteToSplitReq,
+) (*pb.Trip, error) {
+	trip, invited, err := h.service.InviteToSplit(ctx, req.GetTripID(), req.GetUserID(), req.GetInviteeIDs())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "inviteToSplitErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotOwned):
+			return nil, status.Errorf(codes.PermissionDenied, "inviteToSplitErr: %v", err)
+		case errors.Is(err, domain.ErrInvalidSplit):
+			return nil, status.Errorf(codes.InvalidArgument, "inviteToSplitErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotSplittable):
+			return nil, status.Errorf(codes.FailedPrecondition, "inviteToSplitErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "inviteToSplitErr: %v", err)
+	}
+
+	for _, inviteeID := range invited {
+		if err := h.publisher.PublishSplitInvite(ctx, trip, inviteeID); err != nil {
+			return nil, status.Errorf(codes.Internal, "publishErr: %v", err.Error())
+		}
+	}
+
+	return trip.ToProto(), nil
+}
+
+func (h *handler) GetRatingSummary(
+	ctx context.Context,
+	req *pb.GetRatingSummaryReq,
+) (*pb.RatingSummary, error) {
+	summary, err := h.service.GetRatingSummary(ctx, req.GetUserID())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "getRatingSummaryErr: %v", err)
+	}
+
+	return summary.ToProto(), nil
+}
+
+func (h *handler) GetTrip(
+	ctx context.Context,
+	req *pb.GetTripReq,
+) (*pb.Trip, error) {
+	t, err := h.service.GetTrip(ctx, req.GetTripID(), req.GetUserID())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "getTripErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotOwned):
+			return nil, status.Errorf(codes.PermissionDenied, "getTripErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "getTripErr: %v", err)
+	}
+
+	return t.ToProto(), nil
+}
+
+func (h *handler) GetTripReceipt(
+	ctx context.Context,
+	req *pb.GetTripReceiptReq,
+) (*pb.Receipt, error) {
+	receipt, err := h.receipt(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return receipt.ToProto(), nil
+}
+
+func (h *handler) ExportTripReceipt(
+	ctx context.Context,
+	req *pb.GetTripReceiptReq,
+) (*pb.ExportTripReceiptRes, error) {
+	receipt, err := h.receipt(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := receiptHTML(receipt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to export the receipt: %v", err)
+	}
+
+	return &pb.ExportTripReceiptRes{
+		Filename: receiptFilename(receipt),
+		Html:     data,
+	}, nil
+}
+
+func (h *handler) receipt(ctx context.Context, req *pb.GetTripReceiptReq) (*domain.ReceiptModel, error) {
+	receipt, err := h.service.GetTripReceipt(ctx, req.GetTripID(), req.GetUserID())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTripNotFound):
+			return nil, status.Errorf(codes.NotFound, "getTripReceiptErr: %v", err)
+		case errors.Is(err, domain.ErrTripNotOwned):
+			return nil, status.Errorf(codes.PermissionDenied, "getTripReceiptErr: %v", err)
+		case errors.Is(err, domain.ErrReceiptNotAvailable):
+			return nil, status.Errorf(codes.FailedPrecondition, "getTripReceiptErr: %v", err)
+		}
+		return nil, status.Errorf(codes.Internal, "getTripReceiptErr: %v", err)
+	}
+
+	return receipt, nil
 }
@@ -0,0 +1,208 @@
+package grpc
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/types"
+)
+
+var receiptTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Receipt for trip {{.TripID}}</title>
+<style>
+  body { font-family: sans-serif; max-width: 40em; margin: 2em auto; color: #222; }
+  table { width: 100%; border-collapse: collapse; margin-bottom: 1.5em; }
+  td { padding: 0.3em 0; }
+  td.amount { text-align: right; }
+  tr.total td { border-top: 1px solid #222; font-weight: bold; }
+  .muted { color: #666; }
+</style>
+</head>
+<body>
+<h1>Receipt</h1>
+<p class="muted">Trip {{.TripID}} &middot; {{.IssuedAt}} &middot; Rider {{.UserID}}</p>
+
+<h2>Fare</h2>
+<table>
+{{- range .Lines}}
+  <tr{{if .Total}} class="total"{{end}}><td>{{.Label}}</td><td class="amount">{{.Amount}}</td></tr>
+{{- end}}
+</table>
+{{- if .Taxes}}
+<p class="muted">Including {{.Taxes}} of taxes.</p>
+{{- end}}
+
+<h2>Route</h2>
+<table>
+  <tr><td>Pickup</td><td class="amount">{{.Pickup}}</td></tr>
+{{- range .Stops}}
+  <tr><td>Stop</td><td class="amount">{{.}}</td></tr>
+{{- end}}
+  <tr><td>Destination</td><td class="amount">{{.Destination}}</td></tr>
+  <tr><td>Distance</td><td class="amount">{{.Distance}}</td></tr>
+  <tr><td>Duration</td><td class="amount">{{.Duration}}</td></tr>
+</table>
+
+<h2>Driver</h2>
+<table>
+  <tr><td>Driver</td><td class="amount">{{.Driver}}</td></tr>
+  <tr><td>Vehicle</td><td class="amount">{{.Vehicle}}</td></tr>
+  <tr><td>Package</td><td class="amount">{{.PackageSlug}}</td></tr>
+</table>
+
+<h2>Payment</h2>
+<table>
+  <tr><td>Method</td><td class="amount">{{.PaymentMethod}}</td></tr>
+  <tr><td>Status</td><td class="amount">{{.PaymentStatus}}</td></tr>
+{{- if .PaidBy}}
+  <tr><td>Paid by</td><td class="amount">{{.PaidBy}}</td></tr>
+{{- end}}
+{{- if .Refunded}}
+  <tr><td>Refunded</td><td class="amount">{{.Refunded}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+type receiptLine struct {
+	Label  string
+	Amount string
+	Total  bool
+}
+
+type receiptView struct {
+	TripID        string
+	UserID        string
+	IssuedAt      string
+	Lines         []receiptLine
+	Taxes         string
+	Pickup        string
+	Stops         []string
+	Destination   string
+	Distance      string
+	Duration      string
+	Driver        string
+	Vehicle       string
+	PackageSlug   string
+	PaymentMethod string
+	PaymentStatus string
+	PaidBy        string
+	Refunded      string
+}
+
+// receiptHTML renders the receipt as a standalone HTML document, ready to be printed
+func receiptHTML(receipt *domain.ReceiptModel) ([]byte, error) {
+	money := func(cents float64) string {
+		return fmt.Sprintf("%.2f %s", cents/100, strings.ToUpper(receipt.Currency))
+	}
+
+	fare := receipt.Fare
+	view := &receiptView{
+		TripID:        receipt.TripID,
+		UserID:        receipt.UserID,
+		IssuedAt:      receipt.IssuedAt.UTC().Format(time.RFC1123),
+		Pickup:        coordinate(receipt.Route.Pickup),
+		Destination:   coordinate(receipt.Route.Destination),
+		Distance:      fmt.Sprintf("%.1f km", receipt.Route.Distance/1000),
+		Duration:      fmt.Sprintf("%.0f min", receipt.Route.Duration/60),
+		Driver:        receipt.Driver.GetName(),
+		Vehicle:       vehicle(receipt),
+		PackageSlug:   receipt.PackageSlug,
+		PaymentMethod: "Not reported",
+		PaymentStatus: "Pending",
+	}
+
+	items := []struct {
+		label  string
+		amount float64
+	}{
+		{"Base fare", fare.BaseInCents},
+		{"Distance", fare.DistanceInCents},
+		{"Time", fare.TimeInCents},
+		{"Stops", fare.StopsInCents},
+		{"Waiting time", fare.WaitingInCents},
+		{"Tolls", fare.TollsInCents},
+		{"Surge", fare.SurgeInCents},
+		{"Pool discount", -fare.PoolDiscountInCents},
+		{"Promo " + fare.PromoCode, -fare.PromoDiscountInCents},
+	}
+	for _, item := range items {
+		// The base fare always shows, the other items only when they apply
+		if item.amount != 0 || item.label == "Base fare" {
+			view.Lines = append(view.Lines, receiptLine{Label: item.label, Amount: money(item.amount)})
+		}
+	}
+
+	view.Lines = append(view.Lines, receiptLine{Label: "Fare", Amount: money(fare.TotalPriceInCents), Total: true})
+	if fare.ShareInCents > 0 {
+		view.Lines = append(view.Lines, receiptLine{Label: "Your share of the split fare", Amount: money(fare.ShareInCents)})
+	}
+	if receipt.TipInCents > 0 {
+		view.Lines = append(view.Lines, receiptLine{Label: "Tip", Amount: money(receipt.TipInCents)})
+	}
+	view.Lines = append(view.Lines, receiptLine{Label: "Total", Amount: money(receipt.TotalInCents), Total: true})
+
+	if receipt.TaxesInCents > 0 {
+		view.Taxes = money(receipt.TaxesInCents)
+	}
+
+	for _, stop := range receipt.Route.Waypoints {
+		view.Stops = append(view.Stops, coordinate(stop))
+	}
+
+	if payment := receipt.Payment; payment != nil {
+		if payment.Method != "" {
+			view.PaymentMethod = payment.Method
+		}
+		view.PaymentStatus = strings.ReplaceAll(payment.Status(), "_", " ")
+		if payment.PayerID != "" && payment.PayerID != receipt.UserID {
+			view.PaidBy = payment.PayerID
+		}
+		if payment.RefundedInCents > 0 {
+			view.Refunded = money(float64(payment.RefundedInCents))
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := receiptTemplate.Execute(buf, view); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func receiptFilename(receipt *domain.ReceiptModel) string {
+	return fmt.Sprintf("receipt-%s-%s.html", receipt.TripID, receipt.IssuedAt.UTC().Format(time.DateOnly))
+}
+
+func coordinate(c *types.Coordinate) string {
+	if c == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.5f, %.5f", c.Latitude, c.Longitude)
+}
+
+// vehicle describes the vehicle of the driver, only its plate is known for the drivers without a profile
+func vehicle(receipt *domain.ReceiptModel) string {
+	driver := receipt.Driver
+	description := strings.Join(strings.Fields(fmt.Sprintf(
+		"%s %s %s",
+		driver.GetCarColor(),
+		driver.GetCarMake(),
+		driver.GetCarModel(),
+	)), " ")
+	if description == "" {
+		return driver.GetCarPlate()
+	}
+
+	return fmt.Sprintf("%s (%s)", description, driver.GetCarPlate())
+}
@@ -3,19 +3,33 @@ package repository
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"ride-sharing/services/trip-service/internal/domain"
+	pb "ride-sharing/shared/proto/trip"
+
+	"google.golang.org/protobuf/proto"
 )
 
 type inMemRepository struct {
+	mu        sync.RWMutex
 	trips     map[string]*domain.TripModel
 	rideFares map[string]*domain.RideFareModel
+	// ratings by ratee, oldest first
+	ratings map[string][]*domain.RatingModel
+	// promo campaigns by code, and their redemptions by fare
+	promoCampaigns   map[string]*domain.PromoCampaignModel
+	promoRedemptions map[string]*domain.PromoRedemptionModel
 }
 
 func NewInMemRepository() *inMemRepository {
 	return &inMemRepository{
 		trips:     make(map[string]*domain.TripModel),
 		rideFares: make(map[string]*domain.RideFareModel),
+		ratings:   make(map[string][]*domain.RatingModel),
+
+		promoCampaigns:   make(map[string]*domain.PromoCampaignModel),
+		promoRedemptions: make(map[string]*domain.PromoRedemptionModel),
 	}
 }
 
@@ -23,15 +37,85 @@ func (r *inMemRepository) CreateTrip(
 	ctx context.Context,
 	trip *domain.TripModel,
 ) (*domain.TripModel, error) {
-	r.trips[trip.ID.Hex()] = trip
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.trips[trip.ID.Hex()] = cloneTrip(trip)
 	return trip, nil
 }
 
+func (r *inMemRepository) GetTripByID(
+	ctx context.Context,
+	id string,
+) (*domain.TripModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trip, ok := r.trips[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripNotFound, id)
+	}
+
+	return cloneTrip(trip), nil
+}
+
+func (r *inMemRepository) UpdateTrip(
+	ctx context.Context,
+	trip *domain.TripModel,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.trips[trip.ID.Hex()]; !ok {
+		return fmt.Errorf("%w: %s", domain.ErrTripNotFound, trip.ID.Hex())
+	}
+
+	r.trips[trip.ID.Hex()] = cloneTrip(trip)
+	return nil
+}
+
+func (r *inMemRepository) ListTripsByStatus(
+	ctx context.Context,
+	status string,
+) ([]*domain.TripModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trips := make([]*domain.TripModel, 0)
+	for _, trip := range r.trips {
+		if trip.Status == status {
+			trips = append(trips, cloneTrip(trip))
+		}
+	}
+
+	return trips, nil
+}
+
+func (r *inMemRepository) CountRiderTrips(
+	ctx context.Context,
+	userID string,
+) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	count := 0
+	for _, trip := range r.trips {
+		if trip.Status != domain.TripStatusCancelled && trip.HasRider(userID) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 func (r *inMemRepository) SaveRideFare(
 	ctx context.Context,
 	fare *domain.RideFareModel,
 ) error {
-	r.rideFares[fare.ID.Hex()] = fare
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rideFares[fare.ID.Hex()] = cloneRideFare(fare)
 	return nil
 }
 
@@ -39,10 +123,195 @@ func (r *inMemRepository) GetRideFareByID(
 	ctx context.Context,
 	id string,
 ) (*domain.RideFareModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	fare, ok := r.rideFares[id]
 	if !ok {
 		return nil, fmt.Errorf("ride fare with id %s doesn't exist", id)
 	}
 
-	return fare, nil
+	return cloneRideFare(fare), nil
+}
+
+func (r *inMemRepository) SaveRating(
+	ctx context.Context,
+	rating *domain.RatingModel,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.ratings[rating.RateeID] {
+		if existing.TripID == rating.TripID && existing.RaterID == rating.RaterID {
+			return fmt.Errorf("%w: %s already rated %s on trip %s",
+				domain.ErrAlreadyRated, rating.RaterID, rating.RateeID, rating.TripID)
+		}
+	}
+
+	r.ratings[rating.RateeID] = append(r.ratings[rating.RateeID], rating)
+	return nil
+}
+
+func (r *inMemRepository) ListLatestRatings(
+	ctx context.Context,
+	rateeID string,
+	limit int,
+) ([]*domain.RatingModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := r.ratings[rateeID]
+	latest := make([]*domain.RatingModel, 0, min(len(all), limit))
+	for i := len(all) - 1; i >= 0 && len(latest) < limit; i-- {
+		latest = append(latest, all[i])
+	}
+
+	return latest, nil
+}
+
+func (r *inMemRepository) SavePromoCampaign(
+	ctx context.Context,
+	campaign *domain.PromoCampaignModel,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.promoCampaigns[campaign.Code] = campaign
+	return nil
+}
+
+func (r *inMemRepository) GetPromoCampaign(
+	ctx context.Context,
+	code string,
+) (*domain.PromoCampaignModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	campaign, ok := r.promoCampaigns[code]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPromoNotFound, code)
+	}
+
+	return campaign, nil
+}
+
+func (r *inMemRepository) CountPromoRedemptions(
+	ctx context.Context,
+	code, userID string,
+) (int, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	total, byUser := r.countPromoRedemptions(code, userID)
+	return total, byUser, nil
+}
+
+func (r *inMemRepository) RedeemPromo(
+	ctx context.Context,
+	redemption *domain.PromoRedemptionModel,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	campaign, ok := r.promoCampaigns[redemption.Code]
+	if !ok {
+		return fmt.Errorf("%w: %s", domain.ErrPromoNotFound, redemption.Code)
+	}
+
+	if _, ok := r.promoRedemptions[redemption.FareID]; ok {
+		return fmt.Errorf("%w: fare %s was already booked with %s", domain.ErrPromoExhausted, redemption.FareID, redemption.Code)
+	}
+
+	total, byUser := r.countPromoRedemptions(redemption.Code, redemption.UserID)
+	if campaign.MaxRedemptions > 0 && total >= campaign.MaxRedemptions {
+		return fmt.Errorf("%w: %s was redeemed %d times", domain.ErrPromoExhausted, redemption.Code, total)
+	}
+	// A first ride is only booked once, whatever the limit per rider
+	if campaign.FirstRideOnly && byUser > 0 {
+		return fmt.Errorf("%w: %s already booked a first ride with %s", domain.ErrPromoExhausted, redemption.UserID, redemption.Code)
+	}
+	if campaign.MaxRedemptionsPerUser > 0 && byUser >= campaign.MaxRedemptionsPerUser {
+		return fmt.Errorf("%w: %s redeemed %s %d times", domain.ErrPromoExhausted, redemption.UserID, redemption.Code, byUser)
+	}
+
+	r.promoRedemptions[redemption.FareID] = redemption
+	return nil
+}
+
+func (r *inMemRepository) ReleasePromo(
+	ctx context.Context,
+	fareID string,
+) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.promoRedemptions, fareID)
+	return nil
+}
+
+// countPromoRedemptions must be called with the mutex held
+func (r *inMemRepository) countPromoRedemptions(code, userID string) (total int, byUser int) {
+	for _, redemption := range r.promoRedemptions {
+		if redemption.Code != code {
+			continue
+		}
+
+		total++
+		if redemption.UserID == userID {
+			byUser++
+		}
+	}
+
+	return total, byUser
+}
+
+// cloneTrip copies the trip, so that the callers can't change the stored one without UpdateTrip. The routes
+// and coordinates are shared, they aren't changed once quoted.
+func cloneTrip(trip *domain.TripModel) *domain.TripModel {
+	clone := *trip
+	clone.RideFare = cloneRideFare(trip.RideFare)
+	if trip.Driver != nil {
+		clone.Driver = proto.Clone(trip.Driver).(*pb.TripDriver)
+	}
+	if trip.FinalFare != nil {
+		finalFare := *trip.FinalFare
+		clone.FinalFare = &finalFare
+	}
+
+	clone.Riders = cloneAll(trip.Riders, func(rider *domain.TripRider) *domain.TripRider {
+		return &domain.TripRider{UserID: rider.UserID, RideFare: cloneRideFare(rider.RideFare)}
+	})
+	clone.StopSequence = cloneAll(trip.StopSequence, copyOf)
+	clone.Payments = cloneAll(trip.Payments, copyOf)
+	clone.Tips = cloneAll(trip.Tips, copyOf)
+	clone.SplitParticipants = cloneAll(trip.SplitParticipants, copyOf)
+
+	return &clone
+}
+
+func cloneRideFare(fare *domain.RideFareModel) *domain.RideFareModel {
+	if fare == nil {
+		return nil
+	}
+
+	clone := *fare
+	return &clone
+}
+
+func cloneAll[T any](items []*T, clone func(*T) *T) []*T {
+	if items == nil {
+		return nil
+	}
+
+	clones := make([]*T, len(items))
+	for i, item := range items {
+		clones[i] = clone(item)
+	}
+
+	return clones
+}
+
+func copyOf[T any](item *T) *T {
+	clone := *item
+	return &clone
 }
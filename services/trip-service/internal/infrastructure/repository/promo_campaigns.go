@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+// LoadPromoCampaigns saves the campaigns of the JSON file, an array of campaigns, to the repository
+func LoadPromoCampaigns(ctx context.Context, repo domain.PromoRepository, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read the promo campaigns: %w", err)
+	}
+
+	var campaigns []*domain.PromoCampaignModel
+	if err := json.Unmarshal(data, &campaigns); err != nil {
+		return 0, fmt.Errorf("failed to parse the promo campaigns: %w", err)
+	}
+
+	seen := make(map[string]bool, len(campaigns))
+	for _, campaign := range campaigns {
+		campaign.Code = domain.NormalizePromoCode(campaign.Code)
+		if err := campaign.Validate(); err != nil {
+			return 0, err
+		}
+		if seen[campaign.Code] {
+			return 0, fmt.Errorf("%w: %s is defined twice", domain.ErrInvalidPromo, campaign.Code)
+		}
+		seen[campaign.Code] = true
+	}
+
+	for _, campaign := range campaigns {
+		if err := repo.SavePromoCampaign(ctx, campaign); err != nil {
+			return 0, fmt.Errorf("failed to save the promo campaign %s: %w", campaign.Code, err)
+		}
+	}
+
+	return len(campaigns), nil
+}
@@ -0,0 +1,204 @@
+package routing
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+
+	"github.com/mmcloughlin/geohash"
+)
+
+type CacheConfig struct {
+	TTL        time.Duration
+	MaxEntries int
+	// GeohashPrecision controls how coordinates are snapped, 7 characters is a ~150m cell
+	GeohashPrecision uint
+}
+
+// CacheStats are the counters of a route cache
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Shared  uint64 // lookups that waited for an identical in-flight lookup
+	Entries int
+}
+
+type cacheEntry struct {
+	key       string
+	route     *tripTypes.OsrmAPIResponse
+	expiresAt time.Time
+}
+
+// inflightCall is a lookup in progress that identical lookups can wait for
+type inflightCall struct {
+	wg    sync.WaitGroup
+	route *tripTypes.OsrmAPIResponse
+	err   error
+}
+
+// cachedProvider is an LRU cache with TTL in front of another route provider.
+// Every stop is snapped to a geohash cell, so riders moving the pin
+// around slightly don't trigger a new lookup.
+type cachedProvider struct {
+	provider domain.RouteProvider
+	cfg      CacheConfig
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List // front is the most recently used
+	inflight map[string]*inflightCall
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	shared atomic.Uint64
+}
+
+func NewCachedProvider(provider domain.RouteProvider, cfg CacheConfig) *cachedProvider {
+	if cfg.GeohashPrecision == 0 || cfg.GeohashPrecision > 12 {
+		cfg.GeohashPrecision = 7
+	}
+
+	return &cachedProvider{
+		provider: provider,
+		cfg:      cfg,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[string]*inflightCall),
+	}
+}
+
+func (c *cachedProvider) GetRoute(
+	ctx context.Context,
+	stops []*types.Coordinate,
+) (*tripTypes.OsrmAPIResponse, error) {
+	if err := domain.ValidateStops(stops); err != nil {
+		return nil, err
+	}
+
+	key := c.key(stops)
+
+	c.mu.Lock()
+	if route, ok := c.get(key); ok {
+		c.mu.Unlock()
+		c.hits.Add(1)
+		return route, nil
+	}
+
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		c.shared.Add(1)
+		call.wg.Wait()
+		return call.route, call.err
+	}
+
+	call := new(inflightCall)
+	call.wg.Add(1)
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	c.misses.Add(1)
+
+	// Other callers may be waiting for this lookup, so it shouldn't die with this caller's request
+	call.route, call.err = c.provider.GetRoute(context.WithoutCancel(ctx), stops)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if call.err == nil {
+		c.set(key, call.route)
+	}
+	c.mu.Unlock()
+
+	call.wg.Done()
+
+	return call.route, call.err
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *cachedProvider) Stats() CacheStats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Shared:  c.shared.Load(),
+		Entries: entries,
+	}
+}
+
+// ReportStats logs the cache counters every interval until the context is done
+func (c *cachedProvider) ReportStats(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := c.Stats()
+			log.Printf(
+				"Route cache stats: hits=%d misses=%d shared=%d entries=%d",
+				stats.Hits, stats.Misses, stats.Shared, stats.Entries,
+			)
+		}
+	}
+}
+
+func (c *cachedProvider) key(stops []*types.Coordinate) string {
+	cells := make([]string, len(stops))
+	for idx, stop := range stops {
+		cells[idx] = geohash.EncodeWithPrecision(stop.Latitude, stop.Longitude, c.cfg.GeohashPrecision)
+	}
+
+	return strings.Join(cells, ":")
+}
+
+// get must be called with the mutex held
+func (c *cachedProvider) get(key string) (*tripTypes.OsrmAPIResponse, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+
+	return entry.route, true
+}
+
+// set must be called with the mutex held
+func (c *cachedProvider) set(key string, route *tripTypes.OsrmAPIResponse) {
+	expiresAt := time.Now().Add(c.cfg.TTL)
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.route = route
+		entry.expiresAt = expiresAt
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, route: route, expiresAt: expiresAt})
+
+	for c.cfg.MaxEntries > 0 && c.lru.Len() > c.cfg.MaxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,161 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+// newTestCache puts a cache in front of a fake answering every lookup with the same route
+func newTestCache(cfg CacheConfig) (*cachedProvider, *FakeProvider) {
+	fake := NewFakeProvider(map[string]*tripTypes.OsrmAPIResponse{DefaultRecordingKey: testRoute(1_000)})
+	return NewCachedProvider(fake, cfg), fake
+}
+
+func TestCachedProviderSnapsStops(t *testing.T) {
+	cache, fake := newTestCache(CacheConfig{TTL: time.Minute})
+
+	mustGetRoute(t, cache, testStops(40.70, -74.00))
+	// ~10m away, in the same ~150m cells
+	mustGetRoute(t, cache, testStops(40.7001, -74.0001))
+
+	if fake.Calls() != 1 {
+		t.Errorf("%d lookups, want 1", fake.Calls())
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("stats = %+v, want 1 hit and 1 miss", stats)
+	}
+}
+
+func TestCachedProviderTTL(t *testing.T) {
+	cache, fake := newTestCache(CacheConfig{TTL: time.Minute})
+	stops := testStops(40.70, -74.00)
+
+	mustGetRoute(t, cache, stops)
+	mustGetRoute(t, cache, stops)
+	if fake.Calls() != 1 {
+		t.Fatalf("%d lookups before the TTL, want 1", fake.Calls())
+	}
+
+	// The entry expired
+	cache.mu.Lock()
+	cache.entries[cache.key(stops)].Value.(*cacheEntry).expiresAt = time.Now().Add(-time.Second)
+	cache.mu.Unlock()
+
+	mustGetRoute(t, cache, stops)
+	if fake.Calls() != 2 {
+		t.Errorf("%d lookups after the TTL, want 2", fake.Calls())
+	}
+	if stats := cache.Stats(); stats.Entries != 1 {
+		t.Errorf("%d entries, want 1", stats.Entries)
+	}
+}
+
+func TestCachedProviderEviction(t *testing.T) {
+	cache, fake := newTestCache(CacheConfig{TTL: time.Minute, MaxEntries: 2})
+	first, second, third := testStops(40.70, -74.00), testStops(40.80, -74.00), testStops(40.90, -74.00)
+
+	mustGetRoute(t, cache, first)
+	mustGetRoute(t, cache, second)
+	// first is now the most recently used, second gets evicted
+	mustGetRoute(t, cache, first)
+	mustGetRoute(t, cache, third)
+
+	if stats := cache.Stats(); stats.Entries != 2 {
+		t.Errorf("%d entries, want 2", stats.Entries)
+	}
+
+	calls := fake.Calls()
+	mustGetRoute(t, cache, first)
+	if fake.Calls() != calls {
+		t.Error("most recently used route evicted")
+	}
+	mustGetRoute(t, cache, second)
+	if fake.Calls() != calls+1 {
+		t.Error("least recently used route not evicted")
+	}
+}
+
+// gatedProvider holds the lookups until the gate is closed
+type gatedProvider struct {
+	domain.RouteProvider
+	started chan struct{}
+	gate    chan struct{}
+}
+
+func (p *gatedProvider) GetRoute(
+	ctx context.Context,
+	stops []*types.Coordinate,
+) (*tripTypes.OsrmAPIResponse, error) {
+	p.started <- struct{}{}
+	<-p.gate
+	return p.RouteProvider.GetRoute(ctx, stops)
+}
+
+func TestCachedProviderSharesLookups(t *testing.T) {
+	fake := NewFakeProvider(map[string]*tripTypes.OsrmAPIResponse{DefaultRecordingKey: testRoute(1_000)})
+	gated := &gatedProvider{RouteProvider: fake, started: make(chan struct{}, 10), gate: make(chan struct{})}
+	cache := NewCachedProvider(gated, CacheConfig{TTL: time.Minute})
+	stops := testStops(40.70, -74.00)
+
+	const callers = 5
+	routes := make([]*tripTypes.OsrmAPIResponse, callers)
+	var wg sync.WaitGroup
+
+	getRoute := func(i int) {
+		defer wg.Done()
+
+		route, err := cache.GetRoute(context.Background(), stops)
+		if err != nil {
+			t.Errorf("GetRoute() error = %v", err)
+		}
+		routes[i] = route
+	}
+
+	wg.Add(1)
+	go getRoute(0)
+	<-gated.started
+
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go getRoute(i)
+	}
+
+	// The other callers wait for the lookup in flight
+	for cache.Stats().Shared < callers-1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(gated.gate)
+	wg.Wait()
+
+	if fake.Calls() != 1 {
+		t.Errorf("%d lookups, want 1", fake.Calls())
+	}
+	for i, route := range routes {
+		if route != routes[0] {
+			t.Errorf("caller %d got another route", i)
+		}
+	}
+}
+
+func TestCachedProviderSkipsErrors(t *testing.T) {
+	fake := NewFakeProvider(nil)
+	cache := NewCachedProvider(fake, CacheConfig{TTL: time.Minute})
+	stops := testStops(40.70, -74.00)
+
+	for range 2 {
+		if _, err := cache.GetRoute(context.Background(), stops); !errors.Is(err, domain.ErrNoRoute) {
+			t.Fatalf("GetRoute() error = %v, want %v", err, domain.ErrNoRoute)
+		}
+	}
+
+	if fake.Calls() != 2 {
+		t.Errorf("%d lookups, want 2, failed lookups aren't cached", fake.Calls())
+	}
+}
@@ -0,0 +1,88 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+// DefaultRecordingKey is used when there is no recording for the requested stops
+const DefaultRecordingKey = "default"
+
+// FakeProvider replays recorded OSRM responses, keyed by StopsKey.
+// It's meant for tests and local runs without network access.
+type FakeProvider struct {
+	mu         sync.RWMutex
+	recordings map[string]*tripTypes.OsrmAPIResponse
+	calls      int
+}
+
+func NewFakeProvider(recordings map[string]*tripTypes.OsrmAPIResponse) *FakeProvider {
+	if recordings == nil {
+		recordings = make(map[string]*tripTypes.OsrmAPIResponse)
+	}
+
+	return &FakeProvider{recordings: recordings}
+}
+
+// LoadFakeProvider reads the recordings from a JSON object of StopsKey -> OSRM response
+func LoadFakeProvider(path string) (*FakeProvider, error) {
+	if path == "" {
+		return NewFakeProvider(nil), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the route recordings: %w", err)
+	}
+
+	recordings := make(map[string]*tripTypes.OsrmAPIResponse)
+	if err := json.Unmarshal(data, &recordings); err != nil {
+		return nil, fmt.Errorf("failed to parse the route recordings: %w", err)
+	}
+
+	return NewFakeProvider(recordings), nil
+}
+
+// Record stores the response to return for the given stops
+func (p *FakeProvider) Record(stops []*types.Coordinate, response *tripTypes.OsrmAPIResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.recordings[StopsKey(stops)] = response
+}
+
+// Calls returns how many times GetRoute was called
+func (p *FakeProvider) Calls() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.calls
+}
+
+func (p *FakeProvider) GetRoute(
+	ctx context.Context,
+	stops []*types.Coordinate,
+) (*tripTypes.OsrmAPIResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+
+	route, ok := p.recordings[StopsKey(stops)]
+	if !ok {
+		route, ok = p.recordings[DefaultRecordingKey]
+	}
+
+	if !ok || len(route.Routes) == 0 {
+		return nil, domain.ErrNoRoute
+	}
+
+	return route, nil
+}
@@ -0,0 +1,44 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+type fallbackProvider struct {
+	primary  domain.RouteProvider
+	fallback domain.RouteProvider
+}
+
+// NewFallbackProvider uses the fallback provider whenever the primary one fails.
+// A "no route" answer is a valid answer, so it's returned as is.
+func NewFallbackProvider(primary, fallback domain.RouteProvider) *fallbackProvider {
+	return &fallbackProvider{
+		primary:  primary,
+		fallback: fallback,
+	}
+}
+
+func (p *fallbackProvider) GetRoute(
+	ctx context.Context,
+	stops []*types.Coordinate,
+) (*tripTypes.OsrmAPIResponse, error) {
+	route, err := p.primary.GetRoute(ctx, stops)
+	if err == nil || errors.Is(err, domain.ErrNoRoute) {
+		return route, err
+	}
+
+	// The caller gave up, there is no point in trying again
+	if ctx.Err() != nil {
+		return nil, err
+	}
+
+	log.Printf("Primary route provider failed, using the fallback: %v", err)
+
+	return p.fallback.GetRoute(ctx, stops)
+}
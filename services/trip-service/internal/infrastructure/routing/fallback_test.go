@@ -0,0 +1,100 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+)
+
+func testStops(lat, lng float64) []*types.Coordinate {
+	return []*types.Coordinate{
+		{Latitude: lat, Longitude: lng},
+		{Latitude: lat + 0.05, Longitude: lng},
+	}
+}
+
+func testRoute(distance float64) *tripTypes.OsrmAPIResponse {
+	return &tripTypes.OsrmAPIResponse{
+		Code:   "Ok",
+		Routes: []tripTypes.OsrmRoute{{Distance: distance, Duration: distance / 10}},
+	}
+}
+
+func mustGetRoute(t *testing.T, provider domain.RouteProvider, stops []*types.Coordinate) *tripTypes.OsrmAPIResponse {
+	t.Helper()
+
+	route, err := provider.GetRoute(context.Background(), stops)
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+
+	return route
+}
+
+// failingProvider fails every lookup, like an unreachable OSRM server
+type failingProvider struct {
+	err error
+}
+
+func (p failingProvider) GetRoute(context.Context, []*types.Coordinate) (*tripTypes.OsrmAPIResponse, error) {
+	return nil, p.err
+}
+
+func TestFallbackProvider(t *testing.T) {
+	stops := testStops(40.70, -74.00)
+	unreachable := errors.New("connection refused")
+
+	t.Run("primary answers", func(t *testing.T) {
+		fake := NewFakeProvider(nil)
+		fake.Record(stops, testRoute(7_000))
+
+		route := mustGetRoute(t, NewFallbackProvider(fake, NewHaversineProvider(30)), stops)
+		if route.Routes[0].Distance != 7_000 {
+			t.Errorf("distance = %f, want the one of the primary", route.Routes[0].Distance)
+		}
+	})
+
+	t.Run("primary fails", func(t *testing.T) {
+		route := mustGetRoute(t, NewFallbackProvider(failingProvider{unreachable}, NewHaversineProvider(36)), stops)
+
+		// 0.05 degrees of latitude is ~5.56km, driven at 10m/s
+		got := route.Routes[0]
+		if math.Abs(got.Distance-5_560) > 10 || math.Abs(got.Duration-got.Distance/10) > 0.001 {
+			t.Errorf("route = %.0fm in %.0fs, want the straight line at 36km/h", got.Distance, got.Duration)
+		}
+		if len(got.Legs) != 1 || len(got.Geometry.Coordinates) != 2 {
+			t.Errorf("%d legs and %d points, want 1 and 2", len(got.Legs), len(got.Geometry.Coordinates))
+		}
+	})
+
+	t.Run("no route", func(t *testing.T) {
+		fallback := NewFakeProvider(map[string]*tripTypes.OsrmAPIResponse{DefaultRecordingKey: testRoute(1_000)})
+
+		_, err := NewFallbackProvider(NewFakeProvider(nil), fallback).GetRoute(context.Background(), stops)
+		if !errors.Is(err, domain.ErrNoRoute) {
+			t.Errorf("GetRoute() error = %v, want %v", err, domain.ErrNoRoute)
+		}
+		if fallback.Calls() != 0 {
+			t.Error("fallback used for a route which doesn't exist")
+		}
+	})
+
+	t.Run("caller gave up", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		fallback := NewFakeProvider(map[string]*tripTypes.OsrmAPIResponse{DefaultRecordingKey: testRoute(1_000)})
+
+		_, err := NewFallbackProvider(failingProvider{context.Canceled}, fallback).GetRoute(ctx, stops)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("GetRoute() error = %v, want %v", err, context.Canceled)
+		}
+		if fallback.Calls() != 0 {
+			t.Error("fallback used after the caller gave up")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package routing
+
+import (
+	"context"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/types"
+	"ride-sharing/shared/util"
+)
+
+// haversineProvider estimates a route as the straight line between the two points.
+// It doesn't need any external service, so it's used locally and as a fallback.
+type haversineProvider struct {
+	speedKmh float64
+}
+
+func NewHaversineProvider(speedKmh float64) *haversineProvider {
+	if speedKmh <= 0 {
+		speedKmh = 30
+	}
+
+	return &haversineProvider{speedKmh: speedKmh}
+}
+
+func (p *haversineProvider) GetRoute(
+	ctx context.Context,
+	stops []*types.Coordinate,
+) (*tripTypes.OsrmAPIResponse, error) {
+	if err := domain.ValidateStops(stops); err != nil {
+		return nil, err
+	}
+
+	// km/h -> m/s
+	speedMps := p.speedKmh * 1000 / 3600
+
+	route := tripTypes.OsrmRoute{
+		Legs: make([]tripTypes.OsrmLeg, 0, len(stops)-1),
+		Geometry: tripTypes.OsrmGeometry{
+			Coordinates: make([][]float64, 0, len(stops)),
+		},
+	}
+
+	for idx, stop := range stops {
+		route.Geometry.Coordinates = append(
+			route.Geometry.Coordinates,
+			[]float64{stop.Longitude, stop.Latitude},
+		)
+
+		if idx == 0 {
+			continue
+		}
+
+		previous := stops[idx-1]
+		distance := util.HaversineMeters(
+			previous.Latitude, previous.Longitude,
+			stop.Latitude, stop.Longitude,
+		)
+
+		leg := tripTypes.OsrmLeg{Distance: distance, Duration: distance / speedMps}
+		route.Legs = append(route.Legs, leg)
+		route.Distance += leg.Distance
+		route.Duration += leg.Duration
+	}
+
+	return &tripTypes.OsrmAPIResponse{
+		Code:   "Ok",
+		Routes: []tripTypes.OsrmRoute{route},
+	}, nil
+}
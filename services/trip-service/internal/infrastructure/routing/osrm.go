@@ -0,0 +1,42 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/osrm"
+	"ride-sharing/shared/types"
+)
+
+type osrmProvider struct {
+	client *osrm.Client
+}
+
+func NewOSRMProvider(baseURL string, timeout time.Duration) *osrmProvider {
+	return &osrmProvider{
+		client: osrm.NewClient(baseURL, timeout),
+	}
+}
+
+func (p *osrmProvider) GetRoute(
+	ctx context.Context,
+	stops []*types.Coordinate,
+) (*tripTypes.OsrmAPIResponse, error) {
+	if err := domain.ValidateStops(stops); err != nil {
+		return nil, err
+	}
+
+	routeRes, err := p.client.Route(ctx, stops)
+	if err != nil {
+		if errors.Is(err, osrm.ErrNoRoute) {
+			return nil, fmt.Errorf("%w: %v", domain.ErrNoRoute, err)
+		}
+		return nil, err
+	}
+
+	return (*tripTypes.OsrmAPIResponse)(routeRes), nil
+}
@@ -0,0 +1,89 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+func TestOSRMProviderStatusMapping(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantErr     error
+		wantErrText string
+	}{
+		{
+			name:   "route found",
+			status: http.StatusOK,
+			body:   `{"code":"Ok","routes":[{"distance":1200,"duration":180,"legs":[{"distance":1200,"duration":180}]}]}`,
+		},
+		{
+			name:    "no route",
+			status:  http.StatusBadRequest,
+			body:    `{"code":"NoRoute","message":"Impossible route between points"}`,
+			wantErr: domain.ErrNoRoute,
+		},
+		{
+			name:    "no segment",
+			status:  http.StatusBadRequest,
+			body:    `{"code":"NoSegment","message":"Could not find a matching segment for coordinate 0"}`,
+			wantErr: domain.ErrNoRoute,
+		},
+		{
+			name:    "ok without routes",
+			status:  http.StatusOK,
+			body:    `{"code":"Ok","routes":[]}`,
+			wantErr: domain.ErrNoRoute,
+		},
+		{
+			name:        "other OSRM error",
+			status:      http.StatusBadRequest,
+			body:        `{"code":"InvalidQuery","message":"Query string malformed"}`,
+			wantErrText: "InvalidQuery",
+		},
+		{
+			name:        "not an OSRM answer",
+			status:      http.StatusBadGateway,
+			body:        `<html>Bad Gateway</html>`,
+			wantErrText: "status 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if want := "/route/v1/driving/" + StopsKey(testStops(40.70, -74.00)); r.URL.Path != want {
+					t.Errorf("path = %s, want %s", r.URL.Path, want)
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			route, err := NewOSRMProvider(server.URL+"/", time.Second).GetRoute(context.Background(), testStops(40.70, -74.00))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("GetRoute() error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantErrText != "":
+				if err == nil || errors.Is(err, domain.ErrNoRoute) || !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Errorf("GetRoute() error = %v, want an error with %q", err, tt.wantErrText)
+				}
+			case err != nil:
+				t.Errorf("GetRoute() error = %v", err)
+			case route.Routes[0].Distance != 1200 || len(route.Routes[0].Legs) != 1:
+				t.Errorf("route = %+v", route.Routes[0])
+			}
+		})
+	}
+}
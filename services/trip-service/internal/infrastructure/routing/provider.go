@@ -0,0 +1,97 @@
+// Package routing implements the route providers used to calculate trip routes
+package routing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/osrm"
+	"ride-sharing/shared/types"
+)
+
+const (
+	ProviderOSRM      = "osrm"
+	ProviderHaversine = "haversine"
+	ProviderFake      = "fake"
+)
+
+type Config struct {
+	// Provider is one of osrm, haversine or fake
+	Provider string
+	// Fallback enables the haversine provider when the primary one is unreachable
+	Fallback          bool
+	OSRMBaseURL       string
+	OSRMTimeout       time.Duration
+	HaversineSpeedKmh float64
+	// FakeResponsesFile is a JSON file with the recorded OSRM responses
+	FakeResponsesFile string
+
+	CacheEnabled bool
+	Cache        CacheConfig
+	// CacheStatsInterval is how often the cache hit/miss counters are logged, 0 disables it
+	CacheStatsInterval time.Duration
+}
+
+// ConfigFromEnv reads the routing configuration from the environment
+func ConfigFromEnv() Config {
+	return Config{
+		Provider:          env.GetString("ROUTE_PROVIDER", ProviderOSRM),
+		Fallback:          env.GetBool("ROUTE_PROVIDER_FALLBACK", true),
+		OSRMBaseURL:       env.GetString("OSRM_API", "http://router.project-osrm.org"),
+		OSRMTimeout:       env.GetDuration("OSRM_TIMEOUT", 5*time.Second),
+		HaversineSpeedKmh: env.GetFloat("HAVERSINE_SPEED_KMH", 30),
+		FakeResponsesFile: env.GetString("ROUTE_FAKE_RESPONSES_FILE", ""),
+		CacheEnabled:      env.GetBool("ROUTE_CACHE_ENABLED", true),
+		Cache: CacheConfig{
+			TTL:              env.GetDuration("ROUTE_CACHE_TTL", 10*time.Minute),
+			MaxEntries:       env.GetInt("ROUTE_CACHE_MAX_ENTRIES", 10_000),
+			GeohashPrecision: uint(env.GetInt("ROUTE_CACHE_GEOHASH_PRECISION", 7)),
+		},
+		CacheStatsInterval: env.GetDuration("ROUTE_CACHE_STATS_INTERVAL", time.Minute),
+	}
+}
+
+// NewProvider builds the route provider selected by the configuration.
+// The context bounds background work such as the cache stats reporting.
+func NewProvider(ctx context.Context, cfg Config) (domain.RouteProvider, error) {
+	var provider domain.RouteProvider
+
+	switch cfg.Provider {
+	case ProviderOSRM:
+		provider = NewOSRMProvider(cfg.OSRMBaseURL, cfg.OSRMTimeout)
+	case ProviderHaversine:
+		// Already the local provider, there is nothing to fall back to or to cache
+		return NewHaversineProvider(cfg.HaversineSpeedKmh), nil
+	case ProviderFake:
+		fake, err := LoadFakeProvider(cfg.FakeResponsesFile)
+		if err != nil {
+			return nil, err
+		}
+		provider = fake
+	default:
+		return nil, fmt.Errorf("unknown route provider: %q", cfg.Provider)
+	}
+
+	if cfg.CacheEnabled {
+		cached := NewCachedProvider(provider, cfg.Cache)
+		if cfg.CacheStatsInterval > 0 {
+			go cached.ReportStats(ctx, cfg.CacheStatsInterval)
+		}
+		provider = cached
+	}
+
+	// Fallback routes are rough estimates, they are kept out of the cache on purpose
+	if cfg.Fallback {
+		provider = NewFallbackProvider(provider, NewHaversineProvider(cfg.HaversineSpeedKmh))
+	}
+
+	return provider, nil
+}
+
+// StopsKey formats the stops as "lon,lat;lon,lat;...", the same format as an OSRM request path
+func StopsKey(stops []*types.Coordinate) string {
+	return osrm.StopsKey(stops)
+}
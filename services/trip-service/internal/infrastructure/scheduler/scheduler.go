@@ -0,0 +1,76 @@
+// Package scheduler triggers the driver matching and reminders of scheduled (book-ahead) rides
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/internal/infrastructure/events"
+	"ride-sharing/shared/contracts"
+)
+
+type Scheduler struct {
+	service   domain.TripService
+	publisher events.Publisher
+	interval  time.Duration
+}
+
+func NewScheduler(
+	service domain.TripService,
+	publisher events.Publisher,
+	interval time.Duration,
+) *Scheduler {
+	return &Scheduler{
+		service:   service,
+		publisher: publisher,
+		interval:  interval,
+	}
+}
+
+// Run checks the scheduled trips every interval until the context is done
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.tick(ctx, now)
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	reminders, err := s.service.RemindScheduledTrips(ctx, now)
+	if err != nil {
+		log.Printf("Failed to get the scheduled trips to remind: %v", err)
+	}
+
+	for _, trip := range reminders {
+		if err := s.publisher.PublishTripEvent(ctx, contracts.TripEventScheduledReminder, trip); err != nil {
+			log.Printf("Failed to publish the reminder of trip %s: %v", trip.ID.Hex(), err)
+		}
+	}
+
+	due, err := s.service.ActivateDueScheduledTrips(ctx, now)
+	if err != nil {
+		log.Printf("Failed to activate the due scheduled trips: %v", err)
+	}
+
+	for _, trip := range due {
+		log.Printf("Scheduled trip %s entered the lead window, looking for a driver", trip.ID.Hex())
+
+		if err := s.publisher.PublishTripEvent(ctx, contracts.TripEventCreated, trip); err != nil {
+			log.Printf("Failed to publish the created event of trip %s, retrying on the next check: %v", trip.ID.Hex(), err)
+
+			// Nobody looks for a driver until the event is published
+			if err := s.service.DeactivateScheduledTrip(ctx, trip.ID.Hex()); err != nil {
+				log.Printf("Failed to put trip %s back to scheduled: %v", trip.ID.Hex(), err)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/internal/infrastructure/events"
+	"ride-sharing/services/trip-service/internal/infrastructure/repository"
+	"ride-sharing/services/trip-service/internal/infrastructure/routing"
+	"ride-sharing/services/trip-service/internal/service"
+	"ride-sharing/shared/contracts"
+)
+
+// flakyPublisher fails to publish the created events while down
+type flakyPublisher struct {
+	events.Publisher
+	down    bool
+	created []string
+}
+
+func (p *flakyPublisher) PublishTripEvent(ctx context.Context, routingKey string, trip *domain.TripModel) error {
+	if routingKey != contracts.TripEventCreated {
+		return nil
+	}
+	if p.down {
+		return errors.New("connection refused")
+	}
+
+	p.created = append(p.created, trip.ID.Hex())
+	return nil
+}
+
+func TestTickRetriesCreatedEvent(t *testing.T) {
+	ctx := context.Background()
+	svc := service.NewService(repository.NewInMemRepository(), routing.NewHaversineProvider(30), nil)
+
+	pickupAt := time.Now().Add(time.Hour)
+	trip, err := svc.CreateTrip(ctx, &domain.RideFareModel{
+		UserID:            "rider",
+		PackageSlug:       "sedan",
+		TotalPriceInCents: 2_000,
+	}, pickupAt)
+	if err != nil {
+		t.Fatalf("CreateTrip() error = %v", err)
+	}
+
+	publisher := &flakyPublisher{down: true}
+	scheduler := NewScheduler(svc, publisher, time.Minute)
+	inLeadWindow := pickupAt.Add(-time.Minute)
+
+	scheduler.tick(ctx, inLeadWindow)
+
+	got, err := svc.GetTrip(ctx, trip.ID.Hex(), "rider")
+	if err != nil {
+		t.Fatalf("GetTrip() error = %v", err)
+	}
+	if got.Status != domain.TripStatusScheduled {
+		t.Fatalf("status after the failed publish = %s, want %s", got.Status, domain.TripStatusScheduled)
+	}
+
+	publisher.down = false
+	scheduler.tick(ctx, inLeadWindow)
+
+	got, _ = svc.GetTrip(ctx, trip.ID.Hex(), "rider")
+	if got.Status != domain.TripStatusPending {
+		t.Errorf("status = %s, want %s", got.Status, domain.TripStatusPending)
+	}
+	if len(publisher.created) != 1 || publisher.created[0] != trip.ID.Hex() {
+		t.Errorf("created events = %v, want one for trip %s", publisher.created, trip.ID.Hex())
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+func (s *service) GetTrip(ctx context.Context, tripID, userID string) (*domain.TripModel, error) {
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if !t.HasRider(userID) && !t.IsSplitParticipant(userID) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripNotOwned, userID)
+	}
+
+	return t, nil
+}
+
+func (s *service) RecordPayment(
+	ctx context.Context,
+	tripID string,
+	payment *domain.TripPayment,
+) (*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	var recorded *domain.TripPayment
+	for _, existing := range t.Payments {
+		if existing.UserID == payment.UserID {
+			recorded = existing
+			break
+		}
+	}
+
+	if recorded == nil {
+		recorded = &domain.TripPayment{UserID: payment.UserID}
+		t.Payments = append(t.Payments, recorded)
+	}
+
+	// The balance is paid on its own session and reported apart from the rest of the fare
+	if payment.AmountInCents > 0 {
+		recorded.AmountInCents = payment.AmountInCents
+	}
+	if payment.BalanceInCents > 0 {
+		recorded.BalanceInCents = payment.BalanceInCents
+	}
+	// The refunds only add up, an older event can't undo a newer one
+	recorded.RefundedInCents = max(recorded.RefundedInCents, payment.RefundedInCents)
+	recorded.Currency = payment.Currency
+	// The refund events don't tell who paid nor how
+	if payment.PayerID != "" {
+		recorded.PayerID = payment.PayerID
+	}
+	if payment.Method != "" {
+		recorded.Method = payment.Method
+	}
+
+	if participant := t.FindSplitParticipant(payment.UserID); participant != nil && participant.Status == domain.SplitStatusAccepted {
+		participant.PaymentStatus = domain.SplitPaymentPaid
+		if payment.PayerID != "" && payment.PayerID != payment.UserID {
+			participant.PaymentStatus = domain.SplitPaymentCoveredByOwner
+		}
+	}
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/env"
+	"ride-sharing/shared/types"
+	"ride-sharing/shared/util"
+)
+
+var (
+	// poolMaxDetour is how much a new pool rider can add to the remaining trip duration
+	poolMaxDetour = env.GetDuration("POOL_MAX_DETOUR", 10*time.Minute)
+	// poolDefaultSeatCapacity is used when the assigned driver didn't report the vehicle capacity
+	poolDefaultSeatCapacity = env.GetInt("POOL_DEFAULT_SEAT_CAPACITY", 3)
+	// poolMatchRadiusMeters is how far the pickup and the drop-off of a new rider can be from the remaining
+	// stops of a pool trip for its detour to be calculated
+	poolMatchRadiusMeters = env.GetFloat("POOL_MATCH_RADIUS_METERS", 3_000)
+)
+
+// poolJoinAttempts is how many times the matching is retried when the chosen trip changed meanwhile
+const poolJoinAttempts = 3
+
+// errPoolTripChanged is returned when the trip changed while the routes were calculated
+var errPoolTripChanged = errors.New("pool trip changed")
+
+// poolCandidate is a snapshot of a pool trip which can take another rider
+type poolCandidate struct {
+	tripID string
+	// stops are copies, the stops of the trip can change once the lock is released
+	stops []*domain.TripStop
+}
+
+// poolInsertion is a candidate position of a new rider in a pool trip stop sequence
+type poolInsertion struct {
+	candidate *poolCandidate
+	// pickupAt and dropoffAt are the indexes of the new stops in the resulting sequence
+	pickupAt  int
+	dropoffAt int
+	detour    float64 // seconds
+}
+
+func (s *service) JoinPoolTrip(
+	ctx context.Context,
+	fare *domain.RideFareModel,
+) (*domain.TripModel, error) {
+	if fare.PackageSlug != domain.PoolPackageSlug {
+		return nil, fmt.Errorf("%w: not a pool fare", domain.ErrNoPoolMatch)
+	}
+
+	for range poolJoinAttempts {
+		candidates, err := s.poolCandidates(ctx, fare)
+		if err != nil {
+			return nil, err
+		}
+
+		// The routes are calculated without holding the lock, the insertion is checked again when committed
+		var best *poolInsertion
+		for _, candidate := range candidates {
+			insertion, err := s.bestPoolInsertion(ctx, candidate, fare)
+			if err != nil {
+				log.Printf("Failed to calculate the pool detour of trip %s: %v", candidate.tripID, err)
+				continue
+			}
+
+			if insertion != nil && (best == nil || insertion.detour < best.detour) {
+				best = insertion
+			}
+		}
+
+		if best == nil {
+			return nil, domain.ErrNoPoolMatch
+		}
+
+		t, err := s.commitPoolInsertion(ctx, best, fare)
+		if errors.Is(err, errPoolTripChanged) {
+			continue
+		}
+
+		return t, err
+	}
+
+	return nil, domain.ErrNoPoolMatch
+}
+
+// commitPoolInsertion adds the rider to the trip, if it still has a free seat and the same stops
+func (s *service) commitPoolInsertion(
+	ctx context.Context,
+	insertion *poolInsertion,
+	fare *domain.RideFareModel,
+) (*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, insertion.candidate.tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	// The trip may have been cancelled, completed, filled up or driven further while the routes were calculated
+	if !hasFreeSeat(t, fare.UserID) || !sameStops(t.StopSequence, insertion.candidate.stops) {
+		return nil, errPoolTripChanged
+	}
+
+	sequence := slices.Insert(slices.Clone(t.StopSequence), insertion.pickupAt,
+		&domain.TripStop{UserID: fare.UserID, Type: domain.TripStopPickup, Location: fare.Pickup})
+	sequence = slices.Insert(sequence, insertion.dropoffAt,
+		&domain.TripStop{UserID: fare.UserID, Type: domain.TripStopDropoff, Location: fare.Destination})
+
+	t.Riders = append(t.Riders, &domain.TripRider{UserID: fare.UserID, RideFare: fare})
+	t.StopSequence = sequence
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
+
+func isPoolJoinable(t *domain.TripModel) bool {
+	return t.IsPool() &&
+		(t.Status == domain.TripStatusAssigned || t.Status == domain.TripStatusInProgress)
+}
+
+func seatCapacity(t *domain.TripModel) int {
+	if t.Driver != nil && t.Driver.SeatCapacity > 0 {
+		return int(t.Driver.SeatCapacity)
+	}
+
+	return poolDefaultSeatCapacity
+}
+
+// hasFreeSeat tells if the rider can join the trip
+func hasFreeSeat(t *domain.TripModel, userID string) bool {
+	if !isPoolJoinable(t) || len(t.Riders) >= seatCapacity(t) {
+		return false
+	}
+
+	return !slices.ContainsFunc(t.Riders, func(r *domain.TripRider) bool {
+		return r.UserID == userID
+	})
+}
+
+func sameStops(sequence, stops []*domain.TripStop) bool {
+	return slices.EqualFunc(sequence, stops, func(a, b *domain.TripStop) bool {
+		return a.UserID == b.UserID && a.Type == b.Type && a.Completed == b.Completed
+	})
+}
+
+// poolCandidates returns a snapshot of the pool trips with a free seat whose remaining stops pass near
+// the pickup and the drop-off of the rider
+func (s *service) poolCandidates(ctx context.Context, fare *domain.RideFareModel) ([]*poolCandidate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	candidates := make([]*poolCandidate, 0)
+
+	for _, status := range []string{domain.TripStatusAssigned, domain.TripStatusInProgress} {
+		trips, err := s.repo.ListTripsByStatus(ctx, status)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s trips: %w", status, err)
+		}
+
+		for _, t := range trips {
+			if !hasFreeSeat(t, fare.UserID) {
+				continue
+			}
+
+			remaining := t.StopSequence[poolAnchor(t.StopSequence):]
+			if !passesNear(remaining, fare.Pickup) || !passesNear(remaining, fare.Destination) {
+				continue
+			}
+
+			candidate := &poolCandidate{tripID: t.ID.Hex(), stops: make([]*domain.TripStop, len(t.StopSequence))}
+			for idx, stop := range t.StopSequence {
+				candidate.stops[idx] = &domain.TripStop{
+					UserID:    stop.UserID,
+					Type:      stop.Type,
+					Location:  stop.Location,
+					Completed: stop.Completed,
+				}
+			}
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, nil
+}
+
+// passesNear tells if one of the stops is within the match radius of the location
+func passesNear(stops []*domain.TripStop, location *types.Coordinate) bool {
+	return slices.ContainsFunc(stops, func(stop *domain.TripStop) bool {
+		return util.HaversineMeters(
+			stop.Location.Latitude, stop.Location.Longitude,
+			location.Latitude, location.Longitude,
+		) <= poolMatchRadiusMeters
+	})
+}
+
+// poolAnchor returns the index of the last completed stop, where the vehicle is coming from.
+// It's the first pickup until the driver reached it.
+func poolAnchor(stops []*domain.TripStop) int {
+	anchor := 0
+	for idx, stop := range stops {
+		if stop.Completed {
+			anchor = idx
+		}
+	}
+
+	return anchor
+}
+
+// bestPoolInsertion tries every pickup/drop-off position of the new rider after the stops
+// already completed, and returns the one adding the least time, if it's under the max detour.
+func (s *service) bestPoolInsertion(
+	ctx context.Context,
+	candidate *poolCandidate,
+	fare *domain.RideFareModel,
+) (*poolInsertion, error) {
+	// The anchor can't be moved, the new stops go after it
+	anchor := poolAnchor(candidate.stops)
+
+	remaining := candidate.stops[anchor:]
+	baseDuration, err := s.sequenceDuration(ctx, remaining)
+	if err != nil {
+		return nil, err
+	}
+
+	pickup := &domain.TripStop{UserID: fare.UserID, Type: domain.TripStopPickup, Location: fare.Pickup}
+	dropoff := &domain.TripStop{UserID: fare.UserID, Type: domain.TripStopDropoff, Location: fare.Destination}
+
+	var best *poolInsertion
+	for i := 1; i <= len(remaining); i++ {
+		withPickup := slices.Insert(slices.Clone(remaining), i, pickup)
+
+		for j := i + 1; j <= len(withPickup); j++ {
+			duration, err := s.sequenceDuration(ctx, slices.Insert(slices.Clone(withPickup), j, dropoff))
+			if errors.Is(err, domain.ErrNoRoute) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			detour := duration - baseDuration
+			if detour > poolMaxDetour.Seconds() || (best != nil && detour >= best.detour) {
+				continue
+			}
+
+			best = &poolInsertion{
+				candidate: candidate,
+				pickupAt:  anchor + i,
+				dropoffAt: anchor + j,
+				detour:    detour,
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// sequenceDuration returns the driving time through the stops, in seconds
+func (s *service) sequenceDuration(ctx context.Context, stops []*domain.TripStop) (float64, error) {
+	if len(stops) < 2 {
+		return 0, nil
+	}
+
+	coordinates := make([]*types.Coordinate, len(stops))
+	for idx, stop := range stops {
+		coordinates[idx] = stop.Location
+	}
+
+	route, err := s.routeProvider.GetRoute(ctx, coordinates)
+	if err != nil {
+		return 0, err
+	}
+
+	return route.Routes[0].Duration, nil
+}
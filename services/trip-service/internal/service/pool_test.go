@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/internal/infrastructure/repository"
+	"ride-sharing/services/trip-service/internal/infrastructure/routing"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
+)
+
+// countingProvider counts the routes requested to the haversine provider, 30km/h in a straight line
+type countingProvider struct {
+	provider domain.RouteProvider
+	calls    atomic.Int64
+}
+
+func (p *countingProvider) GetRoute(
+	ctx context.Context,
+	stops []*types.Coordinate,
+) (*tripTypes.OsrmAPIResponse, error) {
+	p.calls.Add(1)
+	return p.provider.GetRoute(ctx, stops)
+}
+
+func newPoolTestService(t *testing.T) (*service, *countingProvider) {
+	t.Helper()
+
+	maxDetour, matchRadius, capacity := poolMaxDetour, poolMatchRadiusMeters, poolDefaultSeatCapacity
+	t.Cleanup(func() {
+		poolMaxDetour, poolMatchRadiusMeters, poolDefaultSeatCapacity = maxDetour, matchRadius, capacity
+	})
+	// 10 minutes at 30km/h is 5km
+	poolMaxDetour = 10 * time.Minute
+	poolMatchRadiusMeters = 3_000
+	poolDefaultSeatCapacity = 3
+
+	provider := &countingProvider{provider: routing.NewHaversineProvider(30)}
+	return NewService(repository.NewInMemRepository(), provider, nil), provider
+}
+
+func poolFare(userID string, pickupLat, pickupLng, destinationLat, destinationLng float64) *domain.RideFareModel {
+	return &domain.RideFareModel{
+		UserID:      userID,
+		PackageSlug: domain.PoolPackageSlug,
+		Pickup:      &types.Coordinate{Latitude: pickupLat, Longitude: pickupLng},
+		Destination: &types.Coordinate{Latitude: destinationLat, Longitude: destinationLng},
+	}
+}
+
+// newAssignedPoolTrip creates a pool trip going ~5.5km north and assigns it to "driver"
+func newAssignedPoolTrip(t *testing.T, s *service, seatCapacity int32) *domain.TripModel {
+	t.Helper()
+	ctx := context.Background()
+
+	created, err := s.CreateTrip(ctx, poolFare("owner", 40.70, -74.00, 40.75, -74.00), time.Time{})
+	if err != nil {
+		t.Fatalf("CreateTrip() error = %v", err)
+	}
+
+	assigned, err := s.AssignDriver(ctx, created.ID.Hex(), &trip.TripDriver{Id: "driver", SeatCapacity: seatCapacity})
+	if err != nil {
+		t.Fatalf("AssignDriver() error = %v", err)
+	}
+
+	return assigned
+}
+
+// stopOrder describes the sequence as "user type" entries, with a "*" for the completed stops
+func stopOrder(stops []*domain.TripStop) []string {
+	order := make([]string, len(stops))
+	for i, stop := range stops {
+		order[i] = stop.UserID + " " + stop.Type
+		if stop.Completed {
+			order[i] += "*"
+		}
+	}
+
+	return order
+}
+
+func TestJoinPoolTripDetourThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		fare      *domain.RideFareModel
+		wantErr   error
+		wantOrder []string
+		// wantRoutes is false when the trip is filtered out before any route is calculated
+		wantRoutes bool
+	}{
+		{
+			name:       "on the way",
+			fare:       poolFare("rider", 40.71, -74.00, 40.74, -74.00),
+			wantOrder:  []string{"owner pickup", "rider pickup", "rider dropoff", "owner dropoff"},
+			wantRoutes: true,
+		},
+		{
+			name: "detour under the threshold",
+			// ~2.1km east of the route on both ends, adding ~4.2km
+			fare:       poolFare("rider", 40.70, -73.975, 40.75, -73.975),
+			wantOrder:  []string{"owner pickup", "rider pickup", "rider dropoff", "owner dropoff"},
+			wantRoutes: true,
+		},
+		{
+			name: "detour over the threshold",
+			// ~2.9km east of the route on both ends, adding ~5.7km
+			fare:       poolFare("rider", 40.70, -73.966, 40.75, -73.966),
+			wantErr:    domain.ErrNoPoolMatch,
+			wantRoutes: true,
+		},
+		{
+			name:    "drop-off outside of the match radius",
+			fare:    poolFare("rider", 40.71, -74.00, 40.90, -74.00),
+			wantErr: domain.ErrNoPoolMatch,
+		},
+		{
+			name:    "pickup outside of the match radius",
+			fare:    poolFare("rider", 40.50, -74.00, 40.74, -74.00),
+			wantErr: domain.ErrNoPoolMatch,
+		},
+		{
+			name:    "not a pool fare",
+			fare:    &domain.RideFareModel{UserID: "rider", PackageSlug: "sedan"},
+			wantErr: domain.ErrNoPoolMatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, provider := newPoolTestService(t)
+			created := newAssignedPoolTrip(t, s, 0)
+
+			joined, err := s.JoinPoolTrip(context.Background(), tt.fare)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("JoinPoolTrip() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := provider.calls.Load() > 0; got != tt.wantRoutes {
+				t.Errorf("%d routes calculated, want routes: %v", provider.calls.Load(), tt.wantRoutes)
+			}
+			if tt.wantErr != nil {
+				if len(created.Riders) != 1 || len(created.StopSequence) != 2 {
+					t.Errorf("trip changed: %d riders, stops %v", len(created.Riders), stopOrder(created.StopSequence))
+				}
+				return
+			}
+
+			if joined.ID != created.ID {
+				t.Errorf("joined trip %s, want %s", joined.ID.Hex(), created.ID.Hex())
+			}
+			if got := stopOrder(joined.StopSequence); !slices.Equal(got, tt.wantOrder) {
+				t.Errorf("stop sequence = %v, want %v", got, tt.wantOrder)
+			}
+			if !joined.HasRider("rider") {
+				t.Error("rider not added to the trip")
+			}
+		})
+	}
+}
+
+func TestJoinPoolTripKeepsCompletedStopsFirst(t *testing.T) {
+	s, _ := newPoolTestService(t)
+	ctx := context.Background()
+	created := newAssignedPoolTrip(t, s, 0)
+	tripID := created.ID.Hex()
+
+	if _, err := s.JoinPoolTrip(ctx, poolFare("second", 40.71, -74.00, 40.74, -74.00)); err != nil {
+		t.Fatalf("JoinPoolTrip() error = %v", err)
+	}
+
+	// The owner and the second rider were picked up
+	for stop := range 2 {
+		if _, err := s.ReachTripStop(ctx, tripID, "driver", stop); err != nil {
+			t.Fatalf("ReachTripStop(%d) error = %v", stop, err)
+		}
+	}
+
+	// The pickup is behind the vehicle, it can only go after the stops already completed
+	joined, err := s.JoinPoolTrip(ctx, poolFare("third", 40.705, -74.00, 40.745, -74.00))
+	if err != nil {
+		t.Fatalf("JoinPoolTrip() error = %v", err)
+	}
+
+	want := []string{
+		"owner pickup*",
+		"second pickup*",
+		"third pickup",
+		"second dropoff",
+		"third dropoff",
+		"owner dropoff",
+	}
+	if got := stopOrder(joined.StopSequence); !slices.Equal(got, want) {
+		t.Errorf("stop sequence = %v, want %v", got, want)
+	}
+	if joined.Status != domain.TripStatusInProgress {
+		t.Errorf("status = %s, want %s", joined.Status, domain.TripStatusInProgress)
+	}
+}
+
+func TestJoinPoolTripSeatCapacity(t *testing.T) {
+	s, _ := newPoolTestService(t)
+	ctx := context.Background()
+	created := newAssignedPoolTrip(t, s, 2)
+
+	if _, err := s.JoinPoolTrip(ctx, poolFare("owner", 40.71, -74.00, 40.74, -74.00)); !errors.Is(err, domain.ErrNoPoolMatch) {
+		t.Errorf("JoinPoolTrip() by a rider of the trip error = %v, want %v", err, domain.ErrNoPoolMatch)
+	}
+
+	// Both riders race for the last seat
+	var wg sync.WaitGroup
+	var joined atomic.Int64
+	for _, userID := range []string{"first", "second"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := s.JoinPoolTrip(ctx, poolFare(userID, 40.71, -74.00, 40.74, -74.00))
+			switch {
+			case err == nil:
+				joined.Add(1)
+			case !errors.Is(err, domain.ErrNoPoolMatch):
+				t.Errorf("JoinPoolTrip() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if joined.Load() != 1 {
+		t.Errorf("%d riders joined, want 1", joined.Load())
+	}
+
+	got, err := s.repo.GetTripByID(ctx, created.ID.Hex())
+	if err != nil {
+		t.Fatalf("GetTripByID() error = %v", err)
+	}
+	if len(got.Riders) != 2 || len(got.StopSequence) != 4 {
+		t.Errorf("%d riders and %d stops, want 2 and 4", len(got.Riders), len(got.StopSequence))
+	}
+}
+
+func TestReachTripStopPool(t *testing.T) {
+	s, _ := newPoolTestService(t)
+	ctx := context.Background()
+	created := newAssignedPoolTrip(t, s, 0)
+	tripID := created.ID.Hex()
+
+	if _, err := s.ReachTripStop(ctx, tripID, "driver", 1); !errors.Is(err, domain.ErrInvalidTripStop) {
+		t.Errorf("ReachTripStop() out of order error = %v, want %v", err, domain.ErrInvalidTripStop)
+	}
+	if _, err := s.ReachTripStop(ctx, tripID, "other", 0); !errors.Is(err, domain.ErrInvalidTripStop) {
+		t.Errorf("ReachTripStop() by another driver error = %v, want %v", err, domain.ErrInvalidTripStop)
+	}
+
+	reached, err := s.ReachTripStop(ctx, tripID, "driver", 0)
+	if err != nil {
+		t.Fatalf("ReachTripStop() error = %v", err)
+	}
+	if reached.Status != domain.TripStatusInProgress {
+		t.Errorf("status = %s, want %s", reached.Status, domain.TripStatusInProgress)
+	}
+	if got, want := stopOrder(reached.StopSequence), []string{"owner pickup*", "owner dropoff"}; !slices.Equal(got, want) {
+		t.Errorf("stop sequence = %v, want %v", got, want)
+	}
+
+	// Reporting the same stop again is a no-op
+	if _, err := s.ReachTripStop(ctx, tripID, "driver", 0); err != nil {
+		t.Errorf("ReachTripStop() again error = %v", err)
+	}
+	if _, err := s.ReachTripStop(ctx, tripID, "driver", 2); !errors.Is(err, domain.ErrInvalidTripStop) {
+		t.Errorf("ReachTripStop() past the last stop error = %v, want %v", err, domain.ErrInvalidTripStop)
+	}
+
+	if _, err := s.CompleteTrip(ctx, tripID, "driver", nil); err != nil {
+		t.Fatalf("CompleteTrip() error = %v", err)
+	}
+	if _, err := s.CompleteTrip(ctx, tripID, "driver", nil); !errors.Is(err, domain.ErrTripAlreadyCompleted) {
+		t.Errorf("CompleteTrip() again error = %v, want %v", err, domain.ErrTripAlreadyCompleted)
+	}
+	if _, err := s.ReachTripStop(ctx, tripID, "driver", 1); !errors.Is(err, domain.ErrInvalidTripStop) {
+		t.Errorf("ReachTripStop() on a completed trip error = %v, want %v", err, domain.ErrInvalidTripStop)
+	}
+}
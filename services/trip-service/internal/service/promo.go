@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+)
+
+func (s *service) ApplyPromoCode(
+	ctx context.Context,
+	code, userID string,
+	fares []*domain.RideFareModel,
+) error {
+	code = domain.NormalizePromoCode(code)
+	if code == "" {
+		return nil
+	}
+
+	campaign, err := s.checkPromo(ctx, code, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	applied := 0
+	for _, fare := range fares {
+		if !campaign.AppliesTo(fare.PackageSlug) {
+			continue
+		}
+
+		fare.ApplyPromo(campaign)
+		applied++
+	}
+
+	if applied == 0 {
+		return fmt.Errorf("%w: %s only applies to %v", domain.ErrPromoNotEligible, code, campaign.PackageSlugs)
+	}
+
+	return nil
+}
+
+func (s *service) RedeemPromo(ctx context.Context, fare *domain.RideFareModel) error {
+	if fare.PromoCode == "" {
+		return nil
+	}
+
+	now := time.Now()
+	if _, err := s.checkPromo(ctx, fare.PromoCode, fare.UserID, now); err != nil {
+		return err
+	}
+
+	// The repository checks the limits again along with the redemption, concurrent bookings can't exceed them
+	return s.repo.RedeemPromo(ctx, &domain.PromoRedemptionModel{
+		Code:            fare.PromoCode,
+		UserID:          fare.UserID,
+		FareID:          fare.ID.Hex(),
+		DiscountInCents: fare.DiscountInCents,
+		RedeemedAt:      now,
+	})
+}
+
+func (s *service) ReleasePromo(ctx context.Context, fare *domain.RideFareModel) error {
+	if fare == nil || fare.PromoCode == "" {
+		return nil
+	}
+
+	return s.repo.ReleasePromo(ctx, fare.ID.Hex())
+}
+
+// checkPromo returns the campaign of the promo code when the rider can use it now
+func (s *service) checkPromo(
+	ctx context.Context,
+	code, userID string,
+	now time.Time,
+) (*domain.PromoCampaignModel, error) {
+	campaign, err := s.repo.GetPromoCampaign(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := campaign.CheckActive(now); err != nil {
+		return nil, err
+	}
+
+	if campaign.FirstRideOnly {
+		trips, err := s.repo.CountRiderTrips(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count the trips of %s: %w", userID, err)
+		}
+		if trips > 0 {
+			return nil, fmt.Errorf("%w: %s is for the first ride only", domain.ErrPromoNotEligible, code)
+		}
+	}
+
+	total, byUser, err := s.repo.CountPromoRedemptions(ctx, code, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count the redemptions of %s: %w", code, err)
+	}
+	if campaign.MaxRedemptions > 0 && total >= campaign.MaxRedemptions {
+		return nil, fmt.Errorf("%w: %s", domain.ErrPromoExhausted, code)
+	}
+	if campaign.MaxRedemptionsPerUser > 0 && byUser >= campaign.MaxRedemptionsPerUser {
+		return nil, fmt.Errorf("%w: %s already used %s %d times", domain.ErrPromoExhausted, userID, code, byUser)
+	}
+
+	return campaign, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/env"
+)
+
+var (
+	// ratingWindow is how many of the latest ratings make the average of a user
+	ratingWindow = env.GetInt("RATING_WINDOW", 100)
+	// ratingMaxCommentLength keeps the comments to a few sentences
+	ratingMaxCommentLength = env.GetInt("RATING_MAX_COMMENT_LENGTH", 500)
+)
+
+const (
+	minRatingScore = 1
+	maxRatingScore = 5
+	maxRatingTags  = 10
+)
+
+func (s *service) RateTrip(
+	ctx context.Context,
+	tripID, userID, rateeID string,
+	score int,
+	tags []string,
+	comment string,
+) (*domain.RatingModel, *domain.RatingSummary, error) {
+	if score < minRatingScore || score > maxRatingScore {
+		return nil, nil, fmt.Errorf("%w: the score has to be between %d and %d", domain.ErrInvalidRating, minRatingScore, maxRatingScore)
+	}
+	if len(tags) > maxRatingTags {
+		return nil, nil, fmt.Errorf("%w: up to %d tags are allowed", domain.ErrInvalidRating, maxRatingTags)
+	}
+	if len(comment) > ratingMaxCommentLength {
+		return nil, nil, fmt.Errorf("%w: the comment is longer than %d characters", domain.ErrInvalidRating, ratingMaxCommentLength)
+	}
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if t.Status != domain.TripStatusCompleted {
+		return nil, nil, fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotRateable, tripID, t.Status)
+	}
+
+	rating := &domain.RatingModel{
+		TripID:    tripID,
+		RaterID:   userID,
+		Score:     score,
+		Tags:      normalizeTags(tags),
+		Comment:   strings.TrimSpace(comment),
+		CreatedAt: time.Now(),
+	}
+
+	switch {
+	case t.HasRider(userID):
+		rating.RateeID = t.Driver.GetId()
+		rating.RateeRole = domain.RateeRoleDriver
+	case t.Driver != nil && t.Driver.Id == userID:
+		if rateeID == "" {
+			rateeID = t.UserID
+		}
+		if !t.HasRider(rateeID) {
+			return nil, nil, fmt.Errorf("%w: %s didn't ride trip %s", domain.ErrTripNotRateable, rateeID, tripID)
+		}
+		rating.RateeID = rateeID
+		rating.RateeRole = domain.RateeRoleRider
+	default:
+		return nil, nil, fmt.Errorf("%w: %s wasn't part of trip %s", domain.ErrTripNotRateable, userID, tripID)
+	}
+
+	if err := s.repo.SaveRating(ctx, rating); err != nil {
+		return nil, nil, err
+	}
+
+	summary, err := s.GetRatingSummary(ctx, rating.RateeID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rating, summary, nil
+}
+
+func (s *service) GetRatingSummary(ctx context.Context, userID string) (*domain.RatingSummary, error) {
+	ratings, err := s.repo.ListLatestRatings(ctx, userID, ratingWindow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the ratings: %w", err)
+	}
+
+	summary := &domain.RatingSummary{UserID: userID, Count: len(ratings)}
+	if len(ratings) == 0 {
+		return summary, nil
+	}
+
+	total := 0
+	for _, r := range ratings {
+		total += r.Score
+	}
+	summary.Average = float64(total) / float64(len(ratings))
+
+	return summary, nil
+}
+
+// normalizeTags lower cases the tags and drops the empty and duplicated ones
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	return normalized
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	tripTypes "ride-sharing/services/trip-service/pkg/types"
+	"ride-sharing/shared/env"
+)
+
+var (
+	// receiptTaxRate is the tax rate included in the fares, 0.2 for 20%
+	receiptTaxRate = env.GetFloat("TRIP_RECEIPT_TAX_RATE", 0)
+	// receiptCurrency is the currency of the receipts whose payment wasn't reported yet
+	receiptCurrency = env.GetString("TRIP_RECEIPT_CURRENCY", "usd")
+)
+
+func (s *service) GetTripReceipt(ctx context.Context, tripID, userID string) (*domain.ReceiptModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.GetTrip(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Status != domain.TripStatusCompleted {
+		return nil, fmt.Errorf("%w: trip %s is %s", domain.ErrReceiptNotAvailable, tripID, t.Status)
+	}
+
+	// Pool riders have their own fare, split participants share the one of the owner
+	fare := t.RideFare
+	for _, rider := range t.Riders {
+		if rider.UserID == userID && rider.RideFare != nil {
+			fare = rider.RideFare
+		}
+	}
+	if fare == nil {
+		return nil, fmt.Errorf("%w: trip %s has no fare", domain.ErrReceiptNotAvailable, tripID)
+	}
+
+	breakdown := s.fareBreakdown(fare, t.FinalFare)
+	if !t.IsPool() {
+		if share := t.FareShare(userID, breakdown.TotalPriceInCents); share != breakdown.TotalPriceInCents {
+			breakdown.ShareInCents = share
+		}
+	}
+
+	receipt := &domain.ReceiptModel{
+		TripID:      tripID,
+		UserID:      userID,
+		PackageSlug: fare.PackageSlug,
+		Currency:    receiptCurrency,
+		IssuedAt:    t.CompletedAt,
+		Fare:        breakdown,
+		Route: &domain.ReceiptRoute{
+			Pickup:      fare.Pickup,
+			Destination: fare.Destination,
+			Waypoints:   fare.Waypoints,
+			CompletedAt: t.CompletedAt,
+		},
+		Driver: t.Driver,
+	}
+
+	if t.FinalFare != nil {
+		receipt.Route.Distance = t.FinalFare.Distance
+		receipt.Route.Duration = t.FinalFare.Duration
+	} else if fare.Route != nil && len(fare.Route.Routes) > 0 {
+		receipt.Route.Distance = fare.Route.Routes[0].Distance
+		receipt.Route.Duration = fare.Route.Routes[0].Duration
+	}
+
+	if tip := t.FindTip(userID); tip != nil && tip.Status == domain.TripTipStatusPaid {
+		receipt.TipInCents = float64(tip.AmountInCents)
+	}
+
+	for _, payment := range t.Payments {
+		if payment.UserID == userID {
+			receipt.Payment = payment
+			receipt.Currency = payment.Currency
+		}
+	}
+
+	// The taxes are part of the fare, the tips go to the driver untaxed
+	charged := breakdown.ChargedInCents()
+	receipt.TaxesInCents = charged * receiptTaxRate / (1 + receiptTaxRate)
+	receipt.TotalInCents = charged + receipt.TipInCents
+
+	return receipt, nil
+}
+
+// fareBreakdown itemizes the fare like it was priced: the final fare of the completed trips, the quote for
+// the pool riders
+func (s *service) fareBreakdown(fare *domain.RideFareModel, final *domain.FinalFareModel) *domain.FareBreakdown {
+	breakdown := s.itemizeFare(fare, final)
+	breakdown.PromoCode = fare.PromoCode
+	breakdown.PromoDiscountInCents = fare.DiscountInCents
+	breakdown.TotalPriceInCents = fare.TotalPriceInCents
+	if final != nil {
+		breakdown.PromoDiscountInCents = final.DiscountInCents
+		breakdown.TotalPriceInCents = final.TotalPriceInCents
+	}
+
+	return breakdown
+}
+
+// itemizeFare prices the route of the fare item by item, the route driven when the final fare is given.
+// The promo discount is left out.
+func (s *service) itemizeFare(fare *domain.RideFareModel, final *domain.FinalFareModel) *domain.FareBreakdown {
+	pricingCfg := tripTypes.GetDefaultPricingConfig()
+
+	breakdown := &domain.FareBreakdown{}
+	for _, base := range s.getBaseFares() {
+		if base.PackageSlug == fare.PackageSlug {
+			breakdown.BaseInCents = base.TotalPriceInCents
+		}
+	}
+
+	var distance, duration, intermediateStops float64
+	if fare.Route != nil && len(fare.Route.Routes) > 0 {
+		distance = fare.Route.Routes[0].Distance
+		duration = fare.Route.Routes[0].Duration
+		intermediateStops = float64(max(len(fare.Route.Routes[0].Legs)-1, 0))
+	}
+	waitMinutes := intermediateStops * pricingCfg.WaitingMinutesPerStop
+
+	if final != nil {
+		distance = final.Distance
+		duration = final.Duration
+		waitMinutes = final.WaitMinutes
+		breakdown.TollsInCents = final.TollsInCents
+	}
+
+	breakdown.DistanceInCents = distance * pricingCfg.PricePerUnitOfDistance
+	breakdown.TimeInCents = duration * pricingCfg.PricingPerMinute
+	breakdown.StopsInCents = intermediateStops * pricingCfg.PricePerStop
+	breakdown.WaitingInCents = waitMinutes * pricingCfg.PricingPerWaitingMinute
+
+	// Pool riders share the vehicle, they're priced on their quote
+	if final == nil && fare.PackageSlug == domain.PoolPackageSlug {
+		breakdown.PoolDiscountInCents = breakdown.PriceInCents() * pricingCfg.PoolDiscount
+	}
+
+	return breakdown
+}
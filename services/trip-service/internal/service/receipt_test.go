@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/internal/infrastructure/repository"
+	"ride-sharing/services/trip-service/internal/infrastructure/routing"
+	"ride-sharing/shared/proto/trip"
+	"ride-sharing/shared/types"
+)
+
+func receiptTestStops(waypoints int) []*types.Coordinate {
+	stops := []*types.Coordinate{{Latitude: 40.70, Longitude: -74.00}}
+	for i := range waypoints + 1 {
+		stops = append(stops, &types.Coordinate{Latitude: 40.71 + float64(i)*0.01, Longitude: -74.00})
+	}
+
+	return stops
+}
+
+func TestItemizeFareAddsUpToQuote(t *testing.T) {
+	s := NewService(repository.NewInMemRepository(), routing.NewHaversineProvider(30), nil)
+
+	for _, waypoints := range []int{0, 2} {
+		route, err := s.routeProvider.GetRoute(context.Background(), receiptTestStops(waypoints))
+		if err != nil {
+			t.Fatalf("GetRoute() error = %v", err)
+		}
+
+		for _, fare := range s.EstimaPkgsPriceWithRoute(route) {
+			fare.Route = route
+
+			got := s.itemizeFare(fare, nil).PriceInCents()
+			if math.Abs(got-fare.TotalPriceInCents) > 0.001 {
+				t.Errorf("%s with %d waypoints: items add up to %f, want %f",
+					fare.PackageSlug, waypoints, got, fare.TotalPriceInCents)
+			}
+		}
+	}
+}
+
+// newReceiptTrip creates a sedan trip quoted on the straight line, with a promo discount, assigned to "driver"
+func newReceiptTrip(t *testing.T) (*service, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	s := NewService(repository.NewInMemRepository(), routing.NewHaversineProvider(30), nil)
+
+	route, err := s.routeProvider.GetRoute(ctx, receiptTestStops(0))
+	if err != nil {
+		t.Fatalf("GetRoute() error = %v", err)
+	}
+
+	var fare *domain.RideFareModel
+	for _, f := range s.EstimaPkgsPriceWithRoute(route) {
+		if f.PackageSlug == "sedan" {
+			fare = f
+		}
+	}
+	fare.UserID = "rider"
+	fare.Route = route
+	fare.PromoCode = "WELCOME"
+	fare.OriginalPriceInCents = fare.TotalPriceInCents
+	fare.DiscountInCents = 100
+	fare.TotalPriceInCents -= fare.DiscountInCents
+
+	created, err := s.CreateTrip(ctx, fare, time.Time{})
+	if err != nil {
+		t.Fatalf("CreateTrip() error = %v", err)
+	}
+
+	created.Status = domain.TripStatusAssigned
+	created.Driver = &trip.TripDriver{Id: "driver"}
+	if err := s.repo.UpdateTrip(ctx, created); err != nil {
+		t.Fatalf("UpdateTrip() error = %v", err)
+	}
+
+	return s, created.ID.Hex()
+}
+
+func TestGetTripReceipt(t *testing.T) {
+	ctx := context.Background()
+	s, tripID := newReceiptTrip(t)
+
+	if _, err := s.GetTripReceipt(ctx, tripID, "rider"); !errors.Is(err, domain.ErrReceiptNotAvailable) {
+		t.Fatalf("GetTripReceipt() of an assigned trip error = %v, want %v", err, domain.ErrReceiptNotAvailable)
+	}
+
+	// The driver took a detour through a toll
+	completed, err := s.CompleteTrip(ctx, tripID, "driver", &domain.CompletedRoute{
+		Distance:     8_000,
+		Duration:     900,
+		WaitMinutes:  3,
+		TollsInCents: 250,
+	})
+	if err != nil {
+		t.Fatalf("CompleteTrip() error = %v", err)
+	}
+
+	defer func(rate float64) { receiptTaxRate = rate }(receiptTaxRate)
+	receiptTaxRate = 0.25
+
+	receipt, err := s.GetTripReceipt(ctx, tripID, "rider")
+	if err != nil {
+		t.Fatalf("GetTripReceipt() error = %v", err)
+	}
+
+	fare := receipt.Fare
+	if fare.TotalPriceInCents != completed.FinalFare.TotalPriceInCents {
+		t.Errorf("total = %f, want the final fare %f", fare.TotalPriceInCents, completed.FinalFare.TotalPriceInCents)
+	}
+	if got := fare.PriceInCents() - fare.PromoDiscountInCents; math.Abs(got-fare.TotalPriceInCents) > 0.001 {
+		t.Errorf("items minus the promo add up to %f, want %f", got, fare.TotalPriceInCents)
+	}
+	if fare.TollsInCents != 250 || fare.PromoCode != "WELCOME" || fare.PromoDiscountInCents != 100 {
+		t.Errorf("tolls = %f, promo = %s of %f, want 250 and WELCOME of 100",
+			fare.TollsInCents, fare.PromoCode, fare.PromoDiscountInCents)
+	}
+	if receipt.Route.Distance != 8_000 || receipt.Route.Duration != 900 {
+		t.Errorf("route = %fm in %fs, want the one driven", receipt.Route.Distance, receipt.Route.Duration)
+	}
+
+	// The taxes are included in the total
+	if math.Abs(receipt.TaxesInCents-fare.TotalPriceInCents/5) > 0.001 || receipt.TotalInCents != fare.TotalPriceInCents {
+		t.Errorf("taxes = %f of %f, want a fifth of %f",
+			receipt.TaxesInCents, receipt.TotalInCents, fare.TotalPriceInCents)
+	}
+
+	if _, err := s.GetTripReceipt(ctx, tripID, "stranger"); err == nil {
+		t.Error("GetTripReceipt() of another rider's trip succeeded")
+	}
+}
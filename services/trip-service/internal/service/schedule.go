@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/env"
+)
+
+var (
+	// scheduledTripLeadTime is how long before the pickup time the driver matching starts.
+	// Until then, scheduled rides can be cancelled free of charge.
+	scheduledTripLeadTime = env.GetDuration("SCHEDULED_TRIP_LEAD_TIME", 15*time.Minute)
+	// scheduledTripMaxAhead is how far in the future a ride can be booked
+	scheduledTripMaxAhead = env.GetDuration("SCHEDULED_TRIP_MAX_AHEAD", 7*24*time.Hour)
+	// scheduledTripReminderBefore is how long before the pickup time the rider gets a reminder
+	scheduledTripReminderBefore = env.GetDuration("SCHEDULED_TRIP_REMINDER_BEFORE", 30*time.Minute)
+)
+
+func validateSchedule(scheduledAt, now time.Time) error {
+	if scheduledAt.Before(now.Add(scheduledTripLeadTime)) {
+		return fmt.Errorf(
+			"%w: rides have to be scheduled at least %v ahead",
+			domain.ErrInvalidSchedule, scheduledTripLeadTime,
+		)
+	}
+
+	if scheduledAt.After(now.Add(scheduledTripMaxAhead)) {
+		return fmt.Errorf(
+			"%w: rides can be scheduled up to %v ahead",
+			domain.ErrInvalidSchedule, scheduledTripMaxAhead,
+		)
+	}
+
+	return nil
+}
+
+func (s *service) CancelTrip(
+	ctx context.Context,
+	tripID string,
+	userID string,
+) (*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if t.UserID != userID {
+		return nil, domain.ErrTripNotOwned
+	}
+
+	// Trips are cancelled free of charge until a driver is assigned, the scheduled ones included
+	if t.Status != domain.TripStatusScheduled && t.Status != domain.TripStatusPending {
+		return nil, fmt.Errorf("%w: the trip is %s", domain.ErrTripNotCancellable, t.Status)
+	}
+
+	t.Status = domain.TripStatusCancelled
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	// The riders get their promo codes back
+	for _, rider := range t.Riders {
+		if err := s.ReleasePromo(ctx, rider.RideFare); err != nil {
+			log.Printf("Failed to release the promo code of %s on trip %s: %v", rider.UserID, tripID, err)
+		}
+	}
+
+	return t, nil
+}
+
+func (s *service) ActivateDueScheduledTrips(
+	ctx context.Context,
+	now time.Time,
+) ([]*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scheduled, err := s.repo.ListTripsByStatus(ctx, domain.TripStatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled trips: %w", err)
+	}
+
+	due := make([]*domain.TripModel, 0)
+	for _, t := range scheduled {
+		if now.Before(t.ScheduledAt.Add(-scheduledTripLeadTime)) {
+			continue
+		}
+
+		t.Status = domain.TripStatusPending
+		if err := s.repo.UpdateTrip(ctx, t); err != nil {
+			return due, fmt.Errorf("failed to update trip: %w", err)
+		}
+
+		due = append(due, t)
+	}
+
+	return due, nil
+}
+
+func (s *service) DeactivateScheduledTrip(ctx context.Context, tripID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	// Cancelled or already matched meanwhile
+	if !t.IsScheduled() || t.Status != domain.TripStatusPending || t.Driver.GetId() != "" {
+		return nil
+	}
+
+	t.Status = domain.TripStatusScheduled
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) RemindScheduledTrips(
+	ctx context.Context,
+	now time.Time,
+) ([]*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scheduled, err := s.repo.ListTripsByStatus(ctx, domain.TripStatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled trips: %w", err)
+	}
+
+	reminders := make([]*domain.TripModel, 0)
+	for _, t := range scheduled {
+		if t.ReminderSent || now.Before(t.ScheduledAt.Add(-scheduledTripReminderBefore)) {
+			continue
+		}
+
+		t.ReminderSent = true
+		if err := s.repo.UpdateTrip(ctx, t); err != nil {
+			return reminders, fmt.Errorf("failed to update trip: %w", err)
+		}
+
+		reminders = append(reminders, t)
+	}
+
+	return reminders, nil
+}
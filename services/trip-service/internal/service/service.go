@@ -2,40 +2,74 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"slices"
+	"sync"
+	"time"
 
 	"ride-sharing/services/trip-service/internal/domain"
 	tripTypes "ride-sharing/services/trip-service/pkg/types"
 	"ride-sharing/shared/env"
+	"ride-sharing/shared/geojson"
 	"ride-sharing/shared/proto/trip"
 	"ride-sharing/shared/types"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// maxTripStops is the number of intermediate stops a rider can add to a trip
+var maxTripStops = env.GetInt("MAX_TRIP_STOPS", 3)
+
 type service struct {
-	repo domain.TripRepository
+	repo          domain.TripRepository
+	routeProvider domain.RouteProvider
+	// serviceAreas are where trips can start, stop and end. There is no restriction without areas.
+	serviceAreas geojson.ServiceAreas
+
+	// mu serializes trip status transitions, ex. a cancellation racing the scheduler
+	mu sync.Mutex
 }
 
-func NewService(repo domain.TripRepository) *service {
+func NewService(
+	repo domain.TripRepository,
+	routeProvider domain.RouteProvider,
+	serviceAreas geojson.ServiceAreas,
+) *service {
 	return &service{
-		repo: repo,
+		repo:          repo,
+		routeProvider: routeProvider,
+		serviceAreas:  serviceAreas,
 	}
 }
 
 func (s *service) CreateTrip(
 	ctx context.Context,
 	fare *domain.RideFareModel,
+	scheduledAt time.Time,
 ) (*domain.TripModel, error) {
 	t := &domain.TripModel{
 		ID:       primitive.NewObjectID(),
 		UserID:   fare.UserID,
-		Status:   "pending",
+		Status:   domain.TripStatusPending,
 		RideFare: fare,
 		Driver:   &trip.TripDriver{},
+		Riders:   []*domain.TripRider{{UserID: fare.UserID, RideFare: fare}},
+	}
+
+	if fare.PackageSlug == domain.PoolPackageSlug {
+		t.StopSequence = []*domain.TripStop{
+			{UserID: fare.UserID, Type: domain.TripStopPickup, Location: fare.Pickup},
+			{UserID: fare.UserID, Type: domain.TripStopDropoff, Location: fare.Destination},
+		}
+	}
+
+	if !scheduledAt.IsZero() {
+		if err := validateSchedule(scheduledAt, time.Now()); err != nil {
+			return nil, err
+		}
+
+		t.Status = domain.TripStatusScheduled
+		t.ScheduledAt = scheduledAt
 	}
 
 	return s.repo.CreateTrip(ctx, t)
@@ -45,43 +79,70 @@ func (s *service) GetRoute(
 	ctx context.Context,
 	pickup,
 	destination *types.Coordinate,
+	waypoints ...*types.Coordinate,
 ) (*tripTypes.OsrmAPIResponse, error) {
-	baseURL := env.GetString("OSRM_API", "http://router.project-osrm.org")
+	if len(waypoints) > maxTripStops {
+		return nil, fmt.Errorf("%w: up to %d intermediate stops are allowed", domain.ErrTooManyStops, maxTripStops)
+	}
 
-	url := fmt.Sprintf(
-		"%s/route/v1/driving/%f,%f;%f,%f?overview=full&geometries=geojson",
-		baseURL,
-		pickup.Longitude, pickup.Latitude,
-		destination.Longitude, destination.Latitude,
-	)
+	stops := make([]*types.Coordinate, 0, len(waypoints)+2)
+	stops = append(stops, pickup)
+	stops = append(stops, waypoints...)
+	stops = append(stops, destination)
 
-	res, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch route from OSRM API: %v", err)
+	if err := domain.ValidateStops(stops); err != nil {
+		return nil, err
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read the response: %v", err)
+	if err := s.checkServiceAreas(stops); err != nil {
+		return nil, err
 	}
 
-	routeRes := new(tripTypes.OsrmAPIResponse)
-	if err := json.Unmarshal(body, routeRes); err != nil {
-		return nil, fmt.Errorf("failed to parse route response: %v", err)
+	return s.routeProvider.GetRoute(ctx, stops)
+}
+
+func (s *service) checkServiceAreas(stops []*types.Coordinate) error {
+	if len(s.serviceAreas) == 0 {
+		return nil
 	}
 
-	return routeRes, nil
+	for i, stop := range stops {
+		if _, ok := s.serviceAreas.Find(stop.Latitude, stop.Longitude); ok {
+			continue
+		}
+
+		name := "pickup"
+		switch {
+		case i == len(stops)-1:
+			name = "destination"
+		case i > 0:
+			name = fmt.Sprintf("stop %d", i)
+		}
+
+		return fmt.Errorf(
+			"%w: the %s (%f, %f) is out of the operating areas",
+			domain.ErrOutsideServiceArea, name, stop.Latitude, stop.Longitude,
+		)
+	}
+
+	return nil
 }
 
 func (s *service) EstimaPkgsPriceWithRoute(
 	route *tripTypes.OsrmAPIResponse,
 ) []*domain.RideFareModel {
 	baseFares := s.getBaseFares()
-	estimatedFares := make([]*domain.RideFareModel, len(baseFares))
+	estimatedFares := make([]*domain.RideFareModel, 0, len(baseFares))
+
+	// Pooling riders only works for direct trips
+	multiStop := len(route.Routes) > 0 && len(route.Routes[0].Legs) > 1
+
+	for _, fare := range baseFares {
+		if multiStop && fare.PackageSlug == domain.PoolPackageSlug {
+			continue
+		}
 
-	for idx, fare := range baseFares {
-		estimatedFares[idx] = s.estimateFareRoute(fare, route)
+		estimatedFares = append(estimatedFares, s.estimateFareRoute(fare, route))
 	}
 
 	return estimatedFares
@@ -92,7 +153,12 @@ func (s *service) GenerateTripFares(
 	rideFares []*domain.RideFareModel,
 	userID string,
 	route *tripTypes.OsrmAPIResponse,
+	stops []*types.Coordinate,
 ) ([]*domain.RideFareModel, error) {
+	if err := domain.ValidateStops(stops); err != nil {
+		return nil, err
+	}
+
 	fares := make([]*domain.RideFareModel, len(rideFares))
 
 	for idx, fare := range rideFares {
@@ -102,6 +168,13 @@ func (s *service) GenerateTripFares(
 			TotalPriceInCents: fare.TotalPriceInCents,
 			PackageSlug:       fare.PackageSlug,
 			Route:             route,
+			Pickup:            stops[0],
+			Destination:       stops[len(stops)-1],
+			Waypoints:         stops[1 : len(stops)-1],
+
+			PromoCode:            fare.PromoCode,
+			OriginalPriceInCents: fare.OriginalPriceInCents,
+			DiscountInCents:      fare.DiscountInCents,
 		}
 
 		if err := s.repo.SaveRideFare(ctx, fare); err != nil {
@@ -136,6 +209,222 @@ func (s *service) ValidateFare(
 	return fare, nil
 }
 
+func (s *service) AssignDriver(
+	ctx context.Context,
+	tripID string,
+	driver *trip.TripDriver,
+) (*domain.TripModel, error) {
+	if driver.GetId() == "" {
+		return nil, fmt.Errorf("%w: no driver", domain.ErrTripNotAssignable)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if t.Status != domain.TripStatusPending {
+		if t.Driver.GetId() == driver.GetId() {
+			return nil, fmt.Errorf("%w: trip %s to driver %s", domain.ErrTripAlreadyAssigned, tripID, driver.GetId())
+		}
+
+		return nil, fmt.Errorf("%w: the trip is %s", domain.ErrTripNotAssignable, t.Status)
+	}
+
+	t.Status = domain.TripStatusAssigned
+	t.Driver = driver
+
+	// The payment sessions are created for the split as it is now
+	for _, participant := range t.SplitParticipants {
+		switch participant.Status {
+		case domain.SplitStatusInvited:
+			participant.Status = domain.SplitStatusExpired
+		case domain.SplitStatusAccepted:
+			participant.PaymentStatus = domain.SplitPaymentPending
+		}
+	}
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
+
+func (s *service) MarkDriverAssignedPublished(ctx context.Context, tripID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	t.DriverAssignedPublished = true
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return nil
+}
+
+func (s *service) ReachTripStop(
+	ctx context.Context,
+	tripID string,
+	driverID string,
+	stopIndex int,
+) (*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if t.Driver == nil || t.Driver.Id != driverID {
+		return nil, fmt.Errorf("%w: the trip isn't assigned to this driver", domain.ErrInvalidTripStop)
+	}
+
+	if t.Status != domain.TripStatusAssigned && t.Status != domain.TripStatusInProgress {
+		return nil, fmt.Errorf("%w: trip %s is %s", domain.ErrInvalidTripStop, tripID, t.Status)
+	}
+
+	// Pool trips report the stops of their sequence, starting with the first pickup, the other trips
+	// report the waypoints of the ride fare, the pickup being done before the first one
+	stops := len(t.RideFare.Waypoints)
+	if t.IsPool() {
+		stops = len(t.StopSequence)
+	}
+
+	// Stops have to be reached in order, reporting the same stop again is a no-op
+	switch {
+	case stopIndex < 0 || stopIndex >= stops:
+		return nil, fmt.Errorf("%w: the trip has no stop %d", domain.ErrInvalidTripStop, stopIndex)
+	case stopIndex < t.StopsReached:
+		return t, nil
+	case stopIndex > t.StopsReached:
+		return nil, fmt.Errorf("%w: stop %d has to be reached first", domain.ErrInvalidTripStop, t.StopsReached)
+	}
+
+	if t.IsPool() {
+		// The sequence is replaced rather than modified, it's read by the pool matching without the lock
+		sequence := slices.Clone(t.StopSequence)
+		reached := *sequence[stopIndex]
+		reached.Completed = true
+		sequence[stopIndex] = &reached
+		t.StopSequence = sequence
+	}
+
+	t.StopsReached = stopIndex + 1
+	// The rider is on board once the driver reached the pickup
+	if t.Status == domain.TripStatusAssigned {
+		t.Status = domain.TripStatusInProgress
+	}
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
+
+func (s *service) CompleteTrip(
+	ctx context.Context,
+	tripID string,
+	driverID string,
+	route *domain.CompletedRoute,
+) (*domain.TripModel, error) {
+	if route == nil {
+		route = &domain.CompletedRoute{}
+	}
+	if route.Distance < 0 || route.Duration < 0 || route.WaitMinutes < 0 || route.TollsInCents < 0 {
+		return nil, fmt.Errorf("%w: the completed route can't have negative values", domain.ErrTripNotCompletable)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if t.Driver == nil || t.Driver.Id != driverID {
+		return nil, fmt.Errorf("%w: the trip isn't assigned to this driver", domain.ErrTripNotCompletable)
+	}
+
+	switch t.Status {
+	case domain.TripStatusCompleted:
+		return nil, fmt.Errorf("%w: %s", domain.ErrTripAlreadyCompleted, tripID)
+	case domain.TripStatusAssigned, domain.TripStatusInProgress:
+	default:
+		return nil, fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotCompletable, tripID, t.Status)
+	}
+
+	if t.RideFare != nil && t.StopsReached < len(t.RideFare.Waypoints) {
+		return nil, fmt.Errorf("%w: stop %d wasn't reached", domain.ErrTripNotCompletable, t.StopsReached)
+	}
+
+	t.Status = domain.TripStatusCompleted
+	t.CompletedAt = time.Now()
+	// Pool riders pay the fares they were quoted
+	if t.RideFare != nil && !t.IsPool() {
+		t.FinalFare = s.priceCompletedRoute(t.RideFare, route)
+	}
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
+
+// priceCompletedRoute prices the route driven like the quote, with the waiting time and the tolls reported
+// by the driver instead of the expected waiting time at the stops
+func (s *service) priceCompletedRoute(
+	fare *domain.RideFareModel,
+	completed *domain.CompletedRoute,
+) *domain.FinalFareModel {
+	pricingCfg := tripTypes.GetDefaultPricingConfig()
+
+	var distance, duration, intermediateStops float64
+	if fare.Route != nil && len(fare.Route.Routes) > 0 {
+		distance = fare.Route.Routes[0].Distance
+		duration = fare.Route.Routes[0].Duration
+		intermediateStops = float64(max(len(fare.Route.Routes[0].Legs)-1, 0))
+	}
+
+	final := &domain.FinalFareModel{
+		Distance:     distance,
+		Duration:     duration,
+		WaitMinutes:  intermediateStops * pricingCfg.WaitingMinutesPerStop,
+		TollsInCents: completed.TollsInCents,
+	}
+	if completed.Distance > 0 {
+		final.Distance = completed.Distance
+	}
+	if completed.Duration > 0 {
+		final.Duration = completed.Duration
+	}
+	if completed.WaitMinutes > 0 {
+		final.WaitMinutes = completed.WaitMinutes
+	}
+
+	final.TotalPriceInCents = s.itemizeFare(fare, final).PriceInCents()
+
+	// The discount granted at the booking still applies, up to the final fare
+	if fare.DiscountInCents > 0 {
+		final.DiscountInCents = min(fare.DiscountInCents, final.TotalPriceInCents)
+		final.TotalPriceInCents -= final.DiscountInCents
+	}
+
+	return final
+}
+
 func (s *service) estimateFareRoute(
 	fare *domain.RideFareModel,
 	route *tripTypes.OsrmAPIResponse,
@@ -146,13 +435,23 @@ func (s *service) estimateFareRoute(
 
 	distanceKm := route.Routes[0].Distance
 	durationMin := route.Routes[0].Duration
+	// Every leg ends at a stop, the last one being the destination
+	intermediateStops := float64(max(len(route.Routes[0].Legs)-1, 0))
 
 	// distance
 	distanceFare := distanceKm * pricingCfg.PricePerUnitOfDistance
 	// time
 	timeFare := durationMin * pricingCfg.PricingPerMinute
+	// stops, including the time the driver waits at each of them
+	stopsFare := intermediateStops *
+		(pricingCfg.PricePerStop + pricingCfg.WaitingMinutesPerStop*pricingCfg.PricingPerWaitingMinute)
 	// car price
-	totalPrice := carPkgPrice + distanceFare + timeFare
+	totalPrice := carPkgPrice + distanceFare + timeFare + stopsFare
+
+	// pool riders share the vehicle, so they get a discount
+	if fare.PackageSlug == domain.PoolPackageSlug {
+		totalPrice *= 1 - pricingCfg.PoolDiscount
+	}
 
 	// return &domain.RideFareModel{
 	// 	TotalPriceInCents: totalPrice,
@@ -170,5 +469,6 @@ func (s *service) getBaseFares() []*domain.RideFareModel {
 		{PackageSlug: "sedan", TotalPriceInCents: 350},
 		{PackageSlug: "van", TotalPriceInCents: 400},
 		{PackageSlug: "luxury", TotalPriceInCents: 1_000},
+		{PackageSlug: domain.PoolPackageSlug, TotalPriceInCents: 350},
 	}
 }
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/internal/infrastructure/repository"
+	"ride-sharing/services/trip-service/internal/infrastructure/routing"
+	"ride-sharing/shared/proto/trip"
+)
+
+func TestAssignDriver(t *testing.T) {
+	ctx := context.Background()
+	s := NewService(repository.NewInMemRepository(), routing.NewHaversineProvider(30), nil)
+
+	fare := poolFare("rider", 40.70, -74.00, 40.75, -74.00)
+	fare.PackageSlug = "sedan"
+
+	newTrip := func(t *testing.T) string {
+		t.Helper()
+
+		created, err := s.CreateTrip(ctx, fare, time.Time{})
+		if err != nil {
+			t.Fatalf("CreateTrip() error = %v", err)
+		}
+
+		return created.ID.Hex()
+	}
+
+	t.Run("pending trip", func(t *testing.T) {
+		tripID := newTrip(t)
+
+		assigned, err := s.AssignDriver(ctx, tripID, &trip.TripDriver{Id: "driver"})
+		if err != nil {
+			t.Fatalf("AssignDriver() error = %v", err)
+		}
+		if assigned.Status != domain.TripStatusAssigned || assigned.Driver.GetId() != "driver" {
+			t.Errorf("trip is %s with driver %q, want assigned to driver", assigned.Status, assigned.Driver.GetId())
+		}
+
+		// Redelivered assignment
+		if _, err := s.AssignDriver(ctx, tripID, &trip.TripDriver{Id: "driver"}); !errors.Is(err, domain.ErrTripAlreadyAssigned) {
+			t.Errorf("AssignDriver() again error = %v, want %v", err, domain.ErrTripAlreadyAssigned)
+		}
+		if err := s.MarkDriverAssignedPublished(ctx, tripID); err != nil {
+			t.Fatalf("MarkDriverAssignedPublished() error = %v", err)
+		}
+		if published, _ := s.GetTrip(ctx, tripID, "rider"); !published.DriverAssignedPublished {
+			t.Error("assignment not marked as published")
+		}
+		if _, err := s.AssignDriver(ctx, tripID, &trip.TripDriver{Id: "other"}); !errors.Is(err, domain.ErrTripNotAssignable) {
+			t.Errorf("AssignDriver() to another driver error = %v, want %v", err, domain.ErrTripNotAssignable)
+		}
+	})
+
+	t.Run("cancelled trip", func(t *testing.T) {
+		tripID := newTrip(t)
+		if _, err := s.CancelTrip(ctx, tripID, "rider"); err != nil {
+			t.Fatalf("CancelTrip() error = %v", err)
+		}
+
+		if _, err := s.AssignDriver(ctx, tripID, &trip.TripDriver{Id: "driver"}); !errors.Is(err, domain.ErrTripNotAssignable) {
+			t.Errorf("AssignDriver() error = %v, want %v", err, domain.ErrTripNotAssignable)
+		}
+	})
+
+	t.Run("no driver", func(t *testing.T) {
+		if _, err := s.AssignDriver(ctx, newTrip(t), &trip.TripDriver{}); !errors.Is(err, domain.ErrTripNotAssignable) {
+			t.Errorf("AssignDriver() error = %v, want %v", err, domain.ErrTripNotAssignable)
+		}
+	})
+}
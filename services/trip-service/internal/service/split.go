@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/env"
+)
+
+// maxSplitParticipants is how many users, the owner aside, can be invited to split a fare
+var maxSplitParticipants = env.GetInt("TRIP_SPLIT_MAX_PARTICIPANTS", 4)
+
+func (s *service) InviteToSplit(
+	ctx context.Context,
+	tripID, ownerID string,
+	inviteeIDs []string,
+) (*domain.TripModel, []string, error) {
+	if len(inviteeIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: no invitee", domain.ErrInvalidSplit)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if t.UserID != ownerID {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrTripNotOwned, ownerID)
+	}
+	if err := checkSplittable(t); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		invited []string
+		added   int
+	)
+	for _, inviteeID := range inviteeIDs {
+		if inviteeID == "" || inviteeID == ownerID {
+			return nil, nil, fmt.Errorf("%w: %q can't be invited", domain.ErrInvalidSplit, inviteeID)
+		}
+		if slices.Contains(invited, inviteeID) {
+			continue
+		}
+
+		participant := t.FindSplitParticipant(inviteeID)
+		if participant == nil {
+			added++
+		} else if participant.Status == domain.SplitStatusAccepted {
+			continue
+		}
+
+		invited = append(invited, inviteeID)
+	}
+
+	if len(t.SplitParticipants)+added > maxSplitParticipants {
+		return nil, nil, fmt.Errorf("%w: at most %d users can split a fare with the owner", domain.ErrInvalidSplit, maxSplitParticipants)
+	}
+
+	for _, inviteeID := range invited {
+		participant := t.FindSplitParticipant(inviteeID)
+		if participant == nil {
+			t.SplitParticipants = append(t.SplitParticipants, &domain.SplitParticipant{
+				UserID: inviteeID,
+				Status: domain.SplitStatusInvited,
+			})
+			continue
+		}
+
+		// Invited again, they may have declined by mistake
+		participant.Status = domain.SplitStatusInvited
+	}
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, invited, nil
+}
+
+func (s *service) RespondToSplit(
+	ctx context.Context,
+	tripID, userID string,
+	accept bool,
+) (*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	participant := t.FindSplitParticipant(userID)
+	if participant == nil {
+		return nil, fmt.Errorf("%w: %s on trip %s", domain.ErrSplitInviteNotFound, userID, tripID)
+	}
+
+	status := domain.SplitStatusDeclined
+	if accept {
+		status = domain.SplitStatusAccepted
+	}
+
+	switch participant.Status {
+	case status:
+		// Answering twice is a no-op
+		return t, nil
+	case domain.SplitStatusInvited, domain.SplitStatusAccepted, domain.SplitStatusDeclined:
+	default:
+		return nil, fmt.Errorf("%w: the invitation of %s is %s", domain.ErrSplitInviteNotFound, userID, participant.Status)
+	}
+
+	if err := checkSplittable(t); err != nil {
+		return nil, err
+	}
+
+	participant.Status = status
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
+
+func (s *service) RecordSplitPaymentFailure(
+	ctx context.Context,
+	tripID, userID string,
+) (*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	participant := t.FindSplitParticipant(userID)
+	if participant == nil || participant.Status != domain.SplitStatusAccepted {
+		return t, nil
+	}
+
+	// An older failure can't undo the payment
+	if participant.PaymentStatus != domain.SplitPaymentPending {
+		return t, nil
+	}
+
+	participant.PaymentStatus = domain.SplitPaymentFailed
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
+
+// checkSplittable returns ErrTripNotSplittable once the payment sessions are created with the driver
+// assignment, and for the pool trips whose riders pay their own fares
+func checkSplittable(t *domain.TripModel) error {
+	if t.IsPool() {
+		return fmt.Errorf("%w: pool riders pay their own fares", domain.ErrTripNotSplittable)
+	}
+
+	if t.Status != domain.TripStatusScheduled && t.Status != domain.TripStatusPending {
+		return fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotSplittable, t.ID.Hex(), t.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/shared/env"
+)
+
+var (
+	// tipWindow is how long after the completion the riders can tip
+	tipWindow = env.GetDuration("TRIP_TIP_WINDOW", 24*time.Hour)
+	// tipMaxPercent caps the tips, fixed amounts included, to a percentage of the fare of the rider
+	tipMaxPercent = env.GetFloat("TRIP_TIP_MAX_PERCENT", 100)
+)
+
+func (s *service) TipTrip(
+	ctx context.Context,
+	tripID, userID string,
+	amountInCents int64,
+	percent float64,
+) (*domain.TripModel, *domain.TripTip, error) {
+	switch {
+	case (amountInCents == 0) == (percent == 0):
+		return nil, nil, fmt.Errorf("%w: either an amount or a percentage is required", domain.ErrInvalidTip)
+	case amountInCents < 0 || percent < 0:
+		return nil, nil, fmt.Errorf("%w: the tip can't be negative", domain.ErrInvalidTip)
+	case percent > tipMaxPercent:
+		return nil, nil, fmt.Errorf("%w: the tip can't be more than %v%% of the fare", domain.ErrInvalidTip, tipMaxPercent)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	if !t.HasRider(userID) {
+		return nil, nil, fmt.Errorf("%w: %s", domain.ErrTripNotOwned, userID)
+	}
+	if t.Status != domain.TripStatusCompleted || t.Driver == nil {
+		return nil, nil, fmt.Errorf("%w: trip %s is %s", domain.ErrTripNotTippable, tripID, t.Status)
+	}
+	if time.Since(t.CompletedAt) > tipWindow {
+		return nil, nil, fmt.Errorf("%w: trip %s was completed more than %s ago", domain.ErrTripNotTippable, tripID, tipWindow)
+	}
+	existing := t.FindTip(userID)
+
+	fare := t.RiderFare(userID)
+	if percent > 0 {
+		amountInCents = int64(math.Round(fare * percent / 100))
+		if amountInCents == 0 {
+			return nil, nil, fmt.Errorf("%w: %v%% of the fare is less than a cent", domain.ErrInvalidTip, percent)
+		}
+	}
+	if maxTip := int64(math.Round(fare * tipMaxPercent / 100)); amountInCents > maxTip {
+		return nil, nil, fmt.Errorf("%w: the tip can't be more than %d", domain.ErrInvalidTip, maxTip)
+	}
+
+	switch {
+	case existing == nil:
+	// Retries after the tip event failed to publish get the pending tip back, to publish it again
+	case existing.Status == domain.TripTipStatusPending && existing.AmountInCents == amountInCents:
+		return t, existing, nil
+	case existing.Status != domain.TripTipStatusFailed:
+		return nil, nil, fmt.Errorf("%w: %s already tipped on trip %s", domain.ErrAlreadyTipped, userID, tripID)
+	}
+
+	tip := &domain.TripTip{
+		UserID:        userID,
+		AmountInCents: amountInCents,
+		Status:        domain.TripTipStatusPending,
+		CreatedAt:     time.Now(),
+	}
+	// The tip which failed is replaced, the rider can tip again
+	t.Tips = slices.DeleteFunc(t.Tips, func(other *domain.TripTip) bool {
+		return other.UserID == userID
+	})
+	t.Tips = append(t.Tips, tip)
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, tip, nil
+}
+
+func (s *service) SettleTip(ctx context.Context, tripID, userID string, paid bool) (*domain.TripModel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.repo.GetTripByID(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip: %w", err)
+	}
+
+	tip := t.FindTip(userID)
+	if tip == nil {
+		return nil, fmt.Errorf("%w: %s didn't tip on trip %s", domain.ErrTripNotTippable, userID, tripID)
+	}
+
+	// A paid tip stays paid, the failures reported along with the success come from expired duplicates
+	if tip.Status == domain.TripTipStatusPaid {
+		return t, nil
+	}
+
+	tip.Status = domain.TripTipStatusFailed
+	if paid {
+		tip.Status = domain.TripTipStatusPaid
+	}
+
+	if err := s.repo.UpdateTrip(ctx, t); err != nil {
+		return nil, fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ride-sharing/services/trip-service/internal/domain"
+	"ride-sharing/services/trip-service/internal/infrastructure/repository"
+	"ride-sharing/services/trip-service/internal/infrastructure/routing"
+	"ride-sharing/shared/proto/trip"
+)
+
+// newCompletedTrip creates a trip with a fare of $20, completed by "driver" at completedAt
+func newCompletedTrip(t *testing.T, completedAt time.Time) (*service, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	s := NewService(repository.NewInMemRepository(), routing.NewHaversineProvider(30), nil)
+
+	created, err := s.CreateTrip(ctx, &domain.RideFareModel{
+		UserID:            "rider",
+		PackageSlug:       "sedan",
+		TotalPriceInCents: 2_000,
+	}, time.Time{})
+	if err != nil {
+		t.Fatalf("CreateTrip() error = %v", err)
+	}
+
+	created.Status = domain.TripStatusCompleted
+	created.Driver = &trip.TripDriver{Id: "driver"}
+	created.CompletedAt = completedAt
+	if err := s.repo.UpdateTrip(ctx, created); err != nil {
+		t.Fatalf("UpdateTrip() error = %v", err)
+	}
+
+	return s, created.ID.Hex()
+}
+
+func TestTipTrip(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		completedAt   time.Time
+		amountInCents int64
+		percent       float64
+		wantErr       error
+		wantAmount    int64
+	}{
+		{
+			name:          "fixed amount",
+			amountInCents: 300,
+			wantAmount:    300,
+		},
+		{
+			name:       "percentage of the fare",
+			percent:    15,
+			wantAmount: 300,
+		},
+		{
+			name:          "amount up to the cap",
+			amountInCents: 2_000,
+			wantAmount:    2_000,
+		},
+		{
+			name:          "amount over the cap",
+			amountInCents: 2_001,
+			wantErr:       domain.ErrInvalidTip,
+		},
+		{
+			name:    "percentage over the cap",
+			percent: 101,
+			wantErr: domain.ErrInvalidTip,
+		},
+		{
+			name:          "both an amount and a percentage",
+			amountInCents: 300,
+			percent:       15,
+			wantErr:       domain.ErrInvalidTip,
+		},
+		{
+			name:    "neither an amount nor a percentage",
+			wantErr: domain.ErrInvalidTip,
+		},
+		{
+			name:          "negative amount",
+			amountInCents: -100,
+			wantErr:       domain.ErrInvalidTip,
+		},
+		{
+			name:    "less than a cent",
+			percent: 0.01,
+			wantErr: domain.ErrInvalidTip,
+		},
+		{
+			name:          "after the tip window",
+			completedAt:   time.Now().Add(-tipWindow - time.Minute),
+			amountInCents: 300,
+			wantErr:       domain.ErrTripNotTippable,
+		},
+		{
+			name:          "not a rider of the trip",
+			userID:        "other",
+			amountInCents: 300,
+			wantErr:       domain.ErrTripNotOwned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completedAt := tt.completedAt
+			if completedAt.IsZero() {
+				completedAt = time.Now()
+			}
+			userID := tt.userID
+			if userID == "" {
+				userID = "rider"
+			}
+
+			s, tripID := newCompletedTrip(t, completedAt)
+
+			_, tip, err := s.TipTrip(context.Background(), tripID, userID, tt.amountInCents, tt.percent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TipTrip() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if tip.AmountInCents != tt.wantAmount || tip.Status != domain.TripTipStatusPending {
+				t.Errorf("tip = %d cents %s, want %d cents pending", tip.AmountInCents, tip.Status, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func tipAndGet(s *service, tripID string, amountInCents int64) (*domain.TripTip, error) {
+	_, tip, err := s.TipTrip(context.Background(), tripID, "rider", amountInCents, 0)
+	return tip, err
+}
+
+func TestTipTripOnce(t *testing.T) {
+	s, tripID := newCompletedTrip(t, time.Now())
+	ctx := context.Background()
+
+	first, err := tipAndGet(s, tripID, 300)
+	if err != nil {
+		t.Fatalf("TipTrip() error = %v", err)
+	}
+
+	// The retry of a tip whose event wasn't published gets it back
+	retried, err := tipAndGet(s, tripID, 300)
+	if err != nil || !retried.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("TipTrip() retried = %v, want the pending tip back", err)
+	}
+	if _, err := tipAndGet(s, tripID, 500); !errors.Is(err, domain.ErrAlreadyTipped) {
+		t.Errorf("TipTrip() of another amount error = %v, want %v", err, domain.ErrAlreadyTipped)
+	}
+
+	if _, err := s.SettleTip(ctx, tripID, "rider", true); err != nil {
+		t.Fatalf("SettleTip() error = %v", err)
+	}
+	// A failure reported by an expired duplicate doesn't undo the payment
+	settled, err := s.SettleTip(ctx, tripID, "rider", false)
+	if err != nil {
+		t.Fatalf("SettleTip() error = %v", err)
+	}
+	if tip := settled.FindTip("rider"); tip.Status != domain.TripTipStatusPaid {
+		t.Errorf("tip status = %s, want %s", tip.Status, domain.TripTipStatusPaid)
+	}
+
+	if _, err := tipAndGet(s, tripID, 300); !errors.Is(err, domain.ErrAlreadyTipped) {
+		t.Errorf("TipTrip() after the payment error = %v, want %v", err, domain.ErrAlreadyTipped)
+	}
+}
+
+func TestTipTripAfterFailure(t *testing.T) {
+	s, tripID := newCompletedTrip(t, time.Now())
+	ctx := context.Background()
+
+	first, err := tipAndGet(s, tripID, 300)
+	if err != nil {
+		t.Fatalf("TipTrip() error = %v", err)
+	}
+	if _, err := s.SettleTip(ctx, tripID, "rider", false); err != nil {
+		t.Fatalf("SettleTip() error = %v", err)
+	}
+
+	// The rider tips again, another amount included, once the tip failed
+	again, err := tipAndGet(s, tripID, 500)
+	if err != nil {
+		t.Fatalf("TipTrip() after the failure error = %v", err)
+	}
+	if again.AmountInCents != 500 || again.Status != domain.TripTipStatusPending || again.CreatedAt.Before(first.CreatedAt) {
+		t.Errorf("tip = %d cents %s, want a new pending tip of 500", again.AmountInCents, again.Status)
+	}
+
+	trip, err := s.GetTrip(ctx, tripID, "rider")
+	if err != nil {
+		t.Fatalf("GetTrip() error = %v", err)
+	}
+	if len(trip.Tips) != 1 || trip.FindTip("rider").AmountInCents != 500 {
+		t.Errorf("%d tips, want the failed one replaced", len(trip.Tips))
+	}
+}
@@ -1,18 +1,24 @@
 package types
 
-import pb "ride-sharing/shared/proto/trip"
-
-type OsrmAPIResponse struct {
-	Routes []struct {
-		Distance float64 `json:"distance"`
-		Duration float64 `json:"duration"`
-		Geometry struct {
-			Coordinates [][]float64 `json:"coordinates"`
-		} `json:"geometry"`
-	} `json:"routes"`
-}
+import (
+	"ride-sharing/shared/osrm"
+	pb "ride-sharing/shared/proto/trip"
+)
+
+// OsrmAPIResponse is the OSRM response, along with its conversion to the trip route
+type OsrmAPIResponse osrm.Response
+
+type (
+	OsrmRoute    = osrm.Route
+	OsrmLeg      = osrm.Leg
+	OsrmGeometry = osrm.Geometry
+)
 
 func (o *OsrmAPIResponse) ToProto() *pb.Route {
+	if len(o.Routes) == 0 {
+		return &pb.Route{}
+	}
+
 	route := o.Routes[0]
 	geometry := route.Geometry.Coordinates
 	coordinates := make([]*pb.Coordinate, len(geometry))
@@ -23,6 +29,14 @@ func (o *OsrmAPIResponse) ToProto() *pb.Route {
 		}
 	}
 
+	legs := make([]*pb.RouteLeg, len(route.Legs))
+	for i, leg := range route.Legs {
+		legs[i] = &pb.RouteLeg{
+			Distance: leg.Distance,
+			Duration: leg.Duration,
+		}
+	}
+
 	return &pb.Route{
 		Geometry: []*pb.Geometry{
 			{
@@ -31,17 +45,29 @@ func (o *OsrmAPIResponse) ToProto() *pb.Route {
 		},
 		Distance: route.Distance,
 		Duration: route.Duration,
+		Legs:     legs,
 	}
 }
 
 type PricingConfig struct {
 	PricePerUnitOfDistance float64
 	PricingPerMinute       float64
+	// Flat fee charged for every intermediate stop
+	PricePerStop float64
+	// Expected time the driver waits at every intermediate stop
+	WaitingMinutesPerStop   float64
+	PricingPerWaitingMinute float64
+	// PoolDiscount is the fraction taken off the fare of pool riders
+	PoolDiscount float64
 }
 
 func GetDefaultPricingConfig() *PricingConfig {
 	return &PricingConfig{
-		PricePerUnitOfDistance: 1.5,
-		PricingPerMinute:       0.25,
+		PricePerUnitOfDistance:  1.5,
+		PricingPerMinute:        0.25,
+		PricePerStop:            100,
+		WaitingMinutesPerStop:   3,
+		PricingPerWaitingMinute: 0.25,
+		PoolDiscount:            0.3,
 	}
 }
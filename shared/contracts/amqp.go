@@ -13,19 +13,45 @@ const (
 	TripEventDriverAssigned      = "trip.event.driver_assigned"
 	TripEventNoDriversFound      = "trip.event.no_drivers_found"
 	TripEventDriverNotInterested = "trip.event.driver_not_interested"
+	TripEventScheduled           = "trip.event.scheduled"
+	TripEventScheduledReminder   = "trip.event.scheduled_reminder"
+	TripEventCancelled           = "trip.event.cancelled"
+	TripEventPoolRiderAdded      = "trip.event.pool_rider_added"
+	TripEventCompleted           = "trip.event.completed"
+	TripEventRated               = "trip.event.rated"
+	TripEventTipAdded            = "trip.event.tip_added"
+	// TripEventSplitInvited goes to the invitee, TripEventSplitUpdated to the owner once they answered
+	TripEventSplitInvited = "trip.event.split_invited"
+	TripEventSplitUpdated = "trip.event.split_updated"
+	// TripEventDriverRejected is published instead of TripEventDriverAssigned when the trip can't take the driver
+	// who accepted it anymore, ex. it was cancelled meanwhile
+	TripEventDriverRejected = "trip.event.driver_rejected"
 
 	// Driver commands (driver.cmd.*)
-	DriverCmdTripRequest = "driver.cmd.trip_request"
-	DriverCmdTripAccept  = "driver.cmd.trip_accept"
-	DriverCmdTripDecline = "driver.cmd.trip_decline"
-	DriverCmdLocation    = "driver.cmd.location"
-	DriverCmdRegister    = "driver.cmd.register"
+	DriverCmdTripRequest  = "driver.cmd.trip_request"
+	DriverCmdTripAccept   = "driver.cmd.trip_accept"
+	DriverCmdTripDecline  = "driver.cmd.trip_decline"
+	DriverCmdLocation     = "driver.cmd.location"
+	DriverCmdRegister     = "driver.cmd.register"
+	DriverCmdStopReached  = "driver.cmd.stop_reached"
+	DriverCmdStatus       = "driver.cmd.status"
+	DriverCmdTripComplete = "driver.cmd.trip_complete"
+	// DriverCmdTripAssign asks the trip service to assign the trip to the driver who accepted it
+	DriverCmdTripAssign = "driver.cmd.trip_assign"
+
+	// Rider commands (rider.cmd.*)
+	RiderCmdSplitAccept  = "rider.cmd.split_accept"
+	RiderCmdSplitDecline = "rider.cmd.split_decline"
 
 	// Payment events (payment.event.*)
 	PaymentEventSessionCreated = "payment.event.session_created"
+	PaymentEventAuthorized     = "payment.event.authorized"
 	PaymentEventSuccess        = "payment.event.success"
 	PaymentEventFailed         = "payment.event.failed"
 	PaymentEventCancelled      = "payment.event.cancelled"
+	PaymentEventRefunded       = "payment.event.refunded"
+	// PaymentEventTipReceived tells the driver a tip was charged, the other payment events go to the riders
+	PaymentEventTipReceived = "payment.event.tip_received"
 
 	// Payment commands (payment.cmd.*)
 	PaymentCmdCreateSession = "payment.cmd.create_session"
@@ -0,0 +1,151 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Area is a named operating area, made of one or more polygons
+type Area struct {
+	Name     string
+	Polygons []Polygon
+}
+
+// Polygon is an outer ring followed by its holes, each ring being [lat, lng] points
+type Polygon [][][]float64
+
+// ServiceAreas are the areas the service operates in
+type ServiceAreas []*Area
+
+// LoadServiceAreas reads the Polygon and MultiPolygon features of the file
+func LoadServiceAreas(path string) (ServiceAreas, error) {
+	fc, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var areas ServiceAreas
+	for i, f := range fc.Features {
+		if f.Geometry == nil {
+			return nil, fmt.Errorf("%s: %s has no geometry", path, f.name(i))
+		}
+
+		var polygons [][][][]float64
+		switch f.Geometry.Type {
+		case "Polygon":
+			var polygon [][][]float64
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygon); err != nil {
+				return nil, fmt.Errorf("%s: invalid coordinates of %s: %w", path, f.name(i), err)
+			}
+			polygons = [][][][]float64{polygon}
+		case "MultiPolygon":
+			if err := json.Unmarshal(f.Geometry.Coordinates, &polygons); err != nil {
+				return nil, fmt.Errorf("%s: invalid coordinates of %s: %w", path, f.name(i), err)
+			}
+		default:
+			return nil, fmt.Errorf("%s: %s is a %s, a Polygon or MultiPolygon is expected", path, f.name(i), f.Geometry.Type)
+		}
+
+		area := &Area{Name: f.name(i)}
+		for _, rings := range polygons {
+			polygon, err := toPolygon(rings)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", path, area.Name, err)
+			}
+			area.Polygons = append(area.Polygons, polygon)
+		}
+
+		if len(area.Polygons) == 0 {
+			return nil, fmt.Errorf("%s: %s has no polygons", path, area.Name)
+		}
+
+		areas = append(areas, area)
+	}
+
+	if len(areas) == 0 {
+		return nil, fmt.Errorf("%s has no service areas", path)
+	}
+
+	return areas, nil
+}
+
+func toPolygon(rings [][][]float64) (Polygon, error) {
+	if len(rings) == 0 {
+		return nil, fmt.Errorf("polygon without rings")
+	}
+
+	polygon := make(Polygon, 0, len(rings))
+	for _, positions := range rings {
+		ring, err := toLatLng(positions)
+		if err != nil {
+			return nil, err
+		}
+
+		// Rings are closed, the first and last positions are the same
+		if len(ring) < 4 {
+			return nil, fmt.Errorf("rings need at least 4 positions, got %d", len(ring))
+		}
+		first, last := ring[0], ring[len(ring)-1]
+		if first[0] != last[0] || first[1] != last[1] {
+			return nil, fmt.Errorf("ring starting at %v is not closed", positions[0])
+		}
+
+		polygon = append(polygon, ring)
+	}
+
+	return polygon, nil
+}
+
+// Find returns the area containing the point, if any
+func (s ServiceAreas) Find(lat, lng float64) (*Area, bool) {
+	for _, area := range s {
+		if area.Contains(lat, lng) {
+			return area, true
+		}
+	}
+
+	return nil, false
+}
+
+// Contains tells whether the point is within the area
+func (a *Area) Contains(lat, lng float64) bool {
+	for _, polygon := range a.Polygons {
+		if polygon.Contains(lat, lng) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Contains tells whether the point is within the outer ring and out of the holes
+func (p Polygon) Contains(lat, lng float64) bool {
+	if !ringContains(p[0], lat, lng) {
+		return false
+	}
+
+	for _, hole := range p[1:] {
+		if ringContains(hole, lat, lng) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ringContains casts a ray from the point and counts the edges it crosses.
+// The areas are city sized, planar coordinates are good enough.
+func ringContains(ring [][]float64, lat, lng float64) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		latI, lngI := ring[i][0], ring[i][1]
+		latJ, lngJ := ring[j][0], ring[j][1]
+
+		if (latI > lat) != (latJ > lat) &&
+			lng < (lngJ-lngI)*(lat-latI)/(latJ-latI)+lngI {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
@@ -0,0 +1,75 @@
+// Package geojson loads the simulation routes and the service areas from GeoJSON files
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ride-sharing/shared/util"
+)
+
+// FeatureCollection is the root object of the GeoJSON files, see RFC 7946
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	Features []*Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string         `json:"type"`
+	Properties map[string]any `json:"properties"`
+	Geometry   *Geometry      `json:"geometry"`
+}
+
+// Geometry coordinates depend on its type, they're decoded when the geometry is used
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// LoadFile reads a FeatureCollection
+func LoadFile(path string) (*FeatureCollection, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	fc := new(FeatureCollection)
+	if err := json.Unmarshal(data, fc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if fc.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("%s is a %q, a FeatureCollection is expected", path, fc.Type)
+	}
+
+	return fc, nil
+}
+
+// name returns the name property of the feature, or its index in the collection
+func (f *Feature) name(index int) string {
+	if name, ok := f.Properties["name"].(string); ok && name != "" {
+		return name
+	}
+
+	return fmt.Sprintf("feature #%d", index)
+}
+
+// toLatLng converts GeoJSON [lng, lat] positions to [lat, lng] points, the order used in the services
+func toLatLng(positions [][]float64) ([][]float64, error) {
+	points := make([][]float64, 0, len(positions))
+	for _, p := range positions {
+		if len(p) < 2 {
+			return nil, fmt.Errorf("position %v has no longitude and latitude", p)
+		}
+
+		lng, lat := p[0], p[1]
+		if !util.IsValidCoordinate(lat, lng) {
+			return nil, fmt.Errorf("position %v is out of range", p)
+		}
+
+		points = append(points, []float64{lat, lng})
+	}
+
+	return points, nil
+}
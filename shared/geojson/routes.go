@@ -0,0 +1,43 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LoadRoutes reads the LineString features of the file as routes of [lat, lng] points
+func LoadRoutes(path string) ([][][]float64, error) {
+	fc, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var routes [][][]float64
+	for i, f := range fc.Features {
+		if f.Geometry == nil || f.Geometry.Type != "LineString" {
+			return nil, fmt.Errorf("%s: %s is not a LineString", path, f.name(i))
+		}
+
+		var positions [][]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &positions); err != nil {
+			return nil, fmt.Errorf("%s: invalid coordinates of %s: %w", path, f.name(i), err)
+		}
+
+		route, err := toLatLng(positions)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, f.name(i), err)
+		}
+
+		if len(route) < 2 {
+			return nil, fmt.Errorf("%s: %s needs at least 2 positions", path, f.name(i))
+		}
+
+		routes = append(routes, route)
+	}
+
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("%s has no routes", path)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,114 @@
+package messaging
+
+import (
+	pbd "ride-sharing/shared/proto/driver"
+	pb "ride-sharing/shared/proto/trip"
+)
+
+// TripEventData is the payload of the trip.event.* messages
+type TripEventData struct {
+	Trip *pb.Trip `json:"trip"`
+}
+
+// DriverTripRequestData is the payload of driver.cmd.trip_request, an offer of the trip to the driver
+type DriverTripRequestData struct {
+	Trip   *pb.Trip    `json:"trip"`
+	Driver *pbd.Driver `json:"driver"`
+}
+
+// DriverTripResponseData is the payload of driver.cmd.trip_accept and driver.cmd.trip_decline.
+// The message owner is the driver answering the offer.
+type DriverTripResponseData struct {
+	TripID  string      `json:"tripID"`
+	RiderID string      `json:"riderID"`
+	Driver  *pbd.Driver `json:"driver"`
+}
+
+// DriverLocationData is the payload of driver.cmd.location
+type DriverLocationData struct {
+	Driver *pbd.Driver `json:"driver"`
+	// RiderID is the rider the driver is assigned to, if any
+	RiderID string `json:"riderID,omitempty"`
+}
+
+// TripRatedData is the payload of trip.event.rated
+type TripRatedData struct {
+	Rating *pb.Rating `json:"rating"`
+	// Summary is the rating summary of the ratee, including the new rating
+	Summary *pb.RatingSummary `json:"summary"`
+}
+
+// TripTipData is the payload of trip.event.tip_added, the message owner is the rider tipping
+type TripTipData struct {
+	TripID      string      `json:"tripID"`
+	DriverID    string      `json:"driverID"`
+	PackageSlug string      `json:"packageSlug"`
+	Tip         *pb.TripTip `json:"tip"`
+}
+
+// RiderSplitResponseData is the payload of rider.cmd.split_accept and rider.cmd.split_decline.
+// The message owner is the invitee answering.
+type RiderSplitResponseData struct {
+	TripID string `json:"tripID"`
+}
+
+// PaymentSessionCreatedData is the payload of payment.event.session_created, the message owner is the rider to pay
+type PaymentSessionCreatedData struct {
+	TripID string `json:"tripID"`
+	// UserID is the rider whose share the session pays, when it's not the message owner
+	UserID string `json:"userID,omitempty"`
+	// Kind is fare, tip or balance
+	Kind      string `json:"kind,omitempty"`
+	SessionID string `json:"sessionID"`
+	// Amount is in the currency units, as shown to the rider
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+	// CheckoutURL is where the rider pays, when the provider hosts the checkout page
+	CheckoutURL string `json:"checkoutURL,omitempty"`
+}
+
+// Payment kinds, as reported by the payment events
+const (
+	PaymentKindFare    = "fare"
+	PaymentKindTip     = "tip"
+	PaymentKindBalance = "balance"
+)
+
+// PaymentEventData is the payload of the other payment.event.* messages
+type PaymentEventData struct {
+	TripID   string `json:"tripID"`
+	UserID   string `json:"userID"`
+	DriverID string `json:"driverID"`
+	// PayerID is who's charged when it's not the rider, the owner covering the share of a split participant
+	PayerID string `json:"payerID,omitempty"`
+	// SessionID is the provider session reporting the event, the stale sessions of a payment are ignored
+	SessionID string `json:"sessionID,omitempty"`
+	// Kind is fare, tip or balance. Tips, and the part of the final fare over the hold, are charged on their own
+	// session once the trip completed.
+	Kind string `json:"kind,omitempty"`
+	// AmountInCents is the total charged, including the tip
+	AmountInCents int64 `json:"amountInCents"`
+	TipInCents    int64 `json:"tipInCents,omitempty"`
+	// Method is how the payer paid, ex. card, when the provider tells
+	Method string `json:"method,omitempty"`
+	// HoldInCents is what the session holds when the payment is captured once the trip completes
+	HoldInCents int64  `json:"holdInCents,omitempty"`
+	Currency    string `json:"currency"`
+}
+
+// PaymentRefundedData is the payload of payment.event.refunded, the message owner is the refunded rider
+type PaymentRefundedData struct {
+	TripID   string `json:"tripID"`
+	UserID   string `json:"userID"`
+	DriverID string `json:"driverID"`
+	RefundID string `json:"refundID"`
+	Reason   string `json:"reason"`
+	// AmountInCents is what this refund gave back
+	AmountInCents int64 `json:"amountInCents"`
+	// PaidInCents and RefundedInCents are the totals of the payment, refunds included, the refunds of its
+	// balance too. BalanceInCents is the part of the final fare over the hold, paid on its own session.
+	PaidInCents     int64  `json:"paidInCents"`
+	BalanceInCents  int64  `json:"balanceInCents,omitempty"`
+	RefundedInCents int64  `json:"refundedInCents"`
+	Currency        string `json:"currency"`
+}
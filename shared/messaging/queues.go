@@ -0,0 +1,77 @@
+package messaging
+
+import "ride-sharing/shared/contracts"
+
+// TripExchange is the topic exchange every event and command is published to
+const TripExchange = "trip"
+
+// Queue names
+const (
+	FindAvailableDriversQueue       = "find_available_drivers"
+	DriverCmdTripRequestQueue       = "driver_cmd_trip_request"
+	DriverTripResponseQueue         = "driver_trip_response"
+	NotifyDriverNoDriversFoundQueue = "notify_driver_no_drivers_found"
+	NotifyDriverAssignQueue         = "notify_driver_assign"
+	AssignTripDriverQueue           = "assign_trip_driver"
+	NotifyDriverLocationQueue       = "notify_driver_location"
+	NotifyTripCompletedQueue        = "notify_trip_completed"
+	DriverTripCompletedQueue        = "driver_trip_completed"
+	DriverRatingsQueue              = "driver_ratings"
+	DriverEarningsQueue             = "driver_earnings"
+	CreatePaymentSessionQueue       = "create_payment_session"
+	NotifyPaymentStatusQueue        = "notify_payment_status"
+	PaymentStatusUpdateQueue        = "payment_status_update"
+	PaymentTripUpdateQueue          = "payment_trip_update"
+	NotifyDriverTipQueue            = "notify_driver_tip"
+	NotifySplitQueue                = "notify_split"
+	SplitResponseQueue              = "split_response"
+)
+
+// queueBindings maps every queue to the routing keys it receives
+var queueBindings = map[string][]string{
+	FindAvailableDriversQueue: {
+		contracts.TripEventCreated,
+		contracts.TripEventCancelled,
+		contracts.TripEventDriverRejected,
+	},
+	DriverCmdTripRequestQueue:       {contracts.DriverCmdTripRequest},
+	DriverTripResponseQueue:         {contracts.DriverCmdTripAccept, contracts.DriverCmdTripDecline},
+	NotifyDriverNoDriversFoundQueue: {contracts.TripEventNoDriversFound},
+	NotifyDriverAssignQueue:         {contracts.TripEventDriverAssigned},
+	AssignTripDriverQueue:           {contracts.DriverCmdTripAssign},
+	NotifyDriverLocationQueue:       {contracts.DriverCmdLocation},
+	NotifyTripCompletedQueue:        {contracts.TripEventCompleted},
+	DriverTripCompletedQueue:        {contracts.TripEventCompleted},
+	DriverRatingsQueue:              {contracts.TripEventRated},
+	DriverEarningsQueue:             {contracts.TripEventCompleted, contracts.PaymentEventSuccess},
+	CreatePaymentSessionQueue: {
+		contracts.TripEventDriverAssigned,
+		contracts.TripEventPoolRiderAdded,
+		contracts.TripEventCancelled,
+		contracts.TripEventCompleted,
+		contracts.TripEventTipAdded,
+	},
+	NotifyPaymentStatusQueue: {
+		contracts.PaymentEventSessionCreated,
+		contracts.PaymentEventAuthorized,
+		contracts.PaymentEventSuccess,
+		contracts.PaymentEventFailed,
+		contracts.PaymentEventCancelled,
+		contracts.PaymentEventRefunded,
+	},
+	PaymentStatusUpdateQueue: {
+		contracts.PaymentEventAuthorized,
+		contracts.PaymentEventSuccess,
+		contracts.PaymentEventFailed,
+		contracts.PaymentEventCancelled,
+	},
+	PaymentTripUpdateQueue: {
+		contracts.PaymentEventSuccess,
+		contracts.PaymentEventFailed,
+		contracts.PaymentEventCancelled,
+		contracts.PaymentEventRefunded,
+	},
+	NotifyDriverTipQueue: {contracts.PaymentEventTipReceived},
+	NotifySplitQueue:     {contracts.TripEventSplitInvited, contracts.TripEventSplitUpdated},
+	SplitResponseQueue:   {contracts.RiderCmdSplitAccept, contracts.RiderCmdSplitDecline},
+}
@@ -4,11 +4,13 @@ package messaging
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
 	"time"
 
+	"ride-sharing/shared/contracts"
 	"ride-sharing/shared/retry"
 	"ride-sharing/shared/util"
 
@@ -50,11 +52,17 @@ func (r *RabbitMQ) GetChannel() *amqp.Channel {
 	return r.ch
 }
 
+// Publish sends the message to the trip exchange, queues receive it based on their bindings
 func (r *RabbitMQ) Publish(
 	ctx context.Context,
 	routingKey string,
-	message string,
+	message contracts.AmqpMessage,
 ) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the message: %v", err)
+	}
+
 	retryCfg := retry.Config{
 		MaxRetries:  3,
 		InitialWait: 100 * time.Millisecond,
@@ -76,13 +84,13 @@ func (r *RabbitMQ) Publish(
 
 		err := ch.PublishWithContext(
 			ctx,
-			"",         // exchange
-			routingKey, // routing key
-			false,      // mandatory
-			false,      // immediate
+			TripExchange, // exchange
+			routingKey,   // routing key
+			false,        // mandatory
+			false,        // immediate
 			amqp.Publishing{
-				ContentType:  "text/plain",
-				Body:         []byte(message),
+				ContentType:  "application/json",
+				Body:         body,
 				DeliveryMode: amqp.Persistent,
 			},
 		)
@@ -331,18 +339,52 @@ func (r *RabbitMQ) monitorChannel() {
 }
 
 func (r *RabbitMQ) setupExchanges() error {
-	return nil
+	return r.ch.ExchangeDeclare(
+		TripExchange, // name
+		"topic",      // type
+		true,         // durable
+		false,        // auto-deleted
+		false,        // internal
+		false,        // no-wait
+		nil,          // arguments
+	)
 }
 
 func (r *RabbitMQ) setupQueues() error {
+	for queueName, routingKeys := range queueBindings {
+		if err := r.declareAndBindQueue(queueName, routingKeys); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *RabbitMQ) declareAndBindQueue(queueName string, routingKeys []string) error {
 	_, err := r.ch.QueueDeclare(
-		"hello", // name
-		true,    // durable
-		false,   // delete when unused
-		false,   // exclusive
-		false,   // no-wait
-		nil,     // arguments
+		queueName, // name
+		true,      // durable
+		false,     // delete when unused
+		false,     // exclusive
+		false,     // no-wait
+		nil,       // arguments
 	)
+	if err != nil {
+		return fmt.Errorf("failed to declare queue %s: %v", queueName, err)
+	}
 
-	return err
+	for _, routingKey := range routingKeys {
+		err := r.ch.QueueBind(
+			queueName,    // queue name
+			routingKey,   // routing key
+			TripExchange, // exchange
+			false,        // no-wait
+			nil,          // arguments
+		)
+		if err != nil {
+			return fmt.Errorf("failed to bind queue %s to %s: %v", queueName, routingKey, err)
+		}
+	}
+
+	return nil
 }
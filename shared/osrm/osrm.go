@@ -0,0 +1,120 @@
+package osrm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"ride-sharing/shared/types"
+)
+
+// Limit the body we're willing to read, full geometries of long routes are big but not that big
+const maxResponseBytes = 10 << 20
+
+// ErrNoRoute is returned when OSRM can't connect the points
+var ErrNoRoute = errors.New("OSRM found no route")
+
+type Response struct {
+	// Code is "Ok" on success, otherwise an OSRM error code such as "NoRoute"
+	Code    string  `json:"code"`
+	Message string  `json:"message,omitempty"`
+	Routes  []Route `json:"routes"`
+}
+
+type Route struct {
+	Distance float64  `json:"distance"`
+	Duration float64  `json:"duration"`
+	Geometry Geometry `json:"geometry"`
+	// Legs has one entry per segment between consecutive stops
+	Legs []Leg `json:"legs"`
+}
+
+type Leg struct {
+	Distance float64 `json:"distance"`
+	Duration float64 `json:"duration"`
+}
+
+type Geometry struct {
+	// Coordinates are GeoJSON positions, i.e. [longitude, latitude]
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+// Client fetches the driving routes from an OSRM server
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Route returns the route going through the stops in order, with its full geometry
+func (c *Client) Route(ctx context.Context, stops []*types.Coordinate) (*Response, error) {
+	// A multi-stop query returns one leg per pair of consecutive stops
+	url := fmt.Sprintf(
+		"%s/route/v1/driving/%s?overview=full&geometries=geojson",
+		c.baseURL,
+		StopsKey(stops),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the OSRM request: %w", err)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch route from OSRM API: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response: %w", err)
+	}
+
+	routeRes := new(Response)
+	if err := json.Unmarshal(body, routeRes); err != nil {
+		// OSRM answers with a JSON body even on errors, anything else is unexpected
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("OSRM API responded with status %d", res.StatusCode)
+		}
+		return nil, fmt.Errorf("failed to parse route response: %w", err)
+	}
+
+	switch routeRes.Code {
+	case "Ok":
+	case "NoRoute", "NoSegment":
+		return nil, fmt.Errorf("%w: %s", ErrNoRoute, routeRes.Message)
+	default:
+		return nil, fmt.Errorf(
+			"OSRM API responded with status %d (%s): %s",
+			res.StatusCode, routeRes.Code, routeRes.Message,
+		)
+	}
+
+	if len(routeRes.Routes) == 0 {
+		return nil, ErrNoRoute
+	}
+
+	return routeRes, nil
+}
+
+// StopsKey formats the stops as "lon,lat;lon,lat;...", the OSRM request path
+func StopsKey(stops []*types.Coordinate) string {
+	points := make([]string, len(stops))
+	for idx, stop := range stops {
+		points[idx] = fmt.Sprintf("%f,%f", stop.Longitude, stop.Latitude)
+	}
+
+	return strings.Join(points, ";")
+}
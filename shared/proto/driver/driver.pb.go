@@ -9,6 +9,7 @@ package driver
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,1495 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type GetDriverShiftRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverShiftRequest) Reset() {
+	*x = GetDriverShiftRequest{}
+	mi := &file_driver_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverShiftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverShiftRequest) ProtoMessage() {}
+
+func (x *GetDriverShiftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverShiftRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverShiftRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetDriverShiftRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+// ShiftSummary is the working time of the driver on the current day. Online time counts every status
+// but offline and break, on trip time the en_route and on_trip statuses.
+type ShiftSummary struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Status   string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// When the driver first went online today
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=startedAt,proto3" json:"startedAt,omitempty"`
+	OnlineSeconds int64                  `protobuf:"varint,4,opt,name=onlineSeconds,proto3" json:"onlineSeconds,omitempty"`
+	OnTripSeconds int64                  `protobuf:"varint,5,opt,name=onTripSeconds,proto3" json:"onTripSeconds,omitempty"`
+	// Online time since the last break long enough to count
+	ContinuousSeconds int64 `protobuf:"varint,6,opt,name=continuousSeconds,proto3" json:"continuousSeconds,omitempty"`
+	// Limits of the continuous and daily online time, 0 when disabled
+	MaxContinuousSeconds int64 `protobuf:"varint,7,opt,name=maxContinuousSeconds,proto3" json:"maxContinuousSeconds,omitempty"`
+	MaxDailySeconds      int64 `protobuf:"varint,8,opt,name=maxDailySeconds,proto3" json:"maxDailySeconds,omitempty"`
+	// Set while the driver is on a required break, it can't go available before
+	BreakUntil *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=breakUntil,proto3" json:"breakUntil,omitempty"`
+	// continuous_limit or daily_limit
+	BreakReason   string `protobuf:"bytes,10,opt,name=breakReason,proto3" json:"breakReason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShiftSummary) Reset() {
+	*x = ShiftSummary{}
+	mi := &file_driver_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShiftSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShiftSummary) ProtoMessage() {}
+
+func (x *ShiftSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShiftSummary.ProtoReflect.Descriptor instead.
+func (*ShiftSummary) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ShiftSummary) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *ShiftSummary) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ShiftSummary) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *ShiftSummary) GetOnlineSeconds() int64 {
+	if x != nil {
+		return x.OnlineSeconds
+	}
+	return 0
+}
+
+func (x *ShiftSummary) GetOnTripSeconds() int64 {
+	if x != nil {
+		return x.OnTripSeconds
+	}
+	return 0
+}
+
+func (x *ShiftSummary) GetContinuousSeconds() int64 {
+	if x != nil {
+		return x.ContinuousSeconds
+	}
+	return 0
+}
+
+func (x *ShiftSummary) GetMaxContinuousSeconds() int64 {
+	if x != nil {
+		return x.MaxContinuousSeconds
+	}
+	return 0
+}
+
+func (x *ShiftSummary) GetMaxDailySeconds() int64 {
+	if x != nil {
+		return x.MaxDailySeconds
+	}
+	return 0
+}
+
+func (x *ShiftSummary) GetBreakUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.BreakUntil
+	}
+	return nil
+}
+
+func (x *ShiftSummary) GetBreakReason() string {
+	if x != nil {
+		return x.BreakReason
+	}
+	return ""
+}
+
+type GetDriverStatementRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// daily or weekly, weeks start on Monday
+	Period string `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	// Any time within the period, now when unset
+	Date          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverStatementRequest) Reset() {
+	*x = GetDriverStatementRequest{}
+	mi := &file_driver_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverStatementRequest) ProtoMessage() {}
+
+func (x *GetDriverStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverStatementRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverStatementRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetDriverStatementRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *GetDriverStatementRequest) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *GetDriverStatementRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+type StatementLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransactionID string                 `protobuf:"bytes,1,opt,name=transactionID,proto3" json:"transactionID,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	TripID        string                 `protobuf:"bytes,3,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// earning, fee, tip or adjustment
+	Kind string `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	// What the driver earns, negative for what it owes
+	AmountInCents int64  `protobuf:"varint,5,opt,name=amountInCents,proto3" json:"amountInCents,omitempty"`
+	Description   string `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatementLine) Reset() {
+	*x = StatementLine{}
+	mi := &file_driver_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatementLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatementLine) ProtoMessage() {}
+
+func (x *StatementLine) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatementLine.ProtoReflect.Descriptor instead.
+func (*StatementLine) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StatementLine) GetTransactionID() string {
+	if x != nil {
+		return x.TransactionID
+	}
+	return ""
+}
+
+func (x *StatementLine) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *StatementLine) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *StatementLine) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *StatementLine) GetAmountInCents() int64 {
+	if x != nil {
+		return x.AmountInCents
+	}
+	return 0
+}
+
+func (x *StatementLine) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type DriverStatement struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	DriverID           string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Period             string                 `protobuf:"bytes,2,opt,name=period,proto3" json:"period,omitempty"`
+	From               *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To                 *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	EarningsInCents    int64                  `protobuf:"varint,5,opt,name=earningsInCents,proto3" json:"earningsInCents,omitempty"`
+	FeesInCents        int64                  `protobuf:"varint,6,opt,name=feesInCents,proto3" json:"feesInCents,omitempty"`
+	TipsInCents        int64                  `protobuf:"varint,7,opt,name=tipsInCents,proto3" json:"tipsInCents,omitempty"`
+	AdjustmentsInCents int64                  `protobuf:"varint,8,opt,name=adjustmentsInCents,proto3" json:"adjustmentsInCents,omitempty"`
+	NetInCents         int64                  `protobuf:"varint,9,opt,name=netInCents,proto3" json:"netInCents,omitempty"`
+	Lines              []*StatementLine       `protobuf:"bytes,10,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DriverStatement) Reset() {
+	*x = DriverStatement{}
+	mi := &file_driver_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverStatement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverStatement) ProtoMessage() {}
+
+func (x *DriverStatement) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverStatement.ProtoReflect.Descriptor instead.
+func (*DriverStatement) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DriverStatement) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *DriverStatement) GetPeriod() string {
+	if x != nil {
+		return x.Period
+	}
+	return ""
+}
+
+func (x *DriverStatement) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *DriverStatement) GetEarningsInCents() int64 {
+	if x != nil {
+		return x.EarningsInCents
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetFeesInCents() int64 {
+	if x != nil {
+		return x.FeesInCents
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetTipsInCents() int64 {
+	if x != nil {
+		return x.TipsInCents
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetAdjustmentsInCents() int64 {
+	if x != nil {
+		return x.AdjustmentsInCents
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetNetInCents() int64 {
+	if x != nil {
+		return x.NetInCents
+	}
+	return 0
+}
+
+func (x *DriverStatement) GetLines() []*StatementLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type ExportDriverStatementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Csv           []byte                 `protobuf:"bytes,2,opt,name=csv,proto3" json:"csv,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportDriverStatementResponse) Reset() {
+	*x = ExportDriverStatementResponse{}
+	mi := &file_driver_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportDriverStatementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportDriverStatementResponse) ProtoMessage() {}
+
+func (x *ExportDriverStatementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportDriverStatementResponse.ProtoReflect.Descriptor instead.
+func (*ExportDriverStatementResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ExportDriverStatementResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportDriverStatementResponse) GetCsv() []byte {
+	if x != nil {
+		return x.Csv
+	}
+	return nil
+}
+
+type AdjustDriverEarningsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// Positive to pay the driver more, negative to take back
+	AmountInCents int64  `protobuf:"varint,2,opt,name=amountInCents,proto3" json:"amountInCents,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Makes retries safe, the same reference is only applied once
+	ReferenceID   string `protobuf:"bytes,4,opt,name=referenceID,proto3" json:"referenceID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustDriverEarningsRequest) Reset() {
+	*x = AdjustDriverEarningsRequest{}
+	mi := &file_driver_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustDriverEarningsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustDriverEarningsRequest) ProtoMessage() {}
+
+func (x *AdjustDriverEarningsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustDriverEarningsRequest.ProtoReflect.Descriptor instead.
+func (*AdjustDriverEarningsRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AdjustDriverEarningsRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *AdjustDriverEarningsRequest) GetAmountInCents() int64 {
+	if x != nil {
+		return x.AmountInCents
+	}
+	return 0
+}
+
+func (x *AdjustDriverEarningsRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AdjustDriverEarningsRequest) GetReferenceID() string {
+	if x != nil {
+		return x.ReferenceID
+	}
+	return ""
+}
+
+type Vehicle struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Make         string                 `protobuf:"bytes,1,opt,name=make,proto3" json:"make,omitempty"`
+	Model        string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Color        string                 `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	Plate        string                 `protobuf:"bytes,4,opt,name=plate,proto3" json:"plate,omitempty"`
+	SeatCapacity int32                  `protobuf:"varint,5,opt,name=seatCapacity,proto3" json:"seatCapacity,omitempty"`
+	// The packages the vehicle qualifies for, ex. sedan, suv
+	PackageSlugs  []string `protobuf:"bytes,6,rep,name=packageSlugs,proto3" json:"packageSlugs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	mi := &file_driver_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Vehicle) GetMake() string {
+	if x != nil {
+		return x.Make
+	}
+	return ""
+}
+
+func (x *Vehicle) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Vehicle) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Vehicle) GetPlate() string {
+	if x != nil {
+		return x.Plate
+	}
+	return ""
+}
+
+func (x *Vehicle) GetSeatCapacity() int32 {
+	if x != nil {
+		return x.SeatCapacity
+	}
+	return 0
+}
+
+func (x *Vehicle) GetPackageSlugs() []string {
+	if x != nil {
+		return x.PackageSlugs
+	}
+	return nil
+}
+
+type DriverProfile struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DriverID       string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProfilePicture string                 `protobuf:"bytes,3,opt,name=profilePicture,proto3" json:"profilePicture,omitempty"`
+	LicenseNumber  string                 `protobuf:"bytes,4,opt,name=licenseNumber,proto3" json:"licenseNumber,omitempty"`
+	Vehicle        *Vehicle               `protobuf:"bytes,5,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	// Kept up to date from the ratings of the riders
+	Rating        float64 `protobuf:"fixed64,6,opt,name=rating,proto3" json:"rating,omitempty"`
+	RatingCount   int32   `protobuf:"varint,7,opt,name=ratingCount,proto3" json:"ratingCount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverProfile) Reset() {
+	*x = DriverProfile{}
+	mi := &file_driver_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverProfile) ProtoMessage() {}
+
+func (x *DriverProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverProfile.ProtoReflect.Descriptor instead.
+func (*DriverProfile) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DriverProfile) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *DriverProfile) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DriverProfile) GetProfilePicture() string {
+	if x != nil {
+		return x.ProfilePicture
+	}
+	return ""
+}
+
+func (x *DriverProfile) GetLicenseNumber() string {
+	if x != nil {
+		return x.LicenseNumber
+	}
+	return ""
+}
+
+func (x *DriverProfile) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *DriverProfile) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *DriverProfile) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+type DriverProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profile       *DriverProfile         `protobuf:"bytes,1,opt,name=profile,proto3" json:"profile,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DriverProfileRequest) Reset() {
+	*x = DriverProfileRequest{}
+	mi := &file_driver_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DriverProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DriverProfileRequest) ProtoMessage() {}
+
+func (x *DriverProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DriverProfileRequest.ProtoReflect.Descriptor instead.
+func (*DriverProfileRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DriverProfileRequest) GetProfile() *DriverProfile {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+type GetDriverProfileRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverProfileRequest) Reset() {
+	*x = GetDriverProfileRequest{}
+	mi := &file_driver_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverProfileRequest) ProtoMessage() {}
+
+func (x *GetDriverProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverProfileRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverProfileRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetDriverProfileRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type DeleteDriverProfileResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDriverProfileResponse) Reset() {
+	*x = DeleteDriverProfileResponse{}
+	mi := &file_driver_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDriverProfileResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDriverProfileResponse) ProtoMessage() {}
+
+func (x *DeleteDriverProfileResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDriverProfileResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDriverProfileResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{11}
+}
+
+type ListDriverProfilesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDriverProfilesRequest) Reset() {
+	*x = ListDriverProfilesRequest{}
+	mi := &file_driver_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDriverProfilesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDriverProfilesRequest) ProtoMessage() {}
+
+func (x *ListDriverProfilesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDriverProfilesRequest.ProtoReflect.Descriptor instead.
+func (*ListDriverProfilesRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{12}
+}
+
+type ListDriverProfilesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Profiles      []*DriverProfile       `protobuf:"bytes,1,rep,name=profiles,proto3" json:"profiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDriverProfilesResponse) Reset() {
+	*x = ListDriverProfilesResponse{}
+	mi := &file_driver_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDriverProfilesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDriverProfilesResponse) ProtoMessage() {}
+
+func (x *ListDriverProfilesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDriverProfilesResponse.ProtoReflect.Descriptor instead.
+func (*ListDriverProfilesResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListDriverProfilesResponse) GetProfiles() []*DriverProfile {
+	if x != nil {
+		return x.Profiles
+	}
+	return nil
+}
+
+// Field numbers match RegisterDriverRequest, which UnregisterDriver used to take
+type UnregisterDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterDriverRequest) Reset() {
+	*x = UnregisterDriverRequest{}
+	mi := &file_driver_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterDriverRequest) ProtoMessage() {}
+
+func (x *UnregisterDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterDriverRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterDriverRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UnregisterDriverRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type GetDriverRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverRequest) Reset() {
+	*x = GetDriverRequest{}
+	mi := &file_driver_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverRequest) ProtoMessage() {}
+
+func (x *GetDriverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetDriverRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type GetDriverResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Driver        *Driver                `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverResponse) Reset() {
+	*x = GetDriverResponse{}
+	mi := &file_driver_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverResponse) ProtoMessage() {}
+
+func (x *GetDriverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverResponse.ProtoReflect.Descriptor instead.
+func (*GetDriverResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetDriverResponse) GetDriver() *Driver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+type ListNearbyDriversRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Location     *Location              `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	RadiusMeters float64                `protobuf:"fixed64,2,opt,name=radiusMeters,proto3" json:"radiusMeters,omitempty"`
+	// Empty for every package
+	PackageSlug   string `protobuf:"bytes,3,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
+	Limit         int32  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNearbyDriversRequest) Reset() {
+	*x = ListNearbyDriversRequest{}
+	mi := &file_driver_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNearbyDriversRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNearbyDriversRequest) ProtoMessage() {}
+
+func (x *ListNearbyDriversRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNearbyDriversRequest.ProtoReflect.Descriptor instead.
+func (*ListNearbyDriversRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListNearbyDriversRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *ListNearbyDriversRequest) GetRadiusMeters() float64 {
+	if x != nil {
+		return x.RadiusMeters
+	}
+	return 0
+}
+
+func (x *ListNearbyDriversRequest) GetPackageSlug() string {
+	if x != nil {
+		return x.PackageSlug
+	}
+	return ""
+}
+
+func (x *ListNearbyDriversRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type NearbyDriver struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Driver *Driver                `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
+	// Meters to the location
+	Distance float64 `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Seconds to reach the location
+	Eta           float64 `protobuf:"fixed64,3,opt,name=eta,proto3" json:"eta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NearbyDriver) Reset() {
+	*x = NearbyDriver{}
+	mi := &file_driver_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NearbyDriver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NearbyDriver) ProtoMessage() {}
+
+func (x *NearbyDriver) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NearbyDriver.ProtoReflect.Descriptor instead.
+func (*NearbyDriver) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *NearbyDriver) GetDriver() *Driver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+func (x *NearbyDriver) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *NearbyDriver) GetEta() float64 {
+	if x != nil {
+		return x.Eta
+	}
+	return 0
+}
+
+type ListNearbyDriversResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Closest first
+	Drivers       []*NearbyDriver `protobuf:"bytes,1,rep,name=drivers,proto3" json:"drivers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNearbyDriversResponse) Reset() {
+	*x = ListNearbyDriversResponse{}
+	mi := &file_driver_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNearbyDriversResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNearbyDriversResponse) ProtoMessage() {}
+
+func (x *ListNearbyDriversResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNearbyDriversResponse.ProtoReflect.Descriptor instead.
+func (*ListNearbyDriversResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListNearbyDriversResponse) GetDrivers() []*NearbyDriver {
+	if x != nil {
+		return x.Drivers
+	}
+	return nil
+}
+
+type WatchDriverLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDriverLocationRequest) Reset() {
+	*x = WatchDriverLocationRequest{}
+	mi := &file_driver_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDriverLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDriverLocationRequest) ProtoMessage() {}
+
+func (x *WatchDriverLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDriverLocationRequest.ProtoReflect.Descriptor instead.
+func (*WatchDriverLocationRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchDriverLocationRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type UpdateDriverLocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	Location      *Location              `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDriverLocationRequest) Reset() {
+	*x = UpdateDriverLocationRequest{}
+	mi := &file_driver_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDriverLocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDriverLocationRequest) ProtoMessage() {}
+
+func (x *UpdateDriverLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDriverLocationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDriverLocationRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateDriverLocationRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *UpdateDriverLocationRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_driver_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *HeartbeatRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+type HeartbeatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Shift         *ShiftSummary          `protobuf:"bytes,2,opt,name=shift,proto3" json:"shift,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_driver_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *HeartbeatResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HeartbeatResponse) GetShift() *ShiftSummary {
+	if x != nil {
+		return x.Shift
+	}
+	return nil
+}
+
+type SetDriverStatusRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DriverID string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// available, break or on_trip, the other statuses are driven by the trip workflow
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDriverStatusRequest) Reset() {
+	*x = SetDriverStatusRequest{}
+	mi := &file_driver_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDriverStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDriverStatusRequest) ProtoMessage() {}
+
+func (x *SetDriverStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_driver_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDriverStatusRequest.ProtoReflect.Descriptor instead.
+func (*SetDriverStatusRequest) Descriptor() ([]byte, []int) {
+	return file_driver_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetDriverStatusRequest) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *SetDriverStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 type RegisterDriverRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DriverID      string                 `protobuf:"bytes,1,opt,name=driverID,proto3" json:"driverID,omitempty"`
@@ -31,7 +1521,7 @@ type RegisterDriverRequest struct {
 
 func (x *RegisterDriverRequest) Reset() {
 	*x = RegisterDriverRequest{}
-	mi := &file_driver_proto_msgTypes[0]
+	mi := &file_driver_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -43,7 +1533,7 @@ func (x *RegisterDriverRequest) String() string {
 func (*RegisterDriverRequest) ProtoMessage() {}
 
 func (x *RegisterDriverRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_driver_proto_msgTypes[0]
+	mi := &file_driver_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,7 +1546,7 @@ func (x *RegisterDriverRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterDriverRequest.ProtoReflect.Descriptor instead.
 func (*RegisterDriverRequest) Descriptor() ([]byte, []int) {
-	return file_driver_proto_rawDescGZIP(), []int{0}
+	return file_driver_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *RegisterDriverRequest) GetDriverID() string {
@@ -82,7 +1572,7 @@ type RegisterDriverResponse struct {
 
 func (x *RegisterDriverResponse) Reset() {
 	*x = RegisterDriverResponse{}
-	mi := &file_driver_proto_msgTypes[1]
+	mi := &file_driver_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -94,7 +1584,7 @@ func (x *RegisterDriverResponse) String() string {
 func (*RegisterDriverResponse) ProtoMessage() {}
 
 func (x *RegisterDriverResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_driver_proto_msgTypes[1]
+	mi := &file_driver_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -107,7 +1597,7 @@ func (x *RegisterDriverResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RegisterDriverResponse.ProtoReflect.Descriptor instead.
 func (*RegisterDriverResponse) Descriptor() ([]byte, []int) {
-	return file_driver_proto_rawDescGZIP(), []int{1}
+	return file_driver_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *RegisterDriverResponse) GetDriver() *Driver {
@@ -126,13 +1616,21 @@ type Driver struct {
 	Geohash        string                 `protobuf:"bytes,5,opt,name=geohash,proto3" json:"geohash,omitempty"`
 	PackageSlug    string                 `protobuf:"bytes,6,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
 	Location       *Location              `protobuf:"bytes,7,opt,name=location,proto3" json:"location,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	SeatCapacity   int32                  `protobuf:"varint,8,opt,name=seatCapacity,proto3" json:"seatCapacity,omitempty"`
+	// offline, available, offered, en_route, on_trip or break
+	Status string `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	// Unset for the drivers without a profile
+	Vehicle *Vehicle `protobuf:"bytes,10,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	// Rolling average of the ratings given by the riders, unrated drivers have no ratings yet
+	Rating        float64 `protobuf:"fixed64,11,opt,name=rating,proto3" json:"rating,omitempty"`
+	RatingCount   int32   `protobuf:"varint,12,opt,name=ratingCount,proto3" json:"ratingCount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Driver) Reset() {
 	*x = Driver{}
-	mi := &file_driver_proto_msgTypes[2]
+	mi := &file_driver_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -144,7 +1642,7 @@ func (x *Driver) String() string {
 func (*Driver) ProtoMessage() {}
 
 func (x *Driver) ProtoReflect() protoreflect.Message {
-	mi := &file_driver_proto_msgTypes[2]
+	mi := &file_driver_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -157,7 +1655,7 @@ func (x *Driver) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Driver.ProtoReflect.Descriptor instead.
 func (*Driver) Descriptor() ([]byte, []int) {
-	return file_driver_proto_rawDescGZIP(), []int{2}
+	return file_driver_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *Driver) GetId() string {
@@ -209,6 +1707,41 @@ func (x *Driver) GetLocation() *Location {
 	return nil
 }
 
+func (x *Driver) GetSeatCapacity() int32 {
+	if x != nil {
+		return x.SeatCapacity
+	}
+	return 0
+}
+
+func (x *Driver) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Driver) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *Driver) GetRating() float64 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *Driver) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
 type Location struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
@@ -219,7 +1752,7 @@ type Location struct {
 
 func (x *Location) Reset() {
 	*x = Location{}
-	mi := &file_driver_proto_msgTypes[3]
+	mi := &file_driver_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -231,7 +1764,7 @@ func (x *Location) String() string {
 func (*Location) ProtoMessage() {}
 
 func (x *Location) ProtoReflect() protoreflect.Message {
-	mi := &file_driver_proto_msgTypes[3]
+	mi := &file_driver_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -244,7 +1777,7 @@ func (x *Location) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Location.ProtoReflect.Descriptor instead.
 func (*Location) Descriptor() ([]byte, []int) {
-	return file_driver_proto_rawDescGZIP(), []int{3}
+	return file_driver_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *Location) GetLatitude() float64 {
@@ -265,12 +1798,114 @@ var File_driver_proto protoreflect.FileDescriptor
 
 const file_driver_proto_rawDesc = "" +
 	"\n" +
-	"\fdriver.proto\x12\x06driver\"U\n" +
+	"\fdriver.proto\x12\x06driver\x1a\x1fgoogle/protobuf/timestamp.proto\"3\n" +
+	"\x15GetDriverShiftRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\"\xb2\x03\n" +
+	"\fShiftSummary\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x128\n" +
+	"\tstartedAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12$\n" +
+	"\ronlineSeconds\x18\x04 \x01(\x03R\ronlineSeconds\x12$\n" +
+	"\ronTripSeconds\x18\x05 \x01(\x03R\ronTripSeconds\x12,\n" +
+	"\x11continuousSeconds\x18\x06 \x01(\x03R\x11continuousSeconds\x122\n" +
+	"\x14maxContinuousSeconds\x18\a \x01(\x03R\x14maxContinuousSeconds\x12(\n" +
+	"\x0fmaxDailySeconds\x18\b \x01(\x03R\x0fmaxDailySeconds\x12:\n" +
+	"\n" +
+	"breakUntil\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"breakUntil\x12 \n" +
+	"\vbreakReason\x18\n" +
+	" \x01(\tR\vbreakReason\"\x7f\n" +
+	"\x19GetDriverStatementRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\tR\x06period\x12.\n" +
+	"\x04date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\"\xe3\x01\n" +
+	"\rStatementLine\x12$\n" +
+	"\rtransactionID\x18\x01 \x01(\tR\rtransactionID\x128\n" +
+	"\tcreatedAt\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x16\n" +
+	"\x06tripID\x18\x03 \x01(\tR\x06tripID\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12$\n" +
+	"\ramountInCents\x18\x05 \x01(\x03R\ramountInCents\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\"\x8c\x03\n" +
+	"\x0fDriverStatement\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\tR\x06period\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12(\n" +
+	"\x0fearningsInCents\x18\x05 \x01(\x03R\x0fearningsInCents\x12 \n" +
+	"\vfeesInCents\x18\x06 \x01(\x03R\vfeesInCents\x12 \n" +
+	"\vtipsInCents\x18\a \x01(\x03R\vtipsInCents\x12.\n" +
+	"\x12adjustmentsInCents\x18\b \x01(\x03R\x12adjustmentsInCents\x12\x1e\n" +
+	"\n" +
+	"netInCents\x18\t \x01(\x03R\n" +
+	"netInCents\x12+\n" +
+	"\x05lines\x18\n" +
+	" \x03(\v2\x15.driver.StatementLineR\x05lines\"M\n" +
+	"\x1dExportDriverStatementResponse\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x10\n" +
+	"\x03csv\x18\x02 \x01(\fR\x03csv\"\x99\x01\n" +
+	"\x1bAdjustDriverEarningsRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12$\n" +
+	"\ramountInCents\x18\x02 \x01(\x03R\ramountInCents\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12 \n" +
+	"\vreferenceID\x18\x04 \x01(\tR\vreferenceID\"\xa7\x01\n" +
+	"\aVehicle\x12\x12\n" +
+	"\x04make\x18\x01 \x01(\tR\x04make\x12\x14\n" +
+	"\x05model\x18\x02 \x01(\tR\x05model\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\x12\x14\n" +
+	"\x05plate\x18\x04 \x01(\tR\x05plate\x12\"\n" +
+	"\fseatCapacity\x18\x05 \x01(\x05R\fseatCapacity\x12\"\n" +
+	"\fpackageSlugs\x18\x06 \x03(\tR\fpackageSlugs\"\xf2\x01\n" +
+	"\rDriverProfile\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12&\n" +
+	"\x0eprofilePicture\x18\x03 \x01(\tR\x0eprofilePicture\x12$\n" +
+	"\rlicenseNumber\x18\x04 \x01(\tR\rlicenseNumber\x12)\n" +
+	"\avehicle\x18\x05 \x01(\v2\x0f.driver.VehicleR\avehicle\x12\x16\n" +
+	"\x06rating\x18\x06 \x01(\x01R\x06rating\x12 \n" +
+	"\vratingCount\x18\a \x01(\x05R\vratingCount\"G\n" +
+	"\x14DriverProfileRequest\x12/\n" +
+	"\aprofile\x18\x01 \x01(\v2\x15.driver.DriverProfileR\aprofile\"5\n" +
+	"\x17GetDriverProfileRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\"\x1d\n" +
+	"\x1bDeleteDriverProfileResponse\"\x1b\n" +
+	"\x19ListDriverProfilesRequest\"O\n" +
+	"\x1aListDriverProfilesResponse\x121\n" +
+	"\bprofiles\x18\x01 \x03(\v2\x15.driver.DriverProfileR\bprofiles\"5\n" +
+	"\x17UnregisterDriverRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\".\n" +
+	"\x10GetDriverRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\";\n" +
+	"\x11GetDriverResponse\x12&\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\"\xa4\x01\n" +
+	"\x18ListNearbyDriversRequest\x12,\n" +
+	"\blocation\x18\x01 \x01(\v2\x10.driver.LocationR\blocation\x12\"\n" +
+	"\fradiusMeters\x18\x02 \x01(\x01R\fradiusMeters\x12 \n" +
+	"\vpackageSlug\x18\x03 \x01(\tR\vpackageSlug\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"d\n" +
+	"\fNearbyDriver\x12&\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\x12\x1a\n" +
+	"\bdistance\x18\x02 \x01(\x01R\bdistance\x12\x10\n" +
+	"\x03eta\x18\x03 \x01(\x01R\x03eta\"K\n" +
+	"\x19ListNearbyDriversResponse\x12.\n" +
+	"\adrivers\x18\x01 \x03(\v2\x14.driver.NearbyDriverR\adrivers\"8\n" +
+	"\x1aWatchDriverLocationRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\"g\n" +
+	"\x1bUpdateDriverLocationRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12,\n" +
+	"\blocation\x18\x02 \x01(\v2\x10.driver.LocationR\blocation\".\n" +
+	"\x10HeartbeatRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\"W\n" +
+	"\x11HeartbeatResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12*\n" +
+	"\x05shift\x18\x02 \x01(\v2\x14.driver.ShiftSummaryR\x05shift\"L\n" +
+	"\x16SetDriverStatusRequest\x12\x1a\n" +
+	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"U\n" +
 	"\x15RegisterDriverRequest\x12\x1a\n" +
 	"\bdriverID\x18\x01 \x01(\tR\bdriverID\x12 \n" +
 	"\vpackageSlug\x18\x02 \x01(\tR\vpackageSlug\"@\n" +
 	"\x16RegisterDriverResponse\x12&\n" +
-	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\"\xda\x01\n" +
+	"\x06driver\x18\x01 \x01(\v2\x0e.driver.DriverR\x06driver\"\xfb\x02\n" +
 	"\x06Driver\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12&\n" +
@@ -278,13 +1913,35 @@ const file_driver_proto_rawDesc = "" +
 	"\bcarPlate\x18\x04 \x01(\tR\bcarPlate\x12\x18\n" +
 	"\ageohash\x18\x05 \x01(\tR\ageohash\x12 \n" +
 	"\vpackageSlug\x18\x06 \x01(\tR\vpackageSlug\x12,\n" +
-	"\blocation\x18\a \x01(\v2\x10.driver.LocationR\blocation\"D\n" +
+	"\blocation\x18\a \x01(\v2\x10.driver.LocationR\blocation\x12\"\n" +
+	"\fseatCapacity\x18\b \x01(\x05R\fseatCapacity\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12)\n" +
+	"\avehicle\x18\n" +
+	" \x01(\v2\x0f.driver.VehicleR\avehicle\x12\x16\n" +
+	"\x06rating\x18\v \x01(\x01R\x06rating\x12 \n" +
+	"\vratingCount\x18\f \x01(\x05R\vratingCount\"D\n" +
 	"\bLocation\x12\x1a\n" +
 	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x02 \x01(\x01R\tlongitude2\xb3\x01\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude2\xfe\n" +
+	"\n" +
 	"\rDriverService\x12O\n" +
-	"\x0eRegisterDriver\x12\x1d.driver.RegisterDriverRequest\x1a\x1e.driver.RegisterDriverResponse\x12Q\n" +
-	"\x10UnregisterDriver\x12\x1d.driver.RegisterDriverRequest\x1a\x1e.driver.RegisterDriverResponseB\x1cZ\x1ashared/proto/driver;driverb\x06proto3"
+	"\x0eRegisterDriver\x12\x1d.driver.RegisterDriverRequest\x1a\x1e.driver.RegisterDriverResponse\x12S\n" +
+	"\x10UnregisterDriver\x12\x1f.driver.UnregisterDriverRequest\x1a\x1e.driver.RegisterDriverResponse\x12@\n" +
+	"\tHeartbeat\x12\x18.driver.HeartbeatRequest\x1a\x19.driver.HeartbeatResponse\x12Q\n" +
+	"\x0fSetDriverStatus\x12\x1e.driver.SetDriverStatusRequest\x1a\x1e.driver.RegisterDriverResponse\x12[\n" +
+	"\x14UpdateDriverLocation\x12#.driver.UpdateDriverLocationRequest\x1a\x1e.driver.RegisterDriverResponse\x12@\n" +
+	"\tGetDriver\x12\x18.driver.GetDriverRequest\x1a\x19.driver.GetDriverResponse\x12X\n" +
+	"\x11ListNearbyDrivers\x12 .driver.ListNearbyDriversRequest\x1a!.driver.ListNearbyDriversResponse\x12K\n" +
+	"\x13WatchDriverLocation\x12\".driver.WatchDriverLocationRequest\x1a\x0e.driver.Driver0\x01\x12J\n" +
+	"\x13CreateDriverProfile\x12\x1c.driver.DriverProfileRequest\x1a\x15.driver.DriverProfile\x12J\n" +
+	"\x10GetDriverProfile\x12\x1f.driver.GetDriverProfileRequest\x1a\x15.driver.DriverProfile\x12J\n" +
+	"\x13UpdateDriverProfile\x12\x1c.driver.DriverProfileRequest\x1a\x15.driver.DriverProfile\x12[\n" +
+	"\x13DeleteDriverProfile\x12\x1f.driver.GetDriverProfileRequest\x1a#.driver.DeleteDriverProfileResponse\x12[\n" +
+	"\x12ListDriverProfiles\x12!.driver.ListDriverProfilesRequest\x1a\".driver.ListDriverProfilesResponse\x12P\n" +
+	"\x12GetDriverStatement\x12!.driver.GetDriverStatementRequest\x1a\x17.driver.DriverStatement\x12a\n" +
+	"\x15ExportDriverStatement\x12!.driver.GetDriverStatementRequest\x1a%.driver.ExportDriverStatementResponse\x12R\n" +
+	"\x14AdjustDriverEarnings\x12#.driver.AdjustDriverEarningsRequest\x1a\x15.driver.StatementLine\x12E\n" +
+	"\x0eGetDriverShift\x12\x1d.driver.GetDriverShiftRequest\x1a\x14.driver.ShiftSummaryB\x1cZ\x1ashared/proto/driver;driverb\x06proto3"
 
 var (
 	file_driver_proto_rawDescOnce sync.Once
@@ -298,25 +1955,98 @@ func file_driver_proto_rawDescGZIP() []byte {
 	return file_driver_proto_rawDescData
 }
 
-var file_driver_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_driver_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_driver_proto_goTypes = []any{
-	(*RegisterDriverRequest)(nil),  // 0: driver.RegisterDriverRequest
-	(*RegisterDriverResponse)(nil), // 1: driver.RegisterDriverResponse
-	(*Driver)(nil),                 // 2: driver.Driver
-	(*Location)(nil),               // 3: driver.Location
+	(*GetDriverShiftRequest)(nil),         // 0: driver.GetDriverShiftRequest
+	(*ShiftSummary)(nil),                  // 1: driver.ShiftSummary
+	(*GetDriverStatementRequest)(nil),     // 2: driver.GetDriverStatementRequest
+	(*StatementLine)(nil),                 // 3: driver.StatementLine
+	(*DriverStatement)(nil),               // 4: driver.DriverStatement
+	(*ExportDriverStatementResponse)(nil), // 5: driver.ExportDriverStatementResponse
+	(*AdjustDriverEarningsRequest)(nil),   // 6: driver.AdjustDriverEarningsRequest
+	(*Vehicle)(nil),                       // 7: driver.Vehicle
+	(*DriverProfile)(nil),                 // 8: driver.DriverProfile
+	(*DriverProfileRequest)(nil),          // 9: driver.DriverProfileRequest
+	(*GetDriverProfileRequest)(nil),       // 10: driver.GetDriverProfileRequest
+	(*DeleteDriverProfileResponse)(nil),   // 11: driver.DeleteDriverProfileResponse
+	(*ListDriverProfilesRequest)(nil),     // 12: driver.ListDriverProfilesRequest
+	(*ListDriverProfilesResponse)(nil),    // 13: driver.ListDriverProfilesResponse
+	(*UnregisterDriverRequest)(nil),       // 14: driver.UnregisterDriverRequest
+	(*GetDriverRequest)(nil),              // 15: driver.GetDriverRequest
+	(*GetDriverResponse)(nil),             // 16: driver.GetDriverResponse
+	(*ListNearbyDriversRequest)(nil),      // 17: driver.ListNearbyDriversRequest
+	(*NearbyDriver)(nil),                  // 18: driver.NearbyDriver
+	(*ListNearbyDriversResponse)(nil),     // 19: driver.ListNearbyDriversResponse
+	(*WatchDriverLocationRequest)(nil),    // 20: driver.WatchDriverLocationRequest
+	(*UpdateDriverLocationRequest)(nil),   // 21: driver.UpdateDriverLocationRequest
+	(*HeartbeatRequest)(nil),              // 22: driver.HeartbeatRequest
+	(*HeartbeatResponse)(nil),             // 23: driver.HeartbeatResponse
+	(*SetDriverStatusRequest)(nil),        // 24: driver.SetDriverStatusRequest
+	(*RegisterDriverRequest)(nil),         // 25: driver.RegisterDriverRequest
+	(*RegisterDriverResponse)(nil),        // 26: driver.RegisterDriverResponse
+	(*Driver)(nil),                        // 27: driver.Driver
+	(*Location)(nil),                      // 28: driver.Location
+	(*timestamppb.Timestamp)(nil),         // 29: google.protobuf.Timestamp
 }
 var file_driver_proto_depIdxs = []int32{
-	2, // 0: driver.RegisterDriverResponse.driver:type_name -> driver.Driver
-	3, // 1: driver.Driver.location:type_name -> driver.Location
-	0, // 2: driver.DriverService.RegisterDriver:input_type -> driver.RegisterDriverRequest
-	0, // 3: driver.DriverService.UnregisterDriver:input_type -> driver.RegisterDriverRequest
-	1, // 4: driver.DriverService.RegisterDriver:output_type -> driver.RegisterDriverResponse
-	1, // 5: driver.DriverService.UnregisterDriver:output_type -> driver.RegisterDriverResponse
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	29, // 0: driver.ShiftSummary.startedAt:type_name -> google.protobuf.Timestamp
+	29, // 1: driver.ShiftSummary.breakUntil:type_name -> google.protobuf.Timestamp
+	29, // 2: driver.GetDriverStatementRequest.date:type_name -> google.protobuf.Timestamp
+	29, // 3: driver.StatementLine.createdAt:type_name -> google.protobuf.Timestamp
+	29, // 4: driver.DriverStatement.from:type_name -> google.protobuf.Timestamp
+	29, // 5: driver.DriverStatement.to:type_name -> google.protobuf.Timestamp
+	3,  // 6: driver.DriverStatement.lines:type_name -> driver.StatementLine
+	7,  // 7: driver.DriverProfile.vehicle:type_name -> driver.Vehicle
+	8,  // 8: driver.DriverProfileRequest.profile:type_name -> driver.DriverProfile
+	8,  // 9: driver.ListDriverProfilesResponse.profiles:type_name -> driver.DriverProfile
+	27, // 10: driver.GetDriverResponse.driver:type_name -> driver.Driver
+	28, // 11: driver.ListNearbyDriversRequest.location:type_name -> driver.Location
+	27, // 12: driver.NearbyDriver.driver:type_name -> driver.Driver
+	18, // 13: driver.ListNearbyDriversResponse.drivers:type_name -> driver.NearbyDriver
+	28, // 14: driver.UpdateDriverLocationRequest.location:type_name -> driver.Location
+	1,  // 15: driver.HeartbeatResponse.shift:type_name -> driver.ShiftSummary
+	27, // 16: driver.RegisterDriverResponse.driver:type_name -> driver.Driver
+	28, // 17: driver.Driver.location:type_name -> driver.Location
+	7,  // 18: driver.Driver.vehicle:type_name -> driver.Vehicle
+	25, // 19: driver.DriverService.RegisterDriver:input_type -> driver.RegisterDriverRequest
+	14, // 20: driver.DriverService.UnregisterDriver:input_type -> driver.UnregisterDriverRequest
+	22, // 21: driver.DriverService.Heartbeat:input_type -> driver.HeartbeatRequest
+	24, // 22: driver.DriverService.SetDriverStatus:input_type -> driver.SetDriverStatusRequest
+	21, // 23: driver.DriverService.UpdateDriverLocation:input_type -> driver.UpdateDriverLocationRequest
+	15, // 24: driver.DriverService.GetDriver:input_type -> driver.GetDriverRequest
+	17, // 25: driver.DriverService.ListNearbyDrivers:input_type -> driver.ListNearbyDriversRequest
+	20, // 26: driver.DriverService.WatchDriverLocation:input_type -> driver.WatchDriverLocationRequest
+	9,  // 27: driver.DriverService.CreateDriverProfile:input_type -> driver.DriverProfileRequest
+	10, // 28: driver.DriverService.GetDriverProfile:input_type -> driver.GetDriverProfileRequest
+	9,  // 29: driver.DriverService.UpdateDriverProfile:input_type -> driver.DriverProfileRequest
+	10, // 30: driver.DriverService.DeleteDriverProfile:input_type -> driver.GetDriverProfileRequest
+	12, // 31: driver.DriverService.ListDriverProfiles:input_type -> driver.ListDriverProfilesRequest
+	2,  // 32: driver.DriverService.GetDriverStatement:input_type -> driver.GetDriverStatementRequest
+	2,  // 33: driver.DriverService.ExportDriverStatement:input_type -> driver.GetDriverStatementRequest
+	6,  // 34: driver.DriverService.AdjustDriverEarnings:input_type -> driver.AdjustDriverEarningsRequest
+	0,  // 35: driver.DriverService.GetDriverShift:input_type -> driver.GetDriverShiftRequest
+	26, // 36: driver.DriverService.RegisterDriver:output_type -> driver.RegisterDriverResponse
+	26, // 37: driver.DriverService.UnregisterDriver:output_type -> driver.RegisterDriverResponse
+	23, // 38: driver.DriverService.Heartbeat:output_type -> driver.HeartbeatResponse
+	26, // 39: driver.DriverService.SetDriverStatus:output_type -> driver.RegisterDriverResponse
+	26, // 40: driver.DriverService.UpdateDriverLocation:output_type -> driver.RegisterDriverResponse
+	16, // 41: driver.DriverService.GetDriver:output_type -> driver.GetDriverResponse
+	19, // 42: driver.DriverService.ListNearbyDrivers:output_type -> driver.ListNearbyDriversResponse
+	27, // 43: driver.DriverService.WatchDriverLocation:output_type -> driver.Driver
+	8,  // 44: driver.DriverService.CreateDriverProfile:output_type -> driver.DriverProfile
+	8,  // 45: driver.DriverService.GetDriverProfile:output_type -> driver.DriverProfile
+	8,  // 46: driver.DriverService.UpdateDriverProfile:output_type -> driver.DriverProfile
+	11, // 47: driver.DriverService.DeleteDriverProfile:output_type -> driver.DeleteDriverProfileResponse
+	13, // 48: driver.DriverService.ListDriverProfiles:output_type -> driver.ListDriverProfilesResponse
+	4,  // 49: driver.DriverService.GetDriverStatement:output_type -> driver.DriverStatement
+	5,  // 50: driver.DriverService.ExportDriverStatement:output_type -> driver.ExportDriverStatementResponse
+	3,  // 51: driver.DriverService.AdjustDriverEarnings:output_type -> driver.StatementLine
+	1,  // 52: driver.DriverService.GetDriverShift:output_type -> driver.ShiftSummary
+	36, // [36:53] is the sub-list for method output_type
+	19, // [19:36] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_driver_proto_init() }
@@ -330,7 +2060,7 @@ func file_driver_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_driver_proto_rawDesc), len(file_driver_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   29,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
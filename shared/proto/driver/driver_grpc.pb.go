@@ -19,8 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DriverService_RegisterDriver_FullMethodName   = "/driver.DriverService/RegisterDriver"
-	DriverService_UnregisterDriver_FullMethodName = "/driver.DriverService/UnregisterDriver"
+	DriverService_RegisterDriver_FullMethodName        = "/driver.DriverService/RegisterDriver"
+	DriverService_UnregisterDriver_FullMethodName      = "/driver.DriverService/UnregisterDriver"
+	DriverService_Heartbeat_FullMethodName             = "/driver.DriverService/Heartbeat"
+	DriverService_SetDriverStatus_FullMethodName       = "/driver.DriverService/SetDriverStatus"
+	DriverService_UpdateDriverLocation_FullMethodName  = "/driver.DriverService/UpdateDriverLocation"
+	DriverService_GetDriver_FullMethodName             = "/driver.DriverService/GetDriver"
+	DriverService_ListNearbyDrivers_FullMethodName     = "/driver.DriverService/ListNearbyDrivers"
+	DriverService_WatchDriverLocation_FullMethodName   = "/driver.DriverService/WatchDriverLocation"
+	DriverService_CreateDriverProfile_FullMethodName   = "/driver.DriverService/CreateDriverProfile"
+	DriverService_GetDriverProfile_FullMethodName      = "/driver.DriverService/GetDriverProfile"
+	DriverService_UpdateDriverProfile_FullMethodName   = "/driver.DriverService/UpdateDriverProfile"
+	DriverService_DeleteDriverProfile_FullMethodName   = "/driver.DriverService/DeleteDriverProfile"
+	DriverService_ListDriverProfiles_FullMethodName    = "/driver.DriverService/ListDriverProfiles"
+	DriverService_GetDriverStatement_FullMethodName    = "/driver.DriverService/GetDriverStatement"
+	DriverService_ExportDriverStatement_FullMethodName = "/driver.DriverService/ExportDriverStatement"
+	DriverService_AdjustDriverEarnings_FullMethodName  = "/driver.DriverService/AdjustDriverEarnings"
+	DriverService_GetDriverShift_FullMethodName        = "/driver.DriverService/GetDriverShift"
 )
 
 // DriverServiceClient is the client API for DriverService service.
@@ -28,7 +43,23 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type DriverServiceClient interface {
 	RegisterDriver(ctx context.Context, in *RegisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
-	UnregisterDriver(ctx context.Context, in *RegisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
+	UnregisterDriver(ctx context.Context, in *UnregisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	SetDriverStatus(ctx context.Context, in *SetDriverStatusRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
+	UpdateDriverLocation(ctx context.Context, in *UpdateDriverLocationRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error)
+	GetDriver(ctx context.Context, in *GetDriverRequest, opts ...grpc.CallOption) (*GetDriverResponse, error)
+	ListNearbyDrivers(ctx context.Context, in *ListNearbyDriversRequest, opts ...grpc.CallOption) (*ListNearbyDriversResponse, error)
+	// Streams the driver every time it moves, until it unregisters
+	WatchDriverLocation(ctx context.Context, in *WatchDriverLocationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Driver], error)
+	CreateDriverProfile(ctx context.Context, in *DriverProfileRequest, opts ...grpc.CallOption) (*DriverProfile, error)
+	GetDriverProfile(ctx context.Context, in *GetDriverProfileRequest, opts ...grpc.CallOption) (*DriverProfile, error)
+	UpdateDriverProfile(ctx context.Context, in *DriverProfileRequest, opts ...grpc.CallOption) (*DriverProfile, error)
+	DeleteDriverProfile(ctx context.Context, in *GetDriverProfileRequest, opts ...grpc.CallOption) (*DeleteDriverProfileResponse, error)
+	ListDriverProfiles(ctx context.Context, in *ListDriverProfilesRequest, opts ...grpc.CallOption) (*ListDriverProfilesResponse, error)
+	GetDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*DriverStatement, error)
+	ExportDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*ExportDriverStatementResponse, error)
+	AdjustDriverEarnings(ctx context.Context, in *AdjustDriverEarningsRequest, opts ...grpc.CallOption) (*StatementLine, error)
+	GetDriverShift(ctx context.Context, in *GetDriverShiftRequest, opts ...grpc.CallOption) (*ShiftSummary, error)
 }
 
 type driverServiceClient struct {
@@ -49,7 +80,7 @@ func (c *driverServiceClient) RegisterDriver(ctx context.Context, in *RegisterDr
 	return out, nil
 }
 
-func (c *driverServiceClient) UnregisterDriver(ctx context.Context, in *RegisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error) {
+func (c *driverServiceClient) UnregisterDriver(ctx context.Context, in *UnregisterDriverRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RegisterDriverResponse)
 	err := c.cc.Invoke(ctx, DriverService_UnregisterDriver_FullMethodName, in, out, cOpts...)
@@ -59,12 +90,187 @@ func (c *driverServiceClient) UnregisterDriver(ctx context.Context, in *Register
 	return out, nil
 }
 
+func (c *driverServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, DriverService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) SetDriverStatus(ctx context.Context, in *SetDriverStatusRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterDriverResponse)
+	err := c.cc.Invoke(ctx, DriverService_SetDriverStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) UpdateDriverLocation(ctx context.Context, in *UpdateDriverLocationRequest, opts ...grpc.CallOption) (*RegisterDriverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterDriverResponse)
+	err := c.cc.Invoke(ctx, DriverService_UpdateDriverLocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) GetDriver(ctx context.Context, in *GetDriverRequest, opts ...grpc.CallOption) (*GetDriverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDriverResponse)
+	err := c.cc.Invoke(ctx, DriverService_GetDriver_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) ListNearbyDrivers(ctx context.Context, in *ListNearbyDriversRequest, opts ...grpc.CallOption) (*ListNearbyDriversResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNearbyDriversResponse)
+	err := c.cc.Invoke(ctx, DriverService_ListNearbyDrivers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) WatchDriverLocation(ctx context.Context, in *WatchDriverLocationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Driver], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DriverService_ServiceDesc.Streams[0], DriverService_WatchDriverLocation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDriverLocationRequest, Driver]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DriverService_WatchDriverLocationClient = grpc.ServerStreamingClient[Driver]
+
+func (c *driverServiceClient) CreateDriverProfile(ctx context.Context, in *DriverProfileRequest, opts ...grpc.CallOption) (*DriverProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverProfile)
+	err := c.cc.Invoke(ctx, DriverService_CreateDriverProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) GetDriverProfile(ctx context.Context, in *GetDriverProfileRequest, opts ...grpc.CallOption) (*DriverProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverProfile)
+	err := c.cc.Invoke(ctx, DriverService_GetDriverProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) UpdateDriverProfile(ctx context.Context, in *DriverProfileRequest, opts ...grpc.CallOption) (*DriverProfile, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverProfile)
+	err := c.cc.Invoke(ctx, DriverService_UpdateDriverProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) DeleteDriverProfile(ctx context.Context, in *GetDriverProfileRequest, opts ...grpc.CallOption) (*DeleteDriverProfileResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDriverProfileResponse)
+	err := c.cc.Invoke(ctx, DriverService_DeleteDriverProfile_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) ListDriverProfiles(ctx context.Context, in *ListDriverProfilesRequest, opts ...grpc.CallOption) (*ListDriverProfilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDriverProfilesResponse)
+	err := c.cc.Invoke(ctx, DriverService_ListDriverProfiles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) GetDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*DriverStatement, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DriverStatement)
+	err := c.cc.Invoke(ctx, DriverService_GetDriverStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) ExportDriverStatement(ctx context.Context, in *GetDriverStatementRequest, opts ...grpc.CallOption) (*ExportDriverStatementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportDriverStatementResponse)
+	err := c.cc.Invoke(ctx, DriverService_ExportDriverStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) AdjustDriverEarnings(ctx context.Context, in *AdjustDriverEarningsRequest, opts ...grpc.CallOption) (*StatementLine, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatementLine)
+	err := c.cc.Invoke(ctx, DriverService_AdjustDriverEarnings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) GetDriverShift(ctx context.Context, in *GetDriverShiftRequest, opts ...grpc.CallOption) (*ShiftSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShiftSummary)
+	err := c.cc.Invoke(ctx, DriverService_GetDriverShift_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DriverServiceServer is the server API for DriverService service.
 // All implementations must embed UnimplementedDriverServiceServer
 // for forward compatibility.
 type DriverServiceServer interface {
 	RegisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error)
-	UnregisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error)
+	UnregisterDriver(context.Context, *UnregisterDriverRequest) (*RegisterDriverResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	SetDriverStatus(context.Context, *SetDriverStatusRequest) (*RegisterDriverResponse, error)
+	UpdateDriverLocation(context.Context, *UpdateDriverLocationRequest) (*RegisterDriverResponse, error)
+	GetDriver(context.Context, *GetDriverRequest) (*GetDriverResponse, error)
+	ListNearbyDrivers(context.Context, *ListNearbyDriversRequest) (*ListNearbyDriversResponse, error)
+	// Streams the driver every time it moves, until it unregisters
+	WatchDriverLocation(*WatchDriverLocationRequest, grpc.ServerStreamingServer[Driver]) error
+	CreateDriverProfile(context.Context, *DriverProfileRequest) (*DriverProfile, error)
+	GetDriverProfile(context.Context, *GetDriverProfileRequest) (*DriverProfile, error)
+	UpdateDriverProfile(context.Context, *DriverProfileRequest) (*DriverProfile, error)
+	DeleteDriverProfile(context.Context, *GetDriverProfileRequest) (*DeleteDriverProfileResponse, error)
+	ListDriverProfiles(context.Context, *ListDriverProfilesRequest) (*ListDriverProfilesResponse, error)
+	GetDriverStatement(context.Context, *GetDriverStatementRequest) (*DriverStatement, error)
+	ExportDriverStatement(context.Context, *GetDriverStatementRequest) (*ExportDriverStatementResponse, error)
+	AdjustDriverEarnings(context.Context, *AdjustDriverEarningsRequest) (*StatementLine, error)
+	GetDriverShift(context.Context, *GetDriverShiftRequest) (*ShiftSummary, error)
 	mustEmbedUnimplementedDriverServiceServer()
 }
 
@@ -78,9 +284,54 @@ type UnimplementedDriverServiceServer struct{}
 func (UnimplementedDriverServiceServer) RegisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RegisterDriver not implemented")
 }
-func (UnimplementedDriverServiceServer) UnregisterDriver(context.Context, *RegisterDriverRequest) (*RegisterDriverResponse, error) {
+func (UnimplementedDriverServiceServer) UnregisterDriver(context.Context, *UnregisterDriverRequest) (*RegisterDriverResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UnregisterDriver not implemented")
 }
+func (UnimplementedDriverServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedDriverServiceServer) SetDriverStatus(context.Context, *SetDriverStatusRequest) (*RegisterDriverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDriverStatus not implemented")
+}
+func (UnimplementedDriverServiceServer) UpdateDriverLocation(context.Context, *UpdateDriverLocationRequest) (*RegisterDriverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDriverLocation not implemented")
+}
+func (UnimplementedDriverServiceServer) GetDriver(context.Context, *GetDriverRequest) (*GetDriverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriver not implemented")
+}
+func (UnimplementedDriverServiceServer) ListNearbyDrivers(context.Context, *ListNearbyDriversRequest) (*ListNearbyDriversResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNearbyDrivers not implemented")
+}
+func (UnimplementedDriverServiceServer) WatchDriverLocation(*WatchDriverLocationRequest, grpc.ServerStreamingServer[Driver]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDriverLocation not implemented")
+}
+func (UnimplementedDriverServiceServer) CreateDriverProfile(context.Context, *DriverProfileRequest) (*DriverProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDriverProfile not implemented")
+}
+func (UnimplementedDriverServiceServer) GetDriverProfile(context.Context, *GetDriverProfileRequest) (*DriverProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverProfile not implemented")
+}
+func (UnimplementedDriverServiceServer) UpdateDriverProfile(context.Context, *DriverProfileRequest) (*DriverProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDriverProfile not implemented")
+}
+func (UnimplementedDriverServiceServer) DeleteDriverProfile(context.Context, *GetDriverProfileRequest) (*DeleteDriverProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDriverProfile not implemented")
+}
+func (UnimplementedDriverServiceServer) ListDriverProfiles(context.Context, *ListDriverProfilesRequest) (*ListDriverProfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDriverProfiles not implemented")
+}
+func (UnimplementedDriverServiceServer) GetDriverStatement(context.Context, *GetDriverStatementRequest) (*DriverStatement, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverStatement not implemented")
+}
+func (UnimplementedDriverServiceServer) ExportDriverStatement(context.Context, *GetDriverStatementRequest) (*ExportDriverStatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportDriverStatement not implemented")
+}
+func (UnimplementedDriverServiceServer) AdjustDriverEarnings(context.Context, *AdjustDriverEarningsRequest) (*StatementLine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustDriverEarnings not implemented")
+}
+func (UnimplementedDriverServiceServer) GetDriverShift(context.Context, *GetDriverShiftRequest) (*ShiftSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverShift not implemented")
+}
 func (UnimplementedDriverServiceServer) mustEmbedUnimplementedDriverServiceServer() {}
 func (UnimplementedDriverServiceServer) testEmbeddedByValue()                       {}
 
@@ -121,7 +372,7 @@ func _DriverService_RegisterDriver_Handler(srv interface{}, ctx context.Context,
 }
 
 func _DriverService_UnregisterDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterDriverRequest)
+	in := new(UnregisterDriverRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -133,7 +384,270 @@ func _DriverService_UnregisterDriver_Handler(srv interface{}, ctx context.Contex
 		FullMethod: DriverService_UnregisterDriver_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(DriverServiceServer).UnregisterDriver(ctx, req.(*RegisterDriverRequest))
+		return srv.(DriverServiceServer).UnregisterDriver(ctx, req.(*UnregisterDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_SetDriverStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDriverStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).SetDriverStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_SetDriverStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).SetDriverStatus(ctx, req.(*SetDriverStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_UpdateDriverLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDriverLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).UpdateDriverLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_UpdateDriverLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).UpdateDriverLocation(ctx, req.(*UpdateDriverLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_GetDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetDriver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetDriver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetDriver(ctx, req.(*GetDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_ListNearbyDrivers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNearbyDriversRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).ListNearbyDrivers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_ListNearbyDrivers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).ListNearbyDrivers(ctx, req.(*ListNearbyDriversRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_WatchDriverLocation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDriverLocationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DriverServiceServer).WatchDriverLocation(m, &grpc.GenericServerStream[WatchDriverLocationRequest, Driver]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DriverService_WatchDriverLocationServer = grpc.ServerStreamingServer[Driver]
+
+func _DriverService_CreateDriverProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DriverProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).CreateDriverProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_CreateDriverProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).CreateDriverProfile(ctx, req.(*DriverProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_GetDriverProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetDriverProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetDriverProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetDriverProfile(ctx, req.(*GetDriverProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_UpdateDriverProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DriverProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).UpdateDriverProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_UpdateDriverProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).UpdateDriverProfile(ctx, req.(*DriverProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_DeleteDriverProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).DeleteDriverProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_DeleteDriverProfile_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).DeleteDriverProfile(ctx, req.(*GetDriverProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_ListDriverProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDriverProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).ListDriverProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_ListDriverProfiles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).ListDriverProfiles(ctx, req.(*ListDriverProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_GetDriverStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetDriverStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetDriverStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetDriverStatement(ctx, req.(*GetDriverStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_ExportDriverStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).ExportDriverStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_ExportDriverStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).ExportDriverStatement(ctx, req.(*GetDriverStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_AdjustDriverEarnings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustDriverEarningsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).AdjustDriverEarnings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_AdjustDriverEarnings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).AdjustDriverEarnings(ctx, req.(*AdjustDriverEarningsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_GetDriverShift_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverShiftRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetDriverShift(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetDriverShift_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetDriverShift(ctx, req.(*GetDriverShiftRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -153,7 +667,69 @@ var DriverService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UnregisterDriver",
 			Handler:    _DriverService_UnregisterDriver_Handler,
 		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _DriverService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "SetDriverStatus",
+			Handler:    _DriverService_SetDriverStatus_Handler,
+		},
+		{
+			MethodName: "UpdateDriverLocation",
+			Handler:    _DriverService_UpdateDriverLocation_Handler,
+		},
+		{
+			MethodName: "GetDriver",
+			Handler:    _DriverService_GetDriver_Handler,
+		},
+		{
+			MethodName: "ListNearbyDrivers",
+			Handler:    _DriverService_ListNearbyDrivers_Handler,
+		},
+		{
+			MethodName: "CreateDriverProfile",
+			Handler:    _DriverService_CreateDriverProfile_Handler,
+		},
+		{
+			MethodName: "GetDriverProfile",
+			Handler:    _DriverService_GetDriverProfile_Handler,
+		},
+		{
+			MethodName: "UpdateDriverProfile",
+			Handler:    _DriverService_UpdateDriverProfile_Handler,
+		},
+		{
+			MethodName: "DeleteDriverProfile",
+			Handler:    _DriverService_DeleteDriverProfile_Handler,
+		},
+		{
+			MethodName: "ListDriverProfiles",
+			Handler:    _DriverService_ListDriverProfiles_Handler,
+		},
+		{
+			MethodName: "GetDriverStatement",
+			Handler:    _DriverService_GetDriverStatement_Handler,
+		},
+		{
+			MethodName: "ExportDriverStatement",
+			Handler:    _DriverService_ExportDriverStatement_Handler,
+		},
+		{
+			MethodName: "AdjustDriverEarnings",
+			Handler:    _DriverService_AdjustDriverEarnings_Handler,
+		},
+		{
+			MethodName: "GetDriverShift",
+			Handler:    _DriverService_GetDriverShift_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDriverLocation",
+			Handler:       _DriverService_WatchDriverLocation_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "driver.proto",
 }
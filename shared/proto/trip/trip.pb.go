@@ -9,6 +9,7 @@ package trip
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -26,6 +27,10 @@ type PreviewTripReq struct {
 	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
 	StartLocation *Coordinate            `protobuf:"bytes,2,opt,name=startLocation,proto3" json:"startLocation,omitempty"`
 	EndLocation   *Coordinate            `protobuf:"bytes,3,opt,name=endLocation,proto3" json:"endLocation,omitempty"`
+	// Intermediate stops between the start and the end location, in order
+	Waypoints []*Coordinate `protobuf:"bytes,4,rep,name=waypoints,proto3" json:"waypoints,omitempty"`
+	// Optional promo code, discounting the fares of the packages it applies to
+	PromoCode     string `protobuf:"bytes,5,opt,name=promoCode,proto3" json:"promoCode,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,6 +86,20 @@ func (x *PreviewTripReq) GetEndLocation() *Coordinate {
 	return nil
 }
 
+func (x *PreviewTripReq) GetWaypoints() []*Coordinate {
+	if x != nil {
+		return x.Waypoints
+	}
+	return nil
+}
+
+func (x *PreviewTripReq) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
 type Coordinate struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
@@ -195,10 +214,12 @@ func (x *PreviewTripRes) GetRideFares() []*RideFare {
 }
 
 type Route struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Geometry      []*Geometry            `protobuf:"bytes,1,rep,name=geometry,proto3" json:"geometry,omitempty"`
-	Distance      float64                `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"`
-	Duration      float64                `protobuf:"fixed64,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Geometry []*Geometry            `protobuf:"bytes,1,rep,name=geometry,proto3" json:"geometry,omitempty"`
+	Distance float64                `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	Duration float64                `protobuf:"fixed64,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// One leg per segment between consecutive stops
+	Legs          []*RouteLeg `protobuf:"bytes,4,rep,name=legs,proto3" json:"legs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -254,6 +275,65 @@ func (x *Route) GetDuration() float64 {
 	return 0
 }
 
+func (x *Route) GetLegs() []*RouteLeg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+type RouteLeg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Distance      float64                `protobuf:"fixed64,1,opt,name=distance,proto3" json:"distance,omitempty"`
+	Duration      float64                `protobuf:"fixed64,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteLeg) Reset() {
+	*x = RouteLeg{}
+	mi := &file_trip_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteLeg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteLeg) ProtoMessage() {}
+
+func (x *RouteLeg) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteLeg.ProtoReflect.Descriptor instead.
+func (*RouteLeg) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RouteLeg) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *RouteLeg) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
 type Geometry struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Coordinates   []*Coordinate          `protobuf:"bytes,1,rep,name=coordinates,proto3" json:"coordinates,omitempty"`
@@ -263,7 +343,7 @@ type Geometry struct {
 
 func (x *Geometry) Reset() {
 	*x = Geometry{}
-	mi := &file_trip_proto_msgTypes[4]
+	mi := &file_trip_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -275,7 +355,7 @@ func (x *Geometry) String() string {
 func (*Geometry) ProtoMessage() {}
 
 func (x *Geometry) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[4]
+	mi := &file_trip_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,7 +368,7 @@ func (x *Geometry) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Geometry.ProtoReflect.Descriptor instead.
 func (*Geometry) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{4}
+	return file_trip_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *Geometry) GetCoordinates() []*Coordinate {
@@ -304,13 +384,17 @@ type RideFare struct {
 	UserID            string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
 	PackageSlug       string                 `protobuf:"bytes,3,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
 	TotalPriceInCents float64                `protobuf:"fixed64,4,opt,name=totalPriceInCents,proto3" json:"totalPriceInCents,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Set when a promo code discounts the fare, totalPriceInCents being the discounted price
+	PromoCode            string  `protobuf:"bytes,5,opt,name=promoCode,proto3" json:"promoCode,omitempty"`
+	OriginalPriceInCents float64 `protobuf:"fixed64,6,opt,name=originalPriceInCents,proto3" json:"originalPriceInCents,omitempty"`
+	DiscountInCents      float64 `protobuf:"fixed64,7,opt,name=discountInCents,proto3" json:"discountInCents,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *RideFare) Reset() {
 	*x = RideFare{}
-	mi := &file_trip_proto_msgTypes[5]
+	mi := &file_trip_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -322,7 +406,7 @@ func (x *RideFare) String() string {
 func (*RideFare) ProtoMessage() {}
 
 func (x *RideFare) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[5]
+	mi := &file_trip_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -335,7 +419,7 @@ func (x *RideFare) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RideFare.ProtoReflect.Descriptor instead.
 func (*RideFare) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{5}
+	return file_trip_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *RideFare) GetId() string {
@@ -366,17 +450,40 @@ func (x *RideFare) GetTotalPriceInCents() float64 {
 	return 0
 }
 
+func (x *RideFare) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *RideFare) GetOriginalPriceInCents() float64 {
+	if x != nil {
+		return x.OriginalPriceInCents
+	}
+	return 0
+}
+
+func (x *RideFare) GetDiscountInCents() float64 {
+	if x != nil {
+		return x.DiscountInCents
+	}
+	return 0
+}
+
 type CreateTripReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RideFareID    string                 `protobuf:"bytes,1,opt,name=rideFareID,proto3" json:"rideFareID,omitempty"`
-	UserID        string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	RideFareID string                 `protobuf:"bytes,1,opt,name=rideFareID,proto3" json:"rideFareID,omitempty"`
+	UserID     string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	// When set, the ride is booked ahead for this pickup time
+	ScheduledAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=scheduledAt,proto3" json:"scheduledAt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateTripReq) Reset() {
 	*x = CreateTripReq{}
-	mi := &file_trip_proto_msgTypes[6]
+	mi := &file_trip_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -388,7 +495,7 @@ func (x *CreateTripReq) String() string {
 func (*CreateTripReq) ProtoMessage() {}
 
 func (x *CreateTripReq) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[6]
+	mi := &file_trip_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -401,7 +508,7 @@ func (x *CreateTripReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTripReq.ProtoReflect.Descriptor instead.
 func (*CreateTripReq) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{6}
+	return file_trip_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateTripReq) GetRideFareID() string {
@@ -418,6 +525,13 @@ func (x *CreateTripReq) GetUserID() string {
 	return ""
 }
 
+func (x *CreateTripReq) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
 type CreateTripRes struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TripID        string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
@@ -428,7 +542,7 @@ type CreateTripRes struct {
 
 func (x *CreateTripRes) Reset() {
 	*x = CreateTripRes{}
-	mi := &file_trip_proto_msgTypes[7]
+	mi := &file_trip_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -440,7 +554,7 @@ func (x *CreateTripRes) String() string {
 func (*CreateTripRes) ProtoMessage() {}
 
 func (x *CreateTripRes) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[7]
+	mi := &file_trip_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -453,7 +567,7 @@ func (x *CreateTripRes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateTripRes.ProtoReflect.Descriptor instead.
 func (*CreateTripRes) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{7}
+	return file_trip_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CreateTripRes) GetTripID() string {
@@ -471,20 +585,40 @@ func (x *CreateTripRes) GetTrip() *Trip {
 }
 
 type Trip struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	SelectedFare  *RideFare              `protobuf:"bytes,2,opt,name=selectedFare,proto3" json:"selectedFare,omitempty"`
-	Route         *Route                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
-	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	UserID        string                 `protobuf:"bytes,5,opt,name=userID,proto3" json:"userID,omitempty"`
-	Driver        *TripDriver            `protobuf:"bytes,6,opt,name=driver,proto3" json:"driver,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SelectedFare *RideFare              `protobuf:"bytes,2,opt,name=selectedFare,proto3" json:"selectedFare,omitempty"`
+	Route        *Route                 `protobuf:"bytes,3,opt,name=route,proto3" json:"route,omitempty"`
+	Status       string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	UserID       string                 `protobuf:"bytes,5,opt,name=userID,proto3" json:"userID,omitempty"`
+	Driver       *TripDriver            `protobuf:"bytes,6,opt,name=driver,proto3" json:"driver,omitempty"`
+	Waypoints    []*Coordinate          `protobuf:"bytes,7,rep,name=waypoints,proto3" json:"waypoints,omitempty"`
+	// How many of the waypoints the driver has already reached
+	StopsReached int32 `protobuf:"varint,8,opt,name=stopsReached,proto3" json:"stopsReached,omitempty"`
+	// Pickup time of a scheduled (book-ahead) ride
+	ScheduledAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=scheduledAt,proto3" json:"scheduledAt,omitempty"`
+	// Everyone riding on the trip, the owner first. Only pool trips have more than one rider
+	Riders []*TripRider `protobuf:"bytes,10,rep,name=riders,proto3" json:"riders,omitempty"`
+	// Order in which the pool riders are picked up and dropped off
+	StopSequence []*TripStop `protobuf:"bytes,11,rep,name=stopSequence,proto3" json:"stopSequence,omitempty"`
+	Pickup       *Coordinate `protobuf:"bytes,12,opt,name=pickup,proto3" json:"pickup,omitempty"`
+	Destination  *Coordinate `protobuf:"bytes,13,opt,name=destination,proto3" json:"destination,omitempty"`
+	// What every rider paid, net of the refunds
+	Payments []*TripPayment `protobuf:"bytes,14,rep,name=payments,proto3" json:"payments,omitempty"`
+	// Price of the route actually driven, set once the trip is completed. Pool trips don't have one,
+	// their riders pay their own fares.
+	FinalFare *FinalFare `protobuf:"bytes,15,opt,name=finalFare,proto3" json:"finalFare,omitempty"`
+	// Tips the riders added once the trip completed, one per rider
+	Tips []*TripTip `protobuf:"bytes,16,rep,name=tips,proto3" json:"tips,omitempty"`
+	// Users the owner split the fare with, each of the accepted ones pays an equal share
+	SplitParticipants []*SplitParticipant `protobuf:"bytes,17,rep,name=splitParticipants,proto3" json:"splitParticipants,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Trip) Reset() {
 	*x = Trip{}
-	mi := &file_trip_proto_msgTypes[8]
+	mi := &file_trip_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -496,7 +630,7 @@ func (x *Trip) String() string {
 func (*Trip) ProtoMessage() {}
 
 func (x *Trip) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[8]
+	mi := &file_trip_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,7 +643,7 @@ func (x *Trip) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Trip.ProtoReflect.Descriptor instead.
 func (*Trip) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{8}
+	return file_trip_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Trip) GetId() string {
@@ -554,33 +688,109 @@ func (x *Trip) GetDriver() *TripDriver {
 	return nil
 }
 
-// Static driver object that will be used
-// to display the driver information in the trip details page
-type TripDriver struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	ProfilePicture string                 `protobuf:"bytes,3,opt,name=profilePicture,proto3" json:"profilePicture,omitempty"`
-	CarPlate       string                 `protobuf:"bytes,4,opt,name=carPlate,proto3" json:"carPlate,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *Trip) GetWaypoints() []*Coordinate {
+	if x != nil {
+		return x.Waypoints
+	}
+	return nil
 }
 
-func (x *TripDriver) Reset() {
-	*x = TripDriver{}
-	mi := &file_trip_proto_msgTypes[9]
+func (x *Trip) GetStopsReached() int32 {
+	if x != nil {
+		return x.StopsReached
+	}
+	return 0
+}
+
+func (x *Trip) GetScheduledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ScheduledAt
+	}
+	return nil
+}
+
+func (x *Trip) GetRiders() []*TripRider {
+	if x != nil {
+		return x.Riders
+	}
+	return nil
+}
+
+func (x *Trip) GetStopSequence() []*TripStop {
+	if x != nil {
+		return x.StopSequence
+	}
+	return nil
+}
+
+func (x *Trip) GetPickup() *Coordinate {
+	if x != nil {
+		return x.Pickup
+	}
+	return nil
+}
+
+func (x *Trip) GetDestination() *Coordinate {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
+func (x *Trip) GetPayments() []*TripPayment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *Trip) GetFinalFare() *FinalFare {
+	if x != nil {
+		return x.FinalFare
+	}
+	return nil
+}
+
+func (x *Trip) GetTips() []*TripTip {
+	if x != nil {
+		return x.Tips
+	}
+	return nil
+}
+
+func (x *Trip) GetSplitParticipants() []*SplitParticipant {
+	if x != nil {
+		return x.SplitParticipants
+	}
+	return nil
+}
+
+type TripTip struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	AmountInCents int64                  `protobuf:"varint,2,opt,name=amountInCents,proto3" json:"amountInCents,omitempty"`
+	// pending until the payment service charged it, then paid or failed
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripTip) Reset() {
+	*x = TripTip{}
+	mi := &file_trip_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TripDriver) String() string {
+func (x *TripTip) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TripDriver) ProtoMessage() {}
+func (*TripTip) ProtoMessage() {}
 
-func (x *TripDriver) ProtoReflect() protoreflect.Message {
-	mi := &file_trip_proto_msgTypes[9]
+func (x *TripTip) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -591,94 +801,1990 @@ func (x *TripDriver) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TripDriver.ProtoReflect.Descriptor instead.
-func (*TripDriver) Descriptor() ([]byte, []int) {
-	return file_trip_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use TripTip.ProtoReflect.Descriptor instead.
+func (*TripTip) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *TripDriver) GetId() string {
+func (x *TripTip) GetUserID() string {
 	if x != nil {
-		return x.Id
+		return x.UserID
 	}
 	return ""
 }
 
-func (x *TripDriver) GetName() string {
+func (x *TripTip) GetAmountInCents() int64 {
 	if x != nil {
-		return x.Name
+		return x.AmountInCents
+	}
+	return 0
+}
+
+func (x *TripTip) GetStatus() string {
+	if x != nil {
+		return x.Status
 	}
 	return ""
 }
 
-func (x *TripDriver) GetProfilePicture() string {
+func (x *TripTip) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ProfilePicture
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type TipTripReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	UserID string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	// A fixed amount, or a percentage of the fare of the rider
+	AmountInCents int64   `protobuf:"varint,3,opt,name=amountInCents,proto3" json:"amountInCents,omitempty"`
+	Percent       float64 `protobuf:"fixed64,4,opt,name=percent,proto3" json:"percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TipTripReq) Reset() {
+	*x = TipTripReq{}
+	mi := &file_trip_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TipTripReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TipTripReq) ProtoMessage() {}
+
+func (x *TipTripReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TipTripReq.ProtoReflect.Descriptor instead.
+func (*TipTripReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TipTripReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
 	}
 	return ""
 }
 
-func (x *TripDriver) GetCarPlate() string {
+func (x *TipTripReq) GetUserID() string {
 	if x != nil {
-		return x.CarPlate
+		return x.UserID
 	}
 	return ""
 }
 
-var File_trip_proto protoreflect.FileDescriptor
+func (x *TipTripReq) GetAmountInCents() int64 {
+	if x != nil {
+		return x.AmountInCents
+	}
+	return 0
+}
 
-const file_trip_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"trip.proto\x12\x04trip\"\x94\x01\n" +
-	"\x0ePreviewTripReq\x12\x16\n" +
-	"\x06userID\x18\x01 \x01(\tR\x06userID\x126\n" +
-	"\rstartLocation\x18\x02 \x01(\v2\x10.trip.CoordinateR\rstartLocation\x122\n" +
-	"\vendLocation\x18\x03 \x01(\v2\x10.trip.CoordinateR\vendLocation\"F\n" +
-	"\n" +
-	"Coordinate\x12\x1a\n" +
-	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+func (x *TipTripReq) GetPercent() float64 {
+	if x != nil {
+		return x.Percent
+	}
+	return 0
+}
+
+type FinalFare struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	TotalPriceInCents float64                `protobuf:"fixed64,1,opt,name=totalPriceInCents,proto3" json:"totalPriceInCents,omitempty"`
+	// Distance and duration of the route driven, in the units of the quoted route
+	Distance float64 `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	Duration float64 `protobuf:"fixed64,3,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Minutes the driver waited at the pickup and the stops
+	WaitMinutes  float64 `protobuf:"fixed64,4,opt,name=waitMinutes,proto3" json:"waitMinutes,omitempty"`
+	TollsInCents float64 `protobuf:"fixed64,5,opt,name=tollsInCents,proto3" json:"tollsInCents,omitempty"`
+	// Promo discount granted at the booking, already taken off the total
+	DiscountInCents float64 `protobuf:"fixed64,6,opt,name=discountInCents,proto3" json:"discountInCents,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FinalFare) Reset() {
+	*x = FinalFare{}
+	mi := &file_trip_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FinalFare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalFare) ProtoMessage() {}
+
+func (x *FinalFare) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalFare.ProtoReflect.Descriptor instead.
+func (*FinalFare) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FinalFare) GetTotalPriceInCents() float64 {
+	if x != nil {
+		return x.TotalPriceInCents
+	}
+	return 0
+}
+
+func (x *FinalFare) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *FinalFare) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *FinalFare) GetWaitMinutes() float64 {
+	if x != nil {
+		return x.WaitMinutes
+	}
+	return 0
+}
+
+func (x *FinalFare) GetTollsInCents() float64 {
+	if x != nil {
+		return x.TollsInCents
+	}
+	return 0
+}
+
+func (x *FinalFare) GetDiscountInCents() float64 {
+	if x != nil {
+		return x.DiscountInCents
+	}
+	return 0
+}
+
+type TripPayment struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserID          string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	AmountInCents   int64                  `protobuf:"varint,2,opt,name=amountInCents,proto3" json:"amountInCents,omitempty"`
+	RefundedInCents int64                  `protobuf:"varint,3,opt,name=refundedInCents,proto3" json:"refundedInCents,omitempty"`
+	NetInCents      int64                  `protobuf:"varint,4,opt,name=netInCents,proto3" json:"netInCents,omitempty"`
+	Currency        string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	// paid, partially_refunded or refunded
+	Status string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	// payerID is who was charged, the owner when they covered the share of a split participant
+	PayerID string `protobuf:"bytes,7,opt,name=payerID,proto3" json:"payerID,omitempty"`
+	// method is how the payer paid, ex. card, when the provider reported it
+	Method string `protobuf:"bytes,8,opt,name=method,proto3" json:"method,omitempty"`
+	// balanceInCents is the part of amountInCents over the hold, paid on its own session
+	BalanceInCents int64 `protobuf:"varint,9,opt,name=balanceInCents,proto3" json:"balanceInCents,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TripPayment) Reset() {
+	*x = TripPayment{}
+	mi := &file_trip_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripPayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripPayment) ProtoMessage() {}
+
+func (x *TripPayment) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripPayment.ProtoReflect.Descriptor instead.
+func (*TripPayment) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TripPayment) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *TripPayment) GetAmountInCents() int64 {
+	if x != nil {
+		return x.AmountInCents
+	}
+	return 0
+}
+
+func (x *TripPayment) GetRefundedInCents() int64 {
+	if x != nil {
+		return x.RefundedInCents
+	}
+	return 0
+}
+
+func (x *TripPayment) GetNetInCents() int64 {
+	if x != nil {
+		return x.NetInCents
+	}
+	return 0
+}
+
+func (x *TripPayment) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TripPayment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TripPayment) GetPayerID() string {
+	if x != nil {
+		return x.PayerID
+	}
+	return ""
+}
+
+func (x *TripPayment) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *TripPayment) GetBalanceInCents() int64 {
+	if x != nil {
+		return x.BalanceInCents
+	}
+	return 0
+}
+
+// SplitParticipant shares the fare of the trip with its owner
+type SplitParticipant struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserID string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	// invited, accepted, declined or expired
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// pending, paid, failed or covered_by_owner, once the accepted participants are charged their share
+	PaymentStatus string `protobuf:"bytes,3,opt,name=paymentStatus,proto3" json:"paymentStatus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SplitParticipant) Reset() {
+	*x = SplitParticipant{}
+	mi := &file_trip_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SplitParticipant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SplitParticipant) ProtoMessage() {}
+
+func (x *SplitParticipant) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SplitParticipant.ProtoReflect.Descriptor instead.
+func (*SplitParticipant) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SplitParticipant) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *SplitParticipant) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SplitParticipant) GetPaymentStatus() string {
+	if x != nil {
+		return x.PaymentStatus
+	}
+	return ""
+}
+
+type InviteToSplitReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// userID is the owner of the trip
+	UserID        string   `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	InviteeIDs    []string `protobuf:"bytes,3,rep,name=inviteeIDs,proto3" json:"inviteeIDs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InviteToSplitReq) Reset() {
+	*x = InviteToSplitReq{}
+	mi := &file_trip_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InviteToSplitReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteToSplitReq) ProtoMessage() {}
+
+func (x *InviteToSplitReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteToSplitReq.ProtoReflect.Descriptor instead.
+func (*InviteToSplitReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *InviteToSplitReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *InviteToSplitReq) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *InviteToSplitReq) GetInviteeIDs() []string {
+	if x != nil {
+		return x.InviteeIDs
+	}
+	return nil
+}
+
+type GetTripReceiptReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// The rider, or split participant, the receipt is for
+	UserID        string `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripReceiptReq) Reset() {
+	*x = GetTripReceiptReq{}
+	mi := &file_trip_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripReceiptReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripReceiptReq) ProtoMessage() {}
+
+func (x *GetTripReceiptReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripReceiptReq.ProtoReflect.Descriptor instead.
+func (*GetTripReceiptReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetTripReceiptReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *GetTripReceiptReq) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+// Receipt of a completed trip for one of its riders, the amounts are in cents
+type Receipt struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TripID      string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	UserID      string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	PackageSlug string                 `protobuf:"bytes,3,opt,name=packageSlug,proto3" json:"packageSlug,omitempty"`
+	Currency    string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	IssuedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=issuedAt,proto3" json:"issuedAt,omitempty"`
+	Fare        *FareBreakdown         `protobuf:"bytes,6,opt,name=fare,proto3" json:"fare,omitempty"`
+	// tipInCents is the tip the rider paid the driver
+	TipInCents float64 `protobuf:"fixed64,7,opt,name=tipInCents,proto3" json:"tipInCents,omitempty"`
+	// taxesInCents is the part of the fare going to taxes, already included in it
+	TaxesInCents float64 `protobuf:"fixed64,8,opt,name=taxesInCents,proto3" json:"taxesInCents,omitempty"`
+	// totalInCents is the fare, or the share of the rider when the fare was split, plus the tip
+	TotalInCents float64       `protobuf:"fixed64,9,opt,name=totalInCents,proto3" json:"totalInCents,omitempty"`
+	Route        *ReceiptRoute `protobuf:"bytes,10,opt,name=route,proto3" json:"route,omitempty"`
+	Driver       *TripDriver   `protobuf:"bytes,11,opt,name=driver,proto3" json:"driver,omitempty"`
+	// Unset until the payment service reported the payment of the rider
+	Payment       *TripPayment `protobuf:"bytes,12,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_trip_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Receipt) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *Receipt) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *Receipt) GetPackageSlug() string {
+	if x != nil {
+		return x.PackageSlug
+	}
+	return ""
+}
+
+func (x *Receipt) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Receipt) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+func (x *Receipt) GetFare() *FareBreakdown {
+	if x != nil {
+		return x.Fare
+	}
+	return nil
+}
+
+func (x *Receipt) GetTipInCents() float64 {
+	if x != nil {
+		return x.TipInCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetTaxesInCents() float64 {
+	if x != nil {
+		return x.TaxesInCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetTotalInCents() float64 {
+	if x != nil {
+		return x.TotalInCents
+	}
+	return 0
+}
+
+func (x *Receipt) GetRoute() *ReceiptRoute {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *Receipt) GetDriver() *TripDriver {
+	if x != nil {
+		return x.Driver
+	}
+	return nil
+}
+
+func (x *Receipt) GetPayment() *TripPayment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+// ExportTripReceiptRes is the receipt rendered as a printable HTML document
+type ExportTripReceiptRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Html          []byte                 `protobuf:"bytes,2,opt,name=html,proto3" json:"html,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportTripReceiptRes) Reset() {
+	*x = ExportTripReceiptRes{}
+	mi := &file_trip_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportTripReceiptRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportTripReceiptRes) ProtoMessage() {}
+
+func (x *ExportTripReceiptRes) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportTripReceiptRes.ProtoReflect.Descriptor instead.
+func (*ExportTripReceiptRes) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExportTripReceiptRes) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *ExportTripReceiptRes) GetHtml() []byte {
+	if x != nil {
+		return x.Html
+	}
+	return nil
+}
+
+type FareBreakdown struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	BaseInCents     float64                `protobuf:"fixed64,1,opt,name=baseInCents,proto3" json:"baseInCents,omitempty"`
+	DistanceInCents float64                `protobuf:"fixed64,2,opt,name=distanceInCents,proto3" json:"distanceInCents,omitempty"`
+	TimeInCents     float64                `protobuf:"fixed64,3,opt,name=timeInCents,proto3" json:"timeInCents,omitempty"`
+	StopsInCents    float64                `protobuf:"fixed64,4,opt,name=stopsInCents,proto3" json:"stopsInCents,omitempty"`
+	WaitingInCents  float64                `protobuf:"fixed64,5,opt,name=waitingInCents,proto3" json:"waitingInCents,omitempty"`
+	TollsInCents    float64                `protobuf:"fixed64,6,opt,name=tollsInCents,proto3" json:"tollsInCents,omitempty"`
+	SurgeInCents    float64                `protobuf:"fixed64,7,opt,name=surgeInCents,proto3" json:"surgeInCents,omitempty"`
+	// The discounts are positive amounts taken off the fare
+	PoolDiscountInCents  float64 `protobuf:"fixed64,8,opt,name=poolDiscountInCents,proto3" json:"poolDiscountInCents,omitempty"`
+	PromoCode            string  `protobuf:"bytes,9,opt,name=promoCode,proto3" json:"promoCode,omitempty"`
+	PromoDiscountInCents float64 `protobuf:"fixed64,10,opt,name=promoDiscountInCents,proto3" json:"promoDiscountInCents,omitempty"`
+	TotalPriceInCents    float64 `protobuf:"fixed64,11,opt,name=totalPriceInCents,proto3" json:"totalPriceInCents,omitempty"`
+	// shareInCents is what the rider owes of the total when the fare was split
+	ShareInCents  float64 `protobuf:"fixed64,12,opt,name=shareInCents,proto3" json:"shareInCents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FareBreakdown) Reset() {
+	*x = FareBreakdown{}
+	mi := &file_trip_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FareBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FareBreakdown) ProtoMessage() {}
+
+func (x *FareBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FareBreakdown.ProtoReflect.Descriptor instead.
+func (*FareBreakdown) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FareBreakdown) GetBaseInCents() float64 {
+	if x != nil {
+		return x.BaseInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetDistanceInCents() float64 {
+	if x != nil {
+		return x.DistanceInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetTimeInCents() float64 {
+	if x != nil {
+		return x.TimeInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetStopsInCents() float64 {
+	if x != nil {
+		return x.StopsInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetWaitingInCents() float64 {
+	if x != nil {
+		return x.WaitingInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetTollsInCents() float64 {
+	if x != nil {
+		return x.TollsInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetSurgeInCents() float64 {
+	if x != nil {
+		return x.SurgeInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetPoolDiscountInCents() float64 {
+	if x != nil {
+		return x.PoolDiscountInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *FareBreakdown) GetPromoDiscountInCents() float64 {
+	if x != nil {
+		return x.PromoDiscountInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetTotalPriceInCents() float64 {
+	if x != nil {
+		return x.TotalPriceInCents
+	}
+	return 0
+}
+
+func (x *FareBreakdown) GetShareInCents() float64 {
+	if x != nil {
+		return x.ShareInCents
+	}
+	return 0
+}
+
+type ReceiptRoute struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Pickup      *Coordinate            `protobuf:"bytes,1,opt,name=pickup,proto3" json:"pickup,omitempty"`
+	Destination *Coordinate            `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Waypoints   []*Coordinate          `protobuf:"bytes,3,rep,name=waypoints,proto3" json:"waypoints,omitempty"`
+	// Distance and duration of the route driven, as they were priced
+	Distance      float64                `protobuf:"fixed64,4,opt,name=distance,proto3" json:"distance,omitempty"`
+	Duration      float64                `protobuf:"fixed64,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=completedAt,proto3" json:"completedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiptRoute) Reset() {
+	*x = ReceiptRoute{}
+	mi := &file_trip_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiptRoute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiptRoute) ProtoMessage() {}
+
+func (x *ReceiptRoute) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiptRoute.ProtoReflect.Descriptor instead.
+func (*ReceiptRoute) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReceiptRoute) GetPickup() *Coordinate {
+	if x != nil {
+		return x.Pickup
+	}
+	return nil
+}
+
+func (x *ReceiptRoute) GetDestination() *Coordinate {
+	if x != nil {
+		return x.Destination
+	}
+	return nil
+}
+
+func (x *ReceiptRoute) GetWaypoints() []*Coordinate {
+	if x != nil {
+		return x.Waypoints
+	}
+	return nil
+}
+
+func (x *ReceiptRoute) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *ReceiptRoute) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *ReceiptRoute) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type GetTripReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// userID has to be one of the riders of the trip
+	UserID        string `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTripReq) Reset() {
+	*x = GetTripReq{}
+	mi := &file_trip_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTripReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripReq) ProtoMessage() {}
+
+func (x *GetTripReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripReq.ProtoReflect.Descriptor instead.
+func (*GetTripReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetTripReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *GetTripReq) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+type TripRider struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Fare          *RideFare              `protobuf:"bytes,2,opt,name=fare,proto3" json:"fare,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripRider) Reset() {
+	*x = TripRider{}
+	mi := &file_trip_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripRider) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripRider) ProtoMessage() {}
+
+func (x *TripRider) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripRider.ProtoReflect.Descriptor instead.
+func (*TripRider) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TripRider) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *TripRider) GetFare() *RideFare {
+	if x != nil {
+		return x.Fare
+	}
+	return nil
+}
+
+type TripStop struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserID string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	// pickup or dropoff
+	Type          string      `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Location      *Coordinate `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Completed     bool        `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripStop) Reset() {
+	*x = TripStop{}
+	mi := &file_trip_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripStop) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripStop) ProtoMessage() {}
+
+func (x *TripStop) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripStop.ProtoReflect.Descriptor instead.
+func (*TripStop) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TripStop) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *TripStop) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TripStop) GetLocation() *Coordinate {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *TripStop) GetCompleted() bool {
+	if x != nil {
+		return x.Completed
+	}
+	return false
+}
+
+type ReachTripStopReq struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TripID   string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	DriverID string                 `protobuf:"bytes,2,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// Index of the waypoint the driver has reached
+	StopIndex     int32 `protobuf:"varint,3,opt,name=stopIndex,proto3" json:"stopIndex,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReachTripStopReq) Reset() {
+	*x = ReachTripStopReq{}
+	mi := &file_trip_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReachTripStopReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReachTripStopReq) ProtoMessage() {}
+
+func (x *ReachTripStopReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReachTripStopReq.ProtoReflect.Descriptor instead.
+func (*ReachTripStopReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReachTripStopReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *ReachTripStopReq) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *ReachTripStopReq) GetStopIndex() int32 {
+	if x != nil {
+		return x.StopIndex
+	}
+	return 0
+}
+
+// Static driver object that will be used
+// to display the driver information in the trip details page
+type TripDriver struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProfilePicture string                 `protobuf:"bytes,3,opt,name=profilePicture,proto3" json:"profilePicture,omitempty"`
+	CarPlate       string                 `protobuf:"bytes,4,opt,name=carPlate,proto3" json:"carPlate,omitempty"`
+	SeatCapacity   int32                  `protobuf:"varint,5,opt,name=seatCapacity,proto3" json:"seatCapacity,omitempty"`
+	// Unset for the drivers without a profile
+	CarMake       string `protobuf:"bytes,6,opt,name=carMake,proto3" json:"carMake,omitempty"`
+	CarModel      string `protobuf:"bytes,7,opt,name=carModel,proto3" json:"carModel,omitempty"`
+	CarColor      string `protobuf:"bytes,8,opt,name=carColor,proto3" json:"carColor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TripDriver) Reset() {
+	*x = TripDriver{}
+	mi := &file_trip_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TripDriver) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TripDriver) ProtoMessage() {}
+
+func (x *TripDriver) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TripDriver.ProtoReflect.Descriptor instead.
+func (*TripDriver) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TripDriver) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *TripDriver) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TripDriver) GetProfilePicture() string {
+	if x != nil {
+		return x.ProfilePicture
+	}
+	return ""
+}
+
+func (x *TripDriver) GetCarPlate() string {
+	if x != nil {
+		return x.CarPlate
+	}
+	return ""
+}
+
+func (x *TripDriver) GetSeatCapacity() int32 {
+	if x != nil {
+		return x.SeatCapacity
+	}
+	return 0
+}
+
+func (x *TripDriver) GetCarMake() string {
+	if x != nil {
+		return x.CarMake
+	}
+	return ""
+}
+
+func (x *TripDriver) GetCarModel() string {
+	if x != nil {
+		return x.CarModel
+	}
+	return ""
+}
+
+func (x *TripDriver) GetCarColor() string {
+	if x != nil {
+		return x.CarColor
+	}
+	return ""
+}
+
+type CancelTripReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TripID        string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	UserID        string                 `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTripReq) Reset() {
+	*x = CancelTripReq{}
+	mi := &file_trip_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTripReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTripReq) ProtoMessage() {}
+
+func (x *CancelTripReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTripReq.ProtoReflect.Descriptor instead.
+func (*CancelTripReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CancelTripReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *CancelTripReq) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+type CancelTripRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trip          *Trip                  `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelTripRes) Reset() {
+	*x = CancelTripRes{}
+	mi := &file_trip_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelTripRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelTripRes) ProtoMessage() {}
+
+func (x *CancelTripRes) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelTripRes.ProtoReflect.Descriptor instead.
+func (*CancelTripRes) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CancelTripRes) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+type CompleteTripReq struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TripID   string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	DriverID string                 `protobuf:"bytes,2,opt,name=driverID,proto3" json:"driverID,omitempty"`
+	// What the driver reports of the route driven, the quoted route is priced for the values left to 0
+	Distance      float64 `protobuf:"fixed64,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	Duration      float64 `protobuf:"fixed64,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	WaitMinutes   float64 `protobuf:"fixed64,5,opt,name=waitMinutes,proto3" json:"waitMinutes,omitempty"`
+	TollsInCents  float64 `protobuf:"fixed64,6,opt,name=tollsInCents,proto3" json:"tollsInCents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteTripReq) Reset() {
+	*x = CompleteTripReq{}
+	mi := &file_trip_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteTripReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteTripReq) ProtoMessage() {}
+
+func (x *CompleteTripReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteTripReq.ProtoReflect.Descriptor instead.
+func (*CompleteTripReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CompleteTripReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *CompleteTripReq) GetDriverID() string {
+	if x != nil {
+		return x.DriverID
+	}
+	return ""
+}
+
+func (x *CompleteTripReq) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *CompleteTripReq) GetDuration() float64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *CompleteTripReq) GetWaitMinutes() float64 {
+	if x != nil {
+		return x.WaitMinutes
+	}
+	return 0
+}
+
+func (x *CompleteTripReq) GetTollsInCents() float64 {
+	if x != nil {
+		return x.TollsInCents
+	}
+	return 0
+}
+
+type RateTripReq struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TripID string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	// The rider or the driver of the trip
+	UserID string `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	// The rider a driver rates, needed for pool trips only. Riders always rate the driver.
+	RateeID string `protobuf:"bytes,3,opt,name=rateeID,proto3" json:"rateeID,omitempty"`
+	// From 1 to 5
+	Score         int32    `protobuf:"varint,4,opt,name=score,proto3" json:"score,omitempty"`
+	Tags          []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	Comment       string   `protobuf:"bytes,6,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateTripReq) Reset() {
+	*x = RateTripReq{}
+	mi := &file_trip_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateTripReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateTripReq) ProtoMessage() {}
+
+func (x *RateTripReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateTripReq.ProtoReflect.Descriptor instead.
+func (*RateTripReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RateTripReq) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *RateTripReq) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *RateTripReq) GetRateeID() string {
+	if x != nil {
+		return x.RateeID
+	}
+	return ""
+}
+
+func (x *RateTripReq) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *RateTripReq) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *RateTripReq) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type Rating struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	TripID  string                 `protobuf:"bytes,1,opt,name=tripID,proto3" json:"tripID,omitempty"`
+	RaterID string                 `protobuf:"bytes,2,opt,name=raterID,proto3" json:"raterID,omitempty"`
+	RateeID string                 `protobuf:"bytes,3,opt,name=rateeID,proto3" json:"rateeID,omitempty"`
+	// driver or rider
+	RateeRole     string                 `protobuf:"bytes,4,opt,name=rateeRole,proto3" json:"rateeRole,omitempty"`
+	Score         int32                  `protobuf:"varint,5,opt,name=score,proto3" json:"score,omitempty"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Comment       string                 `protobuf:"bytes,7,opt,name=comment,proto3" json:"comment,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rating) Reset() {
+	*x = Rating{}
+	mi := &file_trip_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rating) ProtoMessage() {}
+
+func (x *Rating) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rating.ProtoReflect.Descriptor instead.
+func (*Rating) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *Rating) GetTripID() string {
+	if x != nil {
+		return x.TripID
+	}
+	return ""
+}
+
+func (x *Rating) GetRaterID() string {
+	if x != nil {
+		return x.RaterID
+	}
+	return ""
+}
+
+func (x *Rating) GetRateeID() string {
+	if x != nil {
+		return x.RateeID
+	}
+	return ""
+}
+
+func (x *Rating) GetRateeRole() string {
+	if x != nil {
+		return x.RateeRole
+	}
+	return ""
+}
+
+func (x *Rating) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Rating) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Rating) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Rating) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type RatingSummary struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserID string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	// Rolling average of the latest ratings
+	Average       float64 `protobuf:"fixed64,2,opt,name=average,proto3" json:"average,omitempty"`
+	Count         int32   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RatingSummary) Reset() {
+	*x = RatingSummary{}
+	mi := &file_trip_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RatingSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RatingSummary) ProtoMessage() {}
+
+func (x *RatingSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RatingSummary.ProtoReflect.Descriptor instead.
+func (*RatingSummary) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RatingSummary) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+func (x *RatingSummary) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+func (x *RatingSummary) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type RateTripRes struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rating        *Rating                `protobuf:"bytes,1,opt,name=rating,proto3" json:"rating,omitempty"`
+	Summary       *RatingSummary         `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateTripRes) Reset() {
+	*x = RateTripRes{}
+	mi := &file_trip_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateTripRes) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateTripRes) ProtoMessage() {}
+
+func (x *RateTripRes) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateTripRes.ProtoReflect.Descriptor instead.
+func (*RateTripRes) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RateTripRes) GetRating() *Rating {
+	if x != nil {
+		return x.Rating
+	}
+	return nil
+}
+
+func (x *RateTripRes) GetSummary() *RatingSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type GetRatingSummaryReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserID        string                 `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatingSummaryReq) Reset() {
+	*x = GetRatingSummaryReq{}
+	mi := &file_trip_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatingSummaryReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatingSummaryReq) ProtoMessage() {}
+
+func (x *GetRatingSummaryReq) ProtoReflect() protoreflect.Message {
+	mi := &file_trip_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatingSummaryReq.ProtoReflect.Descriptor instead.
+func (*GetRatingSummaryReq) Descriptor() ([]byte, []int) {
+	return file_trip_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetRatingSummaryReq) GetUserID() string {
+	if x != nil {
+		return x.UserID
+	}
+	return ""
+}
+
+var File_trip_proto protoreflect.FileDescriptor
+
+const file_trip_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"trip.proto\x12\x04trip\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe2\x01\n" +
+	"\x0ePreviewTripReq\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x126\n" +
+	"\rstartLocation\x18\x02 \x01(\v2\x10.trip.CoordinateR\rstartLocation\x122\n" +
+	"\vendLocation\x18\x03 \x01(\v2\x10.trip.CoordinateR\vendLocation\x12.\n" +
+	"\twaypoints\x18\x04 \x03(\v2\x10.trip.CoordinateR\twaypoints\x12\x1c\n" +
+	"\tpromoCode\x18\x05 \x01(\tR\tpromoCode\"F\n" +
+	"\n" +
+	"Coordinate\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
 	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"y\n" +
 	"\x0ePreviewTripRes\x12\x16\n" +
 	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12!\n" +
 	"\x05route\x18\x02 \x01(\v2\v.trip.RouteR\x05route\x12,\n" +
-	"\trideFares\x18\x03 \x03(\v2\x0e.trip.RideFareR\trideFares\"k\n" +
+	"\trideFares\x18\x03 \x03(\v2\x0e.trip.RideFareR\trideFares\"\x8f\x01\n" +
 	"\x05Route\x12*\n" +
 	"\bgeometry\x18\x01 \x03(\v2\x0e.trip.GeometryR\bgeometry\x12\x1a\n" +
 	"\bdistance\x18\x02 \x01(\x01R\bdistance\x12\x1a\n" +
-	"\bduration\x18\x03 \x01(\x01R\bduration\">\n" +
+	"\bduration\x18\x03 \x01(\x01R\bduration\x12\"\n" +
+	"\x04legs\x18\x04 \x03(\v2\x0e.trip.RouteLegR\x04legs\"B\n" +
+	"\bRouteLeg\x12\x1a\n" +
+	"\bdistance\x18\x01 \x01(\x01R\bdistance\x12\x1a\n" +
+	"\bduration\x18\x02 \x01(\x01R\bduration\">\n" +
 	"\bGeometry\x122\n" +
-	"\vcoordinates\x18\x01 \x03(\v2\x10.trip.CoordinateR\vcoordinates\"\x82\x01\n" +
+	"\vcoordinates\x18\x01 \x03(\v2\x10.trip.CoordinateR\vcoordinates\"\xfe\x01\n" +
 	"\bRideFare\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06userID\x18\x02 \x01(\tR\x06userID\x12 \n" +
 	"\vpackageSlug\x18\x03 \x01(\tR\vpackageSlug\x12,\n" +
-	"\x11totalPriceInCents\x18\x04 \x01(\x01R\x11totalPriceInCents\"G\n" +
+	"\x11totalPriceInCents\x18\x04 \x01(\x01R\x11totalPriceInCents\x12\x1c\n" +
+	"\tpromoCode\x18\x05 \x01(\tR\tpromoCode\x122\n" +
+	"\x14originalPriceInCents\x18\x06 \x01(\x01R\x14originalPriceInCents\x12(\n" +
+	"\x0fdiscountInCents\x18\a \x01(\x01R\x0fdiscountInCents\"\x85\x01\n" +
 	"\rCreateTripReq\x12\x1e\n" +
 	"\n" +
 	"rideFareID\x18\x01 \x01(\tR\n" +
 	"rideFareID\x12\x16\n" +
-	"\x06userID\x18\x02 \x01(\tR\x06userID\"G\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12<\n" +
+	"\vscheduledAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\"G\n" +
 	"\rCreateTripRes\x12\x16\n" +
 	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x1e\n" +
 	"\x04trip\x18\x02 \x01(\v2\n" +
-	".trip.TripR\x04trip\"\xc7\x01\n" +
+	".trip.TripR\x04trip\"\xdb\x05\n" +
 	"\x04Trip\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x122\n" +
 	"\fselectedFare\x18\x02 \x01(\v2\x0e.trip.RideFareR\fselectedFare\x12!\n" +
 	"\x05route\x18\x03 \x01(\v2\v.trip.RouteR\x05route\x12\x16\n" +
 	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
 	"\x06userID\x18\x05 \x01(\tR\x06userID\x12(\n" +
-	"\x06driver\x18\x06 \x01(\v2\x10.trip.TripDriverR\x06driver\"t\n" +
+	"\x06driver\x18\x06 \x01(\v2\x10.trip.TripDriverR\x06driver\x12.\n" +
+	"\twaypoints\x18\a \x03(\v2\x10.trip.CoordinateR\twaypoints\x12\"\n" +
+	"\fstopsReached\x18\b \x01(\x05R\fstopsReached\x12<\n" +
+	"\vscheduledAt\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\vscheduledAt\x12'\n" +
+	"\x06riders\x18\n" +
+	" \x03(\v2\x0f.trip.TripRiderR\x06riders\x122\n" +
+	"\fstopSequence\x18\v \x03(\v2\x0e.trip.TripStopR\fstopSequence\x12(\n" +
+	"\x06pickup\x18\f \x01(\v2\x10.trip.CoordinateR\x06pickup\x122\n" +
+	"\vdestination\x18\r \x01(\v2\x10.trip.CoordinateR\vdestination\x12-\n" +
+	"\bpayments\x18\x0e \x03(\v2\x11.trip.TripPaymentR\bpayments\x12-\n" +
+	"\tfinalFare\x18\x0f \x01(\v2\x0f.trip.FinalFareR\tfinalFare\x12!\n" +
+	"\x04tips\x18\x10 \x03(\v2\r.trip.TripTipR\x04tips\x12D\n" +
+	"\x11splitParticipants\x18\x11 \x03(\v2\x16.trip.SplitParticipantR\x11splitParticipants\"\x99\x01\n" +
+	"\aTripTip\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12$\n" +
+	"\ramountInCents\x18\x02 \x01(\x03R\ramountInCents\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x128\n" +
+	"\tcreatedAt\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"|\n" +
+	"\n" +
+	"TipTripReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12$\n" +
+	"\ramountInCents\x18\x03 \x01(\x03R\ramountInCents\x12\x18\n" +
+	"\apercent\x18\x04 \x01(\x01R\apercent\"\xe1\x01\n" +
+	"\tFinalFare\x12,\n" +
+	"\x11totalPriceInCents\x18\x01 \x01(\x01R\x11totalPriceInCents\x12\x1a\n" +
+	"\bdistance\x18\x02 \x01(\x01R\bdistance\x12\x1a\n" +
+	"\bduration\x18\x03 \x01(\x01R\bduration\x12 \n" +
+	"\vwaitMinutes\x18\x04 \x01(\x01R\vwaitMinutes\x12\"\n" +
+	"\ftollsInCents\x18\x05 \x01(\x01R\ftollsInCents\x12(\n" +
+	"\x0fdiscountInCents\x18\x06 \x01(\x01R\x0fdiscountInCents\"\xa3\x02\n" +
+	"\vTripPayment\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12$\n" +
+	"\ramountInCents\x18\x02 \x01(\x03R\ramountInCents\x12(\n" +
+	"\x0frefundedInCents\x18\x03 \x01(\x03R\x0frefundedInCents\x12\x1e\n" +
+	"\n" +
+	"netInCents\x18\x04 \x01(\x03R\n" +
+	"netInCents\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x18\n" +
+	"\apayerID\x18\a \x01(\tR\apayerID\x12\x16\n" +
+	"\x06method\x18\b \x01(\tR\x06method\x12&\n" +
+	"\x0ebalanceInCents\x18\t \x01(\x03R\x0ebalanceInCents\"h\n" +
+	"\x10SplitParticipant\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12$\n" +
+	"\rpaymentStatus\x18\x03 \x01(\tR\rpaymentStatus\"b\n" +
+	"\x10InviteToSplitReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12\x1e\n" +
+	"\n" +
+	"inviteeIDs\x18\x03 \x03(\tR\n" +
+	"inviteeIDs\"C\n" +
+	"\x11GetTripReceiptReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\"\xc1\x03\n" +
+	"\aReceipt\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12 \n" +
+	"\vpackageSlug\x18\x03 \x01(\tR\vpackageSlug\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x126\n" +
+	"\bissuedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\x12'\n" +
+	"\x04fare\x18\x06 \x01(\v2\x13.trip.FareBreakdownR\x04fare\x12\x1e\n" +
+	"\n" +
+	"tipInCents\x18\a \x01(\x01R\n" +
+	"tipInCents\x12\"\n" +
+	"\ftaxesInCents\x18\b \x01(\x01R\ftaxesInCents\x12\"\n" +
+	"\ftotalInCents\x18\t \x01(\x01R\ftotalInCents\x12(\n" +
+	"\x05route\x18\n" +
+	" \x01(\v2\x12.trip.ReceiptRouteR\x05route\x12(\n" +
+	"\x06driver\x18\v \x01(\v2\x10.trip.TripDriverR\x06driver\x12+\n" +
+	"\apayment\x18\f \x01(\v2\x11.trip.TripPaymentR\apayment\"F\n" +
+	"\x14ExportTripReceiptRes\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x12\n" +
+	"\x04html\x18\x02 \x01(\fR\x04html\"\xe7\x03\n" +
+	"\rFareBreakdown\x12 \n" +
+	"\vbaseInCents\x18\x01 \x01(\x01R\vbaseInCents\x12(\n" +
+	"\x0fdistanceInCents\x18\x02 \x01(\x01R\x0fdistanceInCents\x12 \n" +
+	"\vtimeInCents\x18\x03 \x01(\x01R\vtimeInCents\x12\"\n" +
+	"\fstopsInCents\x18\x04 \x01(\x01R\fstopsInCents\x12&\n" +
+	"\x0ewaitingInCents\x18\x05 \x01(\x01R\x0ewaitingInCents\x12\"\n" +
+	"\ftollsInCents\x18\x06 \x01(\x01R\ftollsInCents\x12\"\n" +
+	"\fsurgeInCents\x18\a \x01(\x01R\fsurgeInCents\x120\n" +
+	"\x13poolDiscountInCents\x18\b \x01(\x01R\x13poolDiscountInCents\x12\x1c\n" +
+	"\tpromoCode\x18\t \x01(\tR\tpromoCode\x122\n" +
+	"\x14promoDiscountInCents\x18\n" +
+	" \x01(\x01R\x14promoDiscountInCents\x12,\n" +
+	"\x11totalPriceInCents\x18\v \x01(\x01R\x11totalPriceInCents\x12\"\n" +
+	"\fshareInCents\x18\f \x01(\x01R\fshareInCents\"\x92\x02\n" +
+	"\fReceiptRoute\x12(\n" +
+	"\x06pickup\x18\x01 \x01(\v2\x10.trip.CoordinateR\x06pickup\x122\n" +
+	"\vdestination\x18\x02 \x01(\v2\x10.trip.CoordinateR\vdestination\x12.\n" +
+	"\twaypoints\x18\x03 \x03(\v2\x10.trip.CoordinateR\twaypoints\x12\x1a\n" +
+	"\bdistance\x18\x04 \x01(\x01R\bdistance\x12\x1a\n" +
+	"\bduration\x18\x05 \x01(\x01R\bduration\x12<\n" +
+	"\vcompletedAt\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"<\n" +
+	"\n" +
+	"GetTripReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\"G\n" +
+	"\tTripRider\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12\"\n" +
+	"\x04fare\x18\x02 \x01(\v2\x0e.trip.RideFareR\x04fare\"\x82\x01\n" +
+	"\bTripStop\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12,\n" +
+	"\blocation\x18\x03 \x01(\v2\x10.trip.CoordinateR\blocation\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\bR\tcompleted\"d\n" +
+	"\x10ReachTripStopReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x1a\n" +
+	"\bdriverID\x18\x02 \x01(\tR\bdriverID\x12\x1c\n" +
+	"\tstopIndex\x18\x03 \x01(\x05R\tstopIndex\"\xea\x01\n" +
 	"\n" +
 	"TripDriver\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12&\n" +
 	"\x0eprofilePicture\x18\x03 \x01(\tR\x0eprofilePicture\x12\x1a\n" +
-	"\bcarPlate\x18\x04 \x01(\tR\bcarPlate2\x80\x01\n" +
+	"\bcarPlate\x18\x04 \x01(\tR\bcarPlate\x12\"\n" +
+	"\fseatCapacity\x18\x05 \x01(\x05R\fseatCapacity\x12\x18\n" +
+	"\acarMake\x18\x06 \x01(\tR\acarMake\x12\x1a\n" +
+	"\bcarModel\x18\a \x01(\tR\bcarModel\x12\x1a\n" +
+	"\bcarColor\x18\b \x01(\tR\bcarColor\"?\n" +
+	"\rCancelTripReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\"/\n" +
+	"\rCancelTripRes\x12\x1e\n" +
+	"\x04trip\x18\x01 \x01(\v2\n" +
+	".trip.TripR\x04trip\"\xc3\x01\n" +
+	"\x0fCompleteTripReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x1a\n" +
+	"\bdriverID\x18\x02 \x01(\tR\bdriverID\x12\x1a\n" +
+	"\bdistance\x18\x03 \x01(\x01R\bdistance\x12\x1a\n" +
+	"\bduration\x18\x04 \x01(\x01R\bduration\x12 \n" +
+	"\vwaitMinutes\x18\x05 \x01(\x01R\vwaitMinutes\x12\"\n" +
+	"\ftollsInCents\x18\x06 \x01(\x01R\ftollsInCents\"\x9b\x01\n" +
+	"\vRateTripReq\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\tR\x06userID\x12\x18\n" +
+	"\arateeID\x18\x03 \x01(\tR\arateeID\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x05R\x05score\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x18\n" +
+	"\acomment\x18\x06 \x01(\tR\acomment\"\xf0\x01\n" +
+	"\x06Rating\x12\x16\n" +
+	"\x06tripID\x18\x01 \x01(\tR\x06tripID\x12\x18\n" +
+	"\araterID\x18\x02 \x01(\tR\araterID\x12\x18\n" +
+	"\arateeID\x18\x03 \x01(\tR\arateeID\x12\x1c\n" +
+	"\trateeRole\x18\x04 \x01(\tR\trateeRole\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x05R\x05score\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x18\n" +
+	"\acomment\x18\a \x01(\tR\acomment\x128\n" +
+	"\tcreatedAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"W\n" +
+	"\rRatingSummary\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID\x12\x18\n" +
+	"\aaverage\x18\x02 \x01(\x01R\aaverage\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\"b\n" +
+	"\vRateTripRes\x12$\n" +
+	"\x06rating\x18\x01 \x01(\v2\f.trip.RatingR\x06rating\x12-\n" +
+	"\asummary\x18\x02 \x01(\v2\x13.trip.RatingSummaryR\asummary\"-\n" +
+	"\x13GetRatingSummaryReq\x12\x16\n" +
+	"\x06userID\x18\x01 \x01(\tR\x06userID2\xa4\x05\n" +
 	"\vTripService\x129\n" +
 	"\vPreviewTrip\x12\x14.trip.PreviewTripReq\x1a\x14.trip.PreviewTripRes\x126\n" +
 	"\n" +
-	"CreateTrip\x12\x13.trip.CreateTripReq\x1a\x13.trip.CreateTripResB\x18Z\x16shared/proto/trip;tripb\x06proto3"
+	"CreateTrip\x12\x13.trip.CreateTripReq\x1a\x13.trip.CreateTripRes\x123\n" +
+	"\rReachTripStop\x12\x16.trip.ReachTripStopReq\x1a\n" +
+	".trip.Trip\x126\n" +
+	"\n" +
+	"CancelTrip\x12\x13.trip.CancelTripReq\x1a\x13.trip.CancelTripRes\x121\n" +
+	"\fCompleteTrip\x12\x15.trip.CompleteTripReq\x1a\n" +
+	".trip.Trip\x120\n" +
+	"\bRateTrip\x12\x11.trip.RateTripReq\x1a\x11.trip.RateTripRes\x12B\n" +
+	"\x10GetRatingSummary\x12\x19.trip.GetRatingSummaryReq\x1a\x13.trip.RatingSummary\x12'\n" +
+	"\aGetTrip\x12\x10.trip.GetTripReq\x1a\n" +
+	".trip.Trip\x12*\n" +
+	"\aTipTrip\x12\x10.trip.TipTripReq\x1a\r.trip.TripTip\x123\n" +
+	"\rInviteToSplit\x12\x16.trip.InviteToSplitReq\x1a\n" +
+	".trip.Trip\x128\n" +
+	"\x0eGetTripReceipt\x12\x17.trip.GetTripReceiptReq\x1a\r.trip.Receipt\x12H\n" +
+	"\x11ExportTripReceipt\x12\x17.trip.GetTripReceiptReq\x1a\x1a.trip.ExportTripReceiptResB\x18Z\x16shared/proto/trip;tripb\x06proto3"
 
 var (
 	file_trip_proto_rawDescOnce sync.Once
@@ -692,39 +2798,113 @@ func file_trip_proto_rawDescGZIP() []byte {
 	return file_trip_proto_rawDescData
 }
 
-var file_trip_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_trip_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_trip_proto_goTypes = []any{
-	(*PreviewTripReq)(nil), // 0: trip.PreviewTripReq
-	(*Coordinate)(nil),     // 1: trip.Coordinate
-	(*PreviewTripRes)(nil), // 2: trip.PreviewTripRes
-	(*Route)(nil),          // 3: trip.Route
-	(*Geometry)(nil),       // 4: trip.Geometry
-	(*RideFare)(nil),       // 5: trip.RideFare
-	(*CreateTripReq)(nil),  // 6: trip.CreateTripReq
-	(*CreateTripRes)(nil),  // 7: trip.CreateTripRes
-	(*Trip)(nil),           // 8: trip.Trip
-	(*TripDriver)(nil),     // 9: trip.TripDriver
+	(*PreviewTripReq)(nil),        // 0: trip.PreviewTripReq
+	(*Coordinate)(nil),            // 1: trip.Coordinate
+	(*PreviewTripRes)(nil),        // 2: trip.PreviewTripRes
+	(*Route)(nil),                 // 3: trip.Route
+	(*RouteLeg)(nil),              // 4: trip.RouteLeg
+	(*Geometry)(nil),              // 5: trip.Geometry
+	(*RideFare)(nil),              // 6: trip.RideFare
+	(*CreateTripReq)(nil),         // 7: trip.CreateTripReq
+	(*CreateTripRes)(nil),         // 8: trip.CreateTripRes
+	(*Trip)(nil),                  // 9: trip.Trip
+	(*TripTip)(nil),               // 10: trip.TripTip
+	(*TipTripReq)(nil),            // 11: trip.TipTripReq
+	(*FinalFare)(nil),             // 12: trip.FinalFare
+	(*TripPayment)(nil),           // 13: trip.TripPayment
+	(*SplitParticipant)(nil),      // 14: trip.SplitParticipant
+	(*InviteToSplitReq)(nil),      // 15: trip.InviteToSplitReq
+	(*GetTripReceiptReq)(nil),     // 16: trip.GetTripReceiptReq
+	(*Receipt)(nil),               // 17: trip.Receipt
+	(*ExportTripReceiptRes)(nil),  // 18: trip.ExportTripReceiptRes
+	(*FareBreakdown)(nil),         // 19: trip.FareBreakdown
+	(*ReceiptRoute)(nil),          // 20: trip.ReceiptRoute
+	(*GetTripReq)(nil),            // 21: trip.GetTripReq
+	(*TripRider)(nil),             // 22: trip.TripRider
+	(*TripStop)(nil),              // 23: trip.TripStop
+	(*ReachTripStopReq)(nil),      // 24: trip.ReachTripStopReq
+	(*TripDriver)(nil),            // 25: trip.TripDriver
+	(*CancelTripReq)(nil),         // 26: trip.CancelTripReq
+	(*CancelTripRes)(nil),         // 27: trip.CancelTripRes
+	(*CompleteTripReq)(nil),       // 28: trip.CompleteTripReq
+	(*RateTripReq)(nil),           // 29: trip.RateTripReq
+	(*Rating)(nil),                // 30: trip.Rating
+	(*RatingSummary)(nil),         // 31: trip.RatingSummary
+	(*RateTripRes)(nil),           // 32: trip.RateTripRes
+	(*GetRatingSummaryReq)(nil),   // 33: trip.GetRatingSummaryReq
+	(*timestamppb.Timestamp)(nil), // 34: google.protobuf.Timestamp
 }
 var file_trip_proto_depIdxs = []int32{
 	1,  // 0: trip.PreviewTripReq.startLocation:type_name -> trip.Coordinate
 	1,  // 1: trip.PreviewTripReq.endLocation:type_name -> trip.Coordinate
-	3,  // 2: trip.PreviewTripRes.route:type_name -> trip.Route
-	5,  // 3: trip.PreviewTripRes.rideFares:type_name -> trip.RideFare
-	4,  // 4: trip.Route.geometry:type_name -> trip.Geometry
-	1,  // 5: trip.Geometry.coordinates:type_name -> trip.Coordinate
-	8,  // 6: trip.CreateTripRes.trip:type_name -> trip.Trip
-	5,  // 7: trip.Trip.selectedFare:type_name -> trip.RideFare
-	3,  // 8: trip.Trip.route:type_name -> trip.Route
-	9,  // 9: trip.Trip.driver:type_name -> trip.TripDriver
-	0,  // 10: trip.TripService.PreviewTrip:input_type -> trip.PreviewTripReq
-	6,  // 11: trip.TripService.CreateTrip:input_type -> trip.CreateTripReq
-	2,  // 12: trip.TripService.PreviewTrip:output_type -> trip.PreviewTripRes
-	7,  // 13: trip.TripService.CreateTrip:output_type -> trip.CreateTripRes
-	12, // [12:14] is the sub-list for method output_type
-	10, // [10:12] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	1,  // 2: trip.PreviewTripReq.waypoints:type_name -> trip.Coordinate
+	3,  // 3: trip.PreviewTripRes.route:type_name -> trip.Route
+	6,  // 4: trip.PreviewTripRes.rideFares:type_name -> trip.RideFare
+	5,  // 5: trip.Route.geometry:type_name -> trip.Geometry
+	4,  // 6: trip.Route.legs:type_name -> trip.RouteLeg
+	1,  // 7: trip.Geometry.coordinates:type_name -> trip.Coordinate
+	34, // 8: trip.CreateTripReq.scheduledAt:type_name -> google.protobuf.Timestamp
+	9,  // 9: trip.CreateTripRes.trip:type_name -> trip.Trip
+	6,  // 10: trip.Trip.selectedFare:type_name -> trip.RideFare
+	3,  // 11: trip.Trip.route:type_name -> trip.Route
+	25, // 12: trip.Trip.driver:type_name -> trip.TripDriver
+	1,  // 13: trip.Trip.waypoints:type_name -> trip.Coordinate
+	34, // 14: trip.Trip.scheduledAt:type_name -> google.protobuf.Timestamp
+	22, // 15: trip.Trip.riders:type_name -> trip.TripRider
+	23, // 16: trip.Trip.stopSequence:type_name -> trip.TripStop
+	1,  // 17: trip.Trip.pickup:type_name -> trip.Coordinate
+	1,  // 18: trip.Trip.destination:type_name -> trip.Coordinate
+	13, // 19: trip.Trip.payments:type_name -> trip.TripPayment
+	12, // 20: trip.Trip.finalFare:type_name -> trip.FinalFare
+	10, // 21: trip.Trip.tips:type_name -> trip.TripTip
+	14, // 22: trip.Trip.splitParticipants:type_name -> trip.SplitParticipant
+	34, // 23: trip.TripTip.createdAt:type_name -> google.protobuf.Timestamp
+	34, // 24: trip.Receipt.issuedAt:type_name -> google.protobuf.Timestamp
+	19, // 25: trip.Receipt.fare:type_name -> trip.FareBreakdown
+	20, // 26: trip.Receipt.route:type_name -> trip.ReceiptRoute
+	25, // 27: trip.Receipt.driver:type_name -> trip.TripDriver
+	13, // 28: trip.Receipt.payment:type_name -> trip.TripPayment
+	1,  // 29: trip.ReceiptRoute.pickup:type_name -> trip.Coordinate
+	1,  // 30: trip.ReceiptRoute.destination:type_name -> trip.Coordinate
+	1,  // 31: trip.ReceiptRoute.waypoints:type_name -> trip.Coordinate
+	34, // 32: trip.ReceiptRoute.completedAt:type_name -> google.protobuf.Timestamp
+	6,  // 33: trip.TripRider.fare:type_name -> trip.RideFare
+	1,  // 34: trip.TripStop.location:type_name -> trip.Coordinate
+	9,  // 35: trip.CancelTripRes.trip:type_name -> trip.Trip
+	34, // 36: trip.Rating.createdAt:type_name -> google.protobuf.Timestamp
+	30, // 37: trip.RateTripRes.rating:type_name -> trip.Rating
+	31, // 38: trip.RateTripRes.summary:type_name -> trip.RatingSummary
+	0,  // 39: trip.TripService.PreviewTrip:input_type -> trip.PreviewTripReq
+	7,  // 40: trip.TripService.CreateTrip:input_type -> trip.CreateTripReq
+	24, // 41: trip.TripService.ReachTripStop:input_type -> trip.ReachTripStopReq
+	26, // 42: trip.TripService.CancelTrip:input_type -> trip.CancelTripReq
+	28, // 43: trip.TripService.CompleteTrip:input_type -> trip.CompleteTripReq
+	29, // 44: trip.TripService.RateTrip:input_type -> trip.RateTripReq
+	33, // 45: trip.TripService.GetRatingSummary:input_type -> trip.GetRatingSummaryReq
+	21, // 46: trip.TripService.GetTrip:input_type -> trip.GetTripReq
+	11, // 47: trip.TripService.TipTrip:input_type -> trip.TipTripReq
+	15, // 48: trip.TripService.InviteToSplit:input_type -> trip.InviteToSplitReq
+	16, // 49: trip.TripService.GetTripReceipt:input_type -> trip.GetTripReceiptReq
+	16, // 50: trip.TripService.ExportTripReceipt:input_type -> trip.GetTripReceiptReq
+	2,  // 51: trip.TripService.PreviewTrip:output_type -> trip.PreviewTripRes
+	8,  // 52: trip.TripService.CreateTrip:output_type -> trip.CreateTripRes
+	9,  // 53: trip.TripService.ReachTripStop:output_type -> trip.Trip
+	27, // 54: trip.TripService.CancelTrip:output_type -> trip.CancelTripRes
+	9,  // 55: trip.TripService.CompleteTrip:output_type -> trip.Trip
+	32, // 56: trip.TripService.RateTrip:output_type -> trip.RateTripRes
+	31, // 57: trip.TripService.GetRatingSummary:output_type -> trip.RatingSummary
+	9,  // 58: trip.TripService.GetTrip:output_type -> trip.Trip
+	10, // 59: trip.TripService.TipTrip:output_type -> trip.TripTip
+	9,  // 60: trip.TripService.InviteToSplit:output_type -> trip.Trip
+	17, // 61: trip.TripService.GetTripReceipt:output_type -> trip.Receipt
+	18, // 62: trip.TripService.ExportTripReceipt:output_type -> trip.ExportTripReceiptRes
+	51, // [51:63] is the sub-list for method output_type
+	39, // [39:51] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_trip_proto_init() }
@@ -738,7 +2918,7 @@ func file_trip_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_trip_proto_rawDesc), len(file_trip_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   10,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
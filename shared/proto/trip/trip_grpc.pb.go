@@ -19,8 +19,18 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	TripService_PreviewTrip_FullMethodName = "/trip.TripService/PreviewTrip"
-	TripService_CreateTrip_FullMethodName  = "/trip.TripService/CreateTrip"
+	TripService_PreviewTrip_FullMethodName       = "/trip.TripService/PreviewTrip"
+	TripService_CreateTrip_FullMethodName        = "/trip.TripService/CreateTrip"
+	TripService_ReachTripStop_FullMethodName     = "/trip.TripService/ReachTripStop"
+	TripService_CancelTrip_FullMethodName        = "/trip.TripService/CancelTrip"
+	TripService_CompleteTrip_FullMethodName      = "/trip.TripService/CompleteTrip"
+	TripService_RateTrip_FullMethodName          = "/trip.TripService/RateTrip"
+	TripService_GetRatingSummary_FullMethodName  = "/trip.TripService/GetRatingSummary"
+	TripService_GetTrip_FullMethodName           = "/trip.TripService/GetTrip"
+	TripService_TipTrip_FullMethodName           = "/trip.TripService/TipTrip"
+	TripService_InviteToSplit_FullMethodName     = "/trip.TripService/InviteToSplit"
+	TripService_GetTripReceipt_FullMethodName    = "/trip.TripService/GetTripReceipt"
+	TripService_ExportTripReceipt_FullMethodName = "/trip.TripService/ExportTripReceipt"
 )
 
 // TripServiceClient is the client API for TripService service.
@@ -29,6 +39,16 @@ const (
 type TripServiceClient interface {
 	PreviewTrip(ctx context.Context, in *PreviewTripReq, opts ...grpc.CallOption) (*PreviewTripRes, error)
 	CreateTrip(ctx context.Context, in *CreateTripReq, opts ...grpc.CallOption) (*CreateTripRes, error)
+	ReachTripStop(ctx context.Context, in *ReachTripStopReq, opts ...grpc.CallOption) (*Trip, error)
+	CancelTrip(ctx context.Context, in *CancelTripReq, opts ...grpc.CallOption) (*CancelTripRes, error)
+	CompleteTrip(ctx context.Context, in *CompleteTripReq, opts ...grpc.CallOption) (*Trip, error)
+	RateTrip(ctx context.Context, in *RateTripReq, opts ...grpc.CallOption) (*RateTripRes, error)
+	GetRatingSummary(ctx context.Context, in *GetRatingSummaryReq, opts ...grpc.CallOption) (*RatingSummary, error)
+	GetTrip(ctx context.Context, in *GetTripReq, opts ...grpc.CallOption) (*Trip, error)
+	TipTrip(ctx context.Context, in *TipTripReq, opts ...grpc.CallOption) (*TripTip, error)
+	InviteToSplit(ctx context.Context, in *InviteToSplitReq, opts ...grpc.CallOption) (*Trip, error)
+	GetTripReceipt(ctx context.Context, in *GetTripReceiptReq, opts ...grpc.CallOption) (*Receipt, error)
+	ExportTripReceipt(ctx context.Context, in *GetTripReceiptReq, opts ...grpc.CallOption) (*ExportTripReceiptRes, error)
 }
 
 type tripServiceClient struct {
@@ -59,12 +79,122 @@ func (c *tripServiceClient) CreateTrip(ctx context.Context, in *CreateTripReq, o
 	return out, nil
 }
 
+func (c *tripServiceClient) ReachTripStop(ctx context.Context, in *ReachTripStopReq, opts ...grpc.CallOption) (*Trip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trip)
+	err := c.cc.Invoke(ctx, TripService_ReachTripStop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) CancelTrip(ctx context.Context, in *CancelTripReq, opts ...grpc.CallOption) (*CancelTripRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelTripRes)
+	err := c.cc.Invoke(ctx, TripService_CancelTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) CompleteTrip(ctx context.Context, in *CompleteTripReq, opts ...grpc.CallOption) (*Trip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trip)
+	err := c.cc.Invoke(ctx, TripService_CompleteTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) RateTrip(ctx context.Context, in *RateTripReq, opts ...grpc.CallOption) (*RateTripRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateTripRes)
+	err := c.cc.Invoke(ctx, TripService_RateTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) GetRatingSummary(ctx context.Context, in *GetRatingSummaryReq, opts ...grpc.CallOption) (*RatingSummary, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RatingSummary)
+	err := c.cc.Invoke(ctx, TripService_GetRatingSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) GetTrip(ctx context.Context, in *GetTripReq, opts ...grpc.CallOption) (*Trip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trip)
+	err := c.cc.Invoke(ctx, TripService_GetTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) TipTrip(ctx context.Context, in *TipTripReq, opts ...grpc.CallOption) (*TripTip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TripTip)
+	err := c.cc.Invoke(ctx, TripService_TipTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) InviteToSplit(ctx context.Context, in *InviteToSplitReq, opts ...grpc.CallOption) (*Trip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trip)
+	err := c.cc.Invoke(ctx, TripService_InviteToSplit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) GetTripReceipt(ctx context.Context, in *GetTripReceiptReq, opts ...grpc.CallOption) (*Receipt, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Receipt)
+	err := c.cc.Invoke(ctx, TripService_GetTripReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tripServiceClient) ExportTripReceipt(ctx context.Context, in *GetTripReceiptReq, opts ...grpc.CallOption) (*ExportTripReceiptRes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportTripReceiptRes)
+	err := c.cc.Invoke(ctx, TripService_ExportTripReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TripServiceServer is the server API for TripService service.
 // All implementations must embed UnimplementedTripServiceServer
 // for forward compatibility.
 type TripServiceServer interface {
 	PreviewTrip(context.Context, *PreviewTripReq) (*PreviewTripRes, error)
 	CreateTrip(context.Context, *CreateTripReq) (*CreateTripRes, error)
+	ReachTripStop(context.Context, *ReachTripStopReq) (*Trip, error)
+	CancelTrip(context.Context, *CancelTripReq) (*CancelTripRes, error)
+	CompleteTrip(context.Context, *CompleteTripReq) (*Trip, error)
+	RateTrip(context.Context, *RateTripReq) (*RateTripRes, error)
+	GetRatingSummary(context.Context, *GetRatingSummaryReq) (*RatingSummary, error)
+	GetTrip(context.Context, *GetTripReq) (*Trip, error)
+	TipTrip(context.Context, *TipTripReq) (*TripTip, error)
+	InviteToSplit(context.Context, *InviteToSplitReq) (*Trip, error)
+	GetTripReceipt(context.Context, *GetTripReceiptReq) (*Receipt, error)
+	ExportTripReceipt(context.Context, *GetTripReceiptReq) (*ExportTripReceiptRes, error)
 	mustEmbedUnimplementedTripServiceServer()
 }
 
@@ -81,6 +211,36 @@ func (UnimplementedTripServiceServer) PreviewTrip(context.Context, *PreviewTripR
 func (UnimplementedTripServiceServer) CreateTrip(context.Context, *CreateTripReq) (*CreateTripRes, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateTrip not implemented")
 }
+func (UnimplementedTripServiceServer) ReachTripStop(context.Context, *ReachTripStopReq) (*Trip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReachTripStop not implemented")
+}
+func (UnimplementedTripServiceServer) CancelTrip(context.Context, *CancelTripReq) (*CancelTripRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTrip not implemented")
+}
+func (UnimplementedTripServiceServer) CompleteTrip(context.Context, *CompleteTripReq) (*Trip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteTrip not implemented")
+}
+func (UnimplementedTripServiceServer) RateTrip(context.Context, *RateTripReq) (*RateTripRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RateTrip not implemented")
+}
+func (UnimplementedTripServiceServer) GetRatingSummary(context.Context, *GetRatingSummaryReq) (*RatingSummary, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRatingSummary not implemented")
+}
+func (UnimplementedTripServiceServer) GetTrip(context.Context, *GetTripReq) (*Trip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrip not implemented")
+}
+func (UnimplementedTripServiceServer) TipTrip(context.Context, *TipTripReq) (*TripTip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TipTrip not implemented")
+}
+func (UnimplementedTripServiceServer) InviteToSplit(context.Context, *InviteToSplitReq) (*Trip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteToSplit not implemented")
+}
+func (UnimplementedTripServiceServer) GetTripReceipt(context.Context, *GetTripReceiptReq) (*Receipt, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTripReceipt not implemented")
+}
+func (UnimplementedTripServiceServer) ExportTripReceipt(context.Context, *GetTripReceiptReq) (*ExportTripReceiptRes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportTripReceipt not implemented")
+}
 func (UnimplementedTripServiceServer) mustEmbedUnimplementedTripServiceServer() {}
 func (UnimplementedTripServiceServer) testEmbeddedByValue()                     {}
 
@@ -138,6 +298,186 @@ func _TripService_CreateTrip_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TripService_ReachTripStop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReachTripStopReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).ReachTripStop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_ReachTripStop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).ReachTripStop(ctx, req.(*ReachTripStopReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_CancelTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTripReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).CancelTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_CancelTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).CancelTrip(ctx, req.(*CancelTripReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_CompleteTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteTripReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).CompleteTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_CompleteTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).CompleteTrip(ctx, req.(*CompleteTripReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_RateTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateTripReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).RateTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_RateTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).RateTrip(ctx, req.(*RateTripReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_GetRatingSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRatingSummaryReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).GetRatingSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_GetRatingSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).GetRatingSummary(ctx, req.(*GetRatingSummaryReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_GetTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).GetTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_GetTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).GetTrip(ctx, req.(*GetTripReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_TipTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TipTripReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).TipTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_TipTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).TipTrip(ctx, req.(*TipTripReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_InviteToSplit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteToSplitReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).InviteToSplit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_InviteToSplit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).InviteToSplit(ctx, req.(*InviteToSplitReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_GetTripReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripReceiptReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).GetTripReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_GetTripReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).GetTripReceipt(ctx, req.(*GetTripReceiptReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TripService_ExportTripReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripReceiptReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TripServiceServer).ExportTripReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TripService_ExportTripReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TripServiceServer).ExportTripReceipt(ctx, req.(*GetTripReceiptReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TripService_ServiceDesc is the grpc.ServiceDesc for TripService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +493,46 @@ var TripService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateTrip",
 			Handler:    _TripService_CreateTrip_Handler,
 		},
+		{
+			MethodName: "ReachTripStop",
+			Handler:    _TripService_ReachTripStop_Handler,
+		},
+		{
+			MethodName: "CancelTrip",
+			Handler:    _TripService_CancelTrip_Handler,
+		},
+		{
+			MethodName: "CompleteTrip",
+			Handler:    _TripService_CompleteTrip_Handler,
+		},
+		{
+			MethodName: "RateTrip",
+			Handler:    _TripService_RateTrip_Handler,
+		},
+		{
+			MethodName: "GetRatingSummary",
+			Handler:    _TripService_GetRatingSummary_Handler,
+		},
+		{
+			MethodName: "GetTrip",
+			Handler:    _TripService_GetTrip_Handler,
+		},
+		{
+			MethodName: "TipTrip",
+			Handler:    _TripService_TipTrip_Handler,
+		},
+		{
+			MethodName: "InviteToSplit",
+			Handler:    _TripService_InviteToSplit_Handler,
+		},
+		{
+			MethodName: "GetTripReceipt",
+			Handler:    _TripService_GetTripReceipt_Handler,
+		},
+		{
+			MethodName: "ExportTripReceipt",
+			Handler:    _TripService_ExportTripReceipt_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "trip.proto",